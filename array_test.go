@@ -0,0 +1,85 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type arrayPoint struct {
+	X, Y int
+}
+
+func TestMap_Array(t *testing.T) {
+	type Shape struct {
+		Points [2]arrayPoint
+		Named  map[string][1]arrayPoint
+		IP     [4]byte
+	}
+
+	s := Shape{
+		Points: [2]arrayPoint{{1, 2}, {3, 4}},
+		Named:  map[string][1]arrayPoint{"a": {{5, 6}}},
+		IP:     [4]byte{127, 0, 0, 1},
+	}
+
+	m := Map(s)
+
+	points := []interface{}{
+		map[string]interface{}{"X": 1, "Y": 2},
+		map[string]interface{}{"X": 3, "Y": 4},
+	}
+
+	if !reflect.DeepEqual(m["Points"], points) {
+		t.Errorf("Map should convert arrays of structs like slices, got: %#v", m["Points"])
+	}
+
+	named := map[string]interface{}{
+		"a": []interface{}{map[string]interface{}{"X": 5, "Y": 6}},
+	}
+
+	if !reflect.DeepEqual(m["Named"], named) {
+		t.Errorf("Map should convert maps of arrays of structs, got: %#v", m["Named"])
+	}
+
+	if m["IP"] != s.IP {
+		t.Errorf("Map should keep arrays of non structs as is, got: %#v", m["IP"])
+	}
+
+	maps := MapSlice([2]arrayPoint{{1, 2}, {3, 4}})
+	if len(maps) != 2 || maps[1]["X"] != 3 {
+		t.Errorf("MapSlice should accept arrays, got: %v", maps)
+	}
+}
+
+func TestDecode_Array(t *testing.T) {
+	type Shape struct {
+		Points [2]arrayPoint
+		IP     [4]byte
+	}
+
+	s := Shape{
+		Points: [2]arrayPoint{{1, 2}, {3, 4}},
+		IP:     [4]byte{127, 0, 0, 1},
+	}
+
+	var fromDynamo Shape
+	if err := FromDynamoDB(DynamoDB(s), &fromDynamo); err != nil {
+		t.Fatal(err)
+	}
+
+	if fromDynamo != s {
+		t.Errorf("FromDynamoDB should fill arrays, got: %+v", fromDynamo)
+	}
+
+	pairs := RedisHash(s)
+	hash := map[string]string{pairs[0]: pairs[1], pairs[2]: pairs[3]}
+
+	var fromRedis Shape
+	if err := FromRedisHash(hash, &fromRedis); err != nil {
+		t.Fatal(err)
+	}
+
+	if fromRedis != s {
+		t.Errorf("FromRedisHash should fill arrays, got: %+v", fromRedis)
+	}
+}
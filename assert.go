@@ -0,0 +1,123 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TestingT is the part of testing.TB used by the test helpers, such as
+// AssertEqual. It's satisfied by *testing.T and *testing.B.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertEqual compares the structs want and got field by field as in Diff,
+// ignoring the fields at the given paths, and reports the differences to t
+// as a single error:
+//
+//   structs.AssertEqual(t, want, got, "ID", "CreatedAt", "Owner.ID")
+//
+// which reports the differing fields instead of two dumps of the structs:
+//
+//   structs: User differs (-want +got):
+//     Name:
+//       - "Ann"
+//       + "Bob"
+//     Address.City:
+//       - "Oslo"
+//       + "Bergen"
+//
+// A path ignores the field and, for nested structs, all of its fields. A
+// "*" matches any field name, so "*.UpdatedAt" ignores the UpdatedAt fields
+// of all structs nested one level deep. It reports an error if want and got
+// are not of the same struct type. It returns true if the structs are equal.
+func AssertEqual(t TestingT, want, got interface{}, ignore ...string) bool {
+	t.Helper()
+
+	w, err := structVal(want)
+	if err != nil {
+		t.Errorf("structs: want: %v", err)
+		return false
+	}
+
+	g, err := structVal(got)
+	if err != nil {
+		t.Errorf("structs: got: %v", err)
+		return false
+	}
+
+	if w.Type() != g.Type() {
+		t.Errorf("structs: %v: want %s, got %s", ErrTypeMismatch, w.Type(), g.Type())
+		return false
+	}
+
+	var b strings.Builder
+	for _, c := range New(want).Diff(got) {
+		if ignored(c.Path, ignore) {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n  %s:\n    - %s\n    + %s", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+	}
+
+	if b.Len() == 0 {
+		return true
+	}
+
+	name := w.Type().Name()
+	if name == "" {
+		name = "struct"
+	}
+
+	t.Errorf("structs: %s differs (-want +got):%s", name, b.String())
+	return false
+}
+
+// ignored reports whether the field at path is below or at one of the
+// ignored paths.
+func ignored(path string, ignore []string) bool {
+	segments := strings.Split(path, ".")
+
+outer:
+	for _, p := range ignore {
+		pattern := strings.Split(p, ".")
+		if len(pattern) > len(segments) {
+			continue
+		}
+
+		for i, seg := range pattern {
+			if seg != "*" && seg != segments[i] {
+				continue outer
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// formatDiffValue formats the value v of a Change for AssertEqual. Strings
+// are quoted and nil pointers are shown as nil.
+func formatDiffValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+
+	switch {
+	case !rv.IsValid():
+		return "nil"
+	case rv.Kind() == reflect.Ptr && rv.IsNil():
+		return "nil"
+	case rv.Kind() == reflect.Ptr:
+		return "&" + formatDiffValue(rv.Elem().Interface())
+	case rv.Kind() == reflect.String:
+		return fmt.Sprintf("%q", v)
+	}
+
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%+v", v)
+}
@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// recorder is a TestingT which records the reported errors.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type assertAddress struct {
+	ID   int
+	City string
+}
+
+type assertUser struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+	Age       *int
+	Address   assertAddress
+	Billing   *assertAddress
+}
+
+func TestAssertEqual(t *testing.T) {
+	age := 30
+
+	want := assertUser{ID: 1, Name: "Ann", Address: assertAddress{ID: 1, City: "Oslo"}}
+	got := assertUser{
+		ID:        2,
+		Name:      "Bob",
+		CreatedAt: time.Now(),
+		Age:       &age,
+		Address:   assertAddress{ID: 2, City: "Bergen"},
+		Billing:   &assertAddress{City: "Oslo"},
+	}
+
+	r := &recorder{}
+	if AssertEqual(r, want, &got, "ID", "CreatedAt", "*.ID") {
+		t.Error("expected false")
+	}
+
+	wantErr := `structs: assertUser differs (-want +got):
+  Name:
+    - "Ann"
+    + "Bob"
+  Age:
+    - nil
+    + &30
+  Address.City:
+    - "Oslo"
+    + "Bergen"
+  Billing:
+    - nil
+    + &{ID:0 City:Oslo}`
+
+	if len(r.errors) != 1 || r.errors[0] != wantErr {
+		t.Errorf("got %q\nwant %q", r.errors, wantErr)
+	}
+
+	r = &recorder{}
+	if !AssertEqual(r, want, got, "ID", "Name", "CreatedAt", "Age", "Address", "Billing") || len(r.errors) != 0 {
+		t.Errorf("expected no errors, got %q", r.errors)
+	}
+}
+
+func TestAssertEqual_TypeMismatch(t *testing.T) {
+	r := &recorder{}
+	if AssertEqual(r, assertUser{}, assertAddress{}) {
+		t.Error("expected false")
+	}
+
+	if AssertEqual(r, assertUser{}, 42) {
+		t.Error("expected false")
+	}
+
+	if len(r.errors) != 2 {
+		t.Errorf("got %q", r.errors)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		path   string
+		ignore []string
+		want   bool
+	}{
+		{"ID", []string{"ID"}, true},
+		{"Owner.ID", []string{"Owner"}, true},
+		{"Owner.ID", []string{"ID"}, false},
+		{"Owner.ID", []string{"*.ID"}, true},
+		{"IDs", []string{"ID"}, false},
+		{"Owner", []string{"Owner.ID"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := ignored(tt.path, tt.ignore); got != tt.want {
+			t.Errorf("%s %q: got %t want %t", tt.path, tt.ignore, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var (
+	// QueryTagName is the tag name which gives the name of the query
+	// parameter of a field, such as `query:"page"`, for BindRequest.
+	QueryTagName = "query"
+
+	// FormTagName is the tag name which gives the name of the form value
+	// of a field, such as `form:"email"`, for BindRequest.
+	FormTagName = "form"
+
+	// MaxBodyBytes limits the size of a JSON body read by BindRequest.
+	MaxBodyBytes int64 = 10 << 20
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// BindRequest sets the fields of s from the given request. Each field is
+// bound from the source selected by its tags:
+//
+//   type SearchRequest struct {
+//       // from the query string, such as ?page=2
+//       Page int `query:"page"`
+//
+//       // from repeated parameters, such as ?tag=a&tag=b
+//       Tags []string `query:"tag"`
+//
+//       // from a url-encoded or multipart form body
+//       Email string `form:"email"`
+//
+//       // from the files uploaded with a multipart form
+//       Avatar      *multipart.FileHeader   `form:"avatar"`
+//       Attachments []*multipart.FileHeader `form:"attachment"`
+//
+//       // from a JSON body, as encoding/json decodes it
+//       Filter Filter `json:"filter"`
+//
+//       // from the path values of http.ServeMux, such as /users/{id}
+//       UserID int `path:"id"`
+//   }
+//
+// A JSON body, with a Content-Type of application/json or a type ending in
+// +json, is decoded with encoding/json first. Only the fields with a json
+// tag or without a query, form or path tag are set from it, so a field such
+// as `query:"admin"` can't be set by the body. Then the fields with a form
+// tag are set from the form body, the fields with a query tag from the query
+// string and the fields with a path tag from the non-empty path values, so
+// they take precedence. Values are parsed as in LoadEnv, except that slices
+// are set from repeated parameters. Fields of type *multipart.FileHeader are
+// set from the first uploaded file with their form name and fields of type
+// []*multipart.FileHeader from all of them. Fields without a value in the
+// request are left as is. Nested structs with a query or form tag are bound
+// with their name followed by a dot as a prefix, such as ?filter.name=x, and
+// nested structs without one are bound without a prefix. Path values of
+// routers other than http.ServeMux can be bound with BindPath.
+//
+// It returns an error if s was not created from a pointer or if the body
+// can't be read or parsed, or is larger than MaxBodyBytes. Values which can't
+// be set are reported with a FieldErrors, whose Got describes the source of
+// the value, such as `query "page"`.
+func (s *Struct) BindRequest(r *http.Request) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	var errs FieldErrors
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch {
+	case r.Body != nil && r.Body != http.NoBody && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")):
+		// the body is decoded into a copy, so the fields which must not be
+		// set from it are left as is
+		body := (&cloner{seen: make(map[visit]reflect.Value)}).clone(s.value.Addr())
+		err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, MaxBodyBytes)).Decode(body.Interface())
+		bindBody(s.value, body.Elem())
+
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &typeErr):
+			errs = append(errs, &FieldError{
+				Path: typeErr.Field,
+				Type: typeErr.Type,
+				Got:  "body " + typeErr.Value,
+				Err:  fmt.Errorf("%w: can't set %s into %s", ErrTypeMismatch, typeErr.Value, typeErr.Type),
+			})
+		case err != nil && err != io.EOF:
+			return fmt.Errorf("decoding body: %w", err)
+		}
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return fmt.Errorf("parsing form: %w", err)
+		}
+
+		s.bindValues(FormTagName, multipartValuesOf(r.MultipartForm), "", &errs)
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("parsing form: %w", err)
+		}
+
+		s.bindValues(FormTagName, valuesOf(r.PostForm), "", &errs)
+	}
+
+	s.bindValues(QueryTagName, valuesOf(r.URL.Query()), "", &errs)
+	s.bindValues(PathTagName, pathValuesOf(r), "", &errs)
+
+	return errs.err()
+}
+
+// bindBody sets the fields of dst which may be set from a JSON body to the
+// ones of src, which the body was decoded into. Nested structs are set field
+// by field.
+func bindBody(dst, src reflect.Value) {
+	t := dst.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || !bodyField(field) {
+			continue
+		}
+
+		dv, sv := dst.Field(i), src.Field(i)
+		if reflect.DeepEqual(dv.Interface(), sv.Interface()) {
+			continue
+		}
+
+		if !envNested(field.Type) {
+			dv.Set(sv)
+			continue
+		}
+
+		if dv.Kind() == reflect.Ptr {
+			if sv.IsNil() {
+				dv.Set(sv)
+				continue
+			}
+
+			if dv.IsNil() {
+				dv.Set(reflect.New(dv.Type().Elem()))
+			}
+
+			dv, sv = dv.Elem(), sv.Elem()
+		}
+
+		bindBody(dv, sv)
+	}
+}
+
+// bodyField returns true if the given field may be set from a JSON body,
+// which is the case if it has a json tag or isn't bound from another source.
+func bodyField(field reflect.StructField) bool {
+	if _, ok := field.Tag.Lookup("json"); ok {
+		return true
+	}
+
+	for _, tagName := range []string{QueryTagName, FormTagName, PathTagName} {
+		if _, ok := field.Tag.Lookup(tagName); ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// values are the named values of a request, such as its query parameters.
+type values struct {
+	// get returns the values with the given name.
+	get func(name string) []string
+
+	// hasPrefix reports whether there are values whose name starts with
+	// prefix.
+	hasPrefix func(prefix string) bool
+
+	// files returns the uploaded files with the given name. It's nil if
+	// the values can't have files.
+	files func(name string) []*multipart.FileHeader
+}
+
+// valuesOf returns the values of m.
+func valuesOf(m map[string][]string) values {
+	return values{
+		get: func(name string) []string {
+			return m[name]
+		},
+		hasPrefix: func(prefix string) bool {
+			for name := range m {
+				if strings.HasPrefix(name, prefix) {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// multipartValuesOf returns the values and uploaded files of the multipart
+// form f.
+func multipartValuesOf(f *multipart.Form) values {
+	vals := valuesOf(f.Value)
+
+	hasValue := vals.hasPrefix
+	vals.hasPrefix = func(prefix string) bool {
+		if hasValue(prefix) {
+			return true
+		}
+
+		for name := range f.File {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	vals.files = func(name string) []*multipart.FileHeader {
+		return f.File[name]
+	}
+
+	return vals
+}
+
+// pathValuesOf returns the path values of r, which are set by the
+// http.ServeMux. Path values can't be enumerated, so nil pointers to nested
+// structs are never allocated.
+func pathValuesOf(r *http.Request) values {
+	return values{
+		get: func(name string) []string {
+			if v := r.PathValue(name); v != "" {
+				return []string{v}
+			}
+
+			return nil
+		},
+		hasPrefix: func(string) bool {
+			return false
+		},
+	}
+}
+
+// bindValues sets the fields of s with the given tag from vals, where the
+// names of the values start with prefix.
+func (s *Struct) bindValues(tagName string, vals values, prefix string, errs *FieldErrors) {
+	for _, field := range s.structFields() {
+		tag, tagged := field.Tag.Lookup(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, _ := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+
+		v := s.value.FieldByName(field.Name)
+
+		if field.Type == fileHeaderType || field.Type == fileHeadersType {
+			if tagged && vals.files != nil {
+				bindFiles(v, vals.files(prefix+name))
+			}
+			continue
+		}
+
+		if envNested(field.Type) {
+			nestedPrefix := prefix
+			if tagged {
+				nestedPrefix += name + "."
+			}
+
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					if !vals.hasPrefix(nestedPrefix) {
+						continue
+					}
+
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+
+				v = v.Elem()
+			}
+
+			var nestedErrs FieldErrors
+			s.nestedStruct(v.Addr().Interface()).bindValues(tagName, vals, nestedPrefix, &nestedErrs)
+
+			if len(nestedErrs) > 0 {
+				errs.add(field.Name, field.Type, tagName, nestedErrs)
+			}
+			continue
+		}
+
+		if !tagged {
+			continue
+		}
+
+		got := vals.get(prefix + name)
+		if len(got) == 0 {
+			continue
+		}
+
+		if err := parseValues(v, got); err != nil {
+			errs.add(field.Name, field.Type, fmt.Sprintf("%s %q", tagName, prefix+name), err)
+		}
+	}
+}
+
+// bindFiles sets the file field v to the given files, unless there are none.
+func bindFiles(v reflect.Value, files []*multipart.FileHeader) {
+	switch {
+	case len(files) == 0:
+	case v.Kind() == reflect.Slice:
+		v.Set(reflect.ValueOf(files))
+	default:
+		v.Set(reflect.ValueOf(files[0]))
+	}
+}
+
+// parseValues parses the given values into v. Slices are set from all
+// values, other types from the first one.
+func parseValues(v reflect.Value, vals []string) error {
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 ||
+		reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return parseEnv(v, vals[0])
+	}
+
+	slice := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+	for i, val := range vals {
+		if err := parseEnv(slice.Index(i), val); err != nil {
+			return fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+
+	v.Set(slice)
+	return nil
+}
+
+// BindRequest sets the fields of the struct pointed to by dst from the given
+// request. For more info refer to Struct types BindRequest() method. It
+// returns an error if dst is not a pointer to struct.
+func BindRequest(r *http.Request, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.BindRequest(r)
+}
+
+// BindPath sets the fields of s with a path tag from the given route
+// variables, such as the ones of chi or gorilla/mux routers:
+//
+//   type GetOrder struct {
+//       UserID  int       `path:"user_id"`
+//       OrderID uuid.UUID `path:"id"`
+//   }
+//
+//   err := structs.BindPath(mux.Vars(r), &req)
+//
+// Values are parsed as in BindRequest and variables without a field are
+// ignored. It returns an error if s was not created from a pointer. Values
+// which can't be set are reported with a FieldErrors.
+func (s *Struct) BindPath(vars map[string]string) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	m := make(map[string][]string, len(vars))
+	for k, v := range vars {
+		m[k] = []string{v}
+	}
+
+	var errs FieldErrors
+	s.bindValues(PathTagName, valuesOf(m), "", &errs)
+
+	return errs.err()
+}
+
+// BindPath sets the fields of the struct pointed to by dst from the given
+// route variables. For more info refer to Struct types BindPath() method. It
+// returns an error if dst is not a pointer to struct.
+func BindPath(vars map[string]string, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.BindPath(vars)
+}
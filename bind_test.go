@@ -0,0 +1,285 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindFilter struct {
+	Name  string `query:"name" json:"name"`
+	Since time.Time
+}
+
+type bindPage struct {
+	Size int `query:"size"`
+}
+
+type bindRequest struct {
+	Page   int        `query:"page"`
+	Tags   []string   `query:"tag"`
+	Email  string     `form:"email"`
+	Age    int        `form:"age" json:"age"`
+	Filter bindFilter `query:"filter" json:"filter"`
+	Paging bindPage
+	Cursor *bindPage `query:"cursor"`
+	Skip   string    `query:"-"`
+}
+
+func TestBindRequest_Query(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?page=2&tag=a&tag=b,c&filter.name=x&size=10&skip=x&email=e", nil)
+
+	var b bindRequest
+	if err := BindRequest(r, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := bindRequest{
+		Page:   2,
+		Tags:   []string{"a", "b,c"},
+		Filter: bindFilter{Name: "x"},
+		Paging: bindPage{Size: 10},
+	}
+
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("got %#v want %#v", b, want)
+	}
+}
+
+func TestBindRequest_JSON(t *testing.T) {
+	body := `{"age": 30, "filter": {"name": "body", "Since": "2024-01-02T00:00:00Z"}}`
+	r := httptest.NewRequest("POST", "/?filter.name=query&cursor.size=5", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	var b bindRequest
+	if err := New(&b).BindRequest(r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := bindRequest{
+		Age:    30,
+		Filter: bindFilter{Name: "query", Since: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Cursor: &bindPage{Size: 5},
+	}
+
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("got %#v want %#v", b, want)
+	}
+}
+
+func TestBindRequest_JSONTaggedFields(t *testing.T) {
+	type search struct {
+		Page    int  `query:"page"`
+		IsAdmin bool `query:"admin"`
+		Limit   int
+		Nested  struct {
+			Owner string `path:"owner"`
+			Name  string
+		}
+	}
+
+	body := `{"IsAdmin": true, "Limit": 5, "Nested": {"Owner": "root", "Name": "x"}}`
+	r := httptest.NewRequest("POST", "/?page=2", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	var b search
+	b.Nested.Owner = "alice"
+	if err := New(&b).BindRequest(r); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.IsAdmin || b.Nested.Owner != "alice" {
+		t.Errorf("fields tagged with query or path were set from the body: %+v", b)
+	}
+
+	if b.Page != 2 || b.Limit != 5 || b.Nested.Name != "x" {
+		t.Errorf("got %+v", b)
+	}
+
+	MaxBodyBytes = 8
+	defer func() { MaxBodyBytes = 10 << 20 }()
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	if err := New(&b).BindRequest(r); err == nil {
+		t.Error("expected an error for a body larger than MaxBodyBytes")
+	}
+}
+
+func TestBindRequest_Form(t *testing.T) {
+	r := httptest.NewRequest("POST", "/?page=1", strings.NewReader("email=a%40b.c&age=30&page=2"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var b bindRequest
+	if err := BindRequest(r, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Email != "a@b.c" || b.Age != 30 || b.Page != 1 {
+		t.Errorf("got %#v", b)
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("email", "m@b.c")
+	w.Close()
+
+	r = httptest.NewRequest("POST", "/", &buf)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+
+	b = bindRequest{}
+	if err := BindRequest(r, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Email != "m@b.c" {
+		t.Errorf("multipart: got %#v", b)
+	}
+}
+
+type bindUpload struct {
+	Title       string                  `form:"title"`
+	Avatar      *multipart.FileHeader   `form:"avatar"`
+	Attachments []*multipart.FileHeader `form:"attachment"`
+	Missing     *multipart.FileHeader   `form:"missing"`
+	Untagged    *multipart.FileHeader
+}
+
+func TestBindRequest_Files(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("title", "report")
+
+	for _, f := range []struct{ field, name string }{
+		{"avatar", "me.png"},
+		{"attachment", "a.txt"},
+		{"attachment", "b.txt"},
+		{"Untagged", "c.txt"},
+	} {
+		fw, err := w.CreateFormFile(f.field, f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fw.Write([]byte("data"))
+	}
+	w.Close()
+
+	r := httptest.NewRequest("POST", "/", &buf)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+
+	var u bindUpload
+	if err := BindRequest(r, &u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Title != "report" {
+		t.Errorf("title: got %q", u.Title)
+	}
+
+	if u.Avatar == nil || u.Avatar.Filename != "me.png" || u.Avatar.Size != 4 {
+		t.Errorf("avatar: got %#v", u.Avatar)
+	}
+
+	var names []string
+	for _, f := range u.Attachments {
+		names = append(names, f.Filename)
+	}
+
+	if want := []string{"a.txt", "b.txt"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("attachments: got %q want %q", names, want)
+	}
+
+	if u.Missing != nil || u.Untagged != nil {
+		t.Errorf("got %#v and %#v, expected nil", u.Missing, u.Untagged)
+	}
+
+	// file fields are left as is without a multipart form
+	r = httptest.NewRequest("GET", "/?avatar=x", nil)
+
+	u = bindUpload{}
+	if err := BindRequest(r, &u); err != nil || u.Avatar != nil {
+		t.Errorf("query: got %#v, %v", u.Avatar, err)
+	}
+}
+
+func TestBindRequest_Errors(t *testing.T) {
+	r := httptest.NewRequest("POST", "/?page=x&tag=a&filter.name=ok&size=y", strings.NewReader(`{"age": "old"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var b bindRequest
+	err := BindRequest(r, &b)
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected FieldErrors, got %v", err)
+	}
+
+	var got []string
+	for _, e := range fieldErrs {
+		got = append(got, e.Path+" "+e.Got)
+	}
+
+	want := []string{"age body string", `Page query "page"`, `Paging.Size query "size"`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{`))
+	r.Header.Set("Content-Type", "application/json")
+
+	if err := BindRequest(r, &b); err == nil || errors.As(err, &fieldErrs) {
+		t.Errorf("expected a body error, got %v", err)
+	}
+
+	if err := BindRequest(r, b); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
+
+type bindOrder struct {
+	UserID  int    `path:"user_id"`
+	OrderID string `path:"id"`
+	Page    int    `query:"page"`
+}
+
+func TestBindPath(t *testing.T) {
+	var o bindOrder
+	err := BindPath(map[string]string{"user_id": "42", "id": "a/b", "page": "2", "other": "x"}, &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (bindOrder{UserID: 42, OrderID: "a/b"}); o != want {
+		t.Errorf("got %#v want %#v", o, want)
+	}
+
+	err = New(&o).BindPath(map[string]string{"user_id": "me"})
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) || fieldErrs[0].Got != `path "user_id"` {
+		t.Errorf("expected an error for user_id, got %v", err)
+	}
+}
+
+func TestBindRequest_PathValues(t *testing.T) {
+	// as set by http.ServeMux for the pattern /users/{user_id}/orders/{id}
+	r := httptest.NewRequest("GET", "/users/42/orders/7?page=3", nil)
+	r.SetPathValue("user_id", "42")
+	r.SetPathValue("id", "7")
+
+	var o bindOrder
+	if err := BindRequest(r, &o); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (bindOrder{UserID: 42, OrderID: "7", Page: 3}); o != want {
+		t.Errorf("got %#v want %#v", o, want)
+	}
+}
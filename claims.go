@@ -0,0 +1,182 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var (
+	// ClaimsTagName is the tag name which gives the name of the claim a
+	// field is decoded from by DecodeClaims, such as `json:"exp"`. Claims
+	// structs are usually marshaled with encoding/json too, so the json tag
+	// is used by default.
+	ClaimsTagName = "json"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	float64Type = reflect.TypeOf(float64(0))
+)
+
+// DecodeClaims sets the fields of s from the claims of a JWT, such as the
+// map[string]interface{} returned by the parser of a JWT library. Claims are
+// matched with the name given in the field's json tag, or the field's name,
+// and fields tagged with "-" are ignored. Values are assigned as in Fill,
+// with the following additions:
+//
+//   - numeric dates, the seconds since the Unix epoch used by "exp", "iat"
+//     and "nbf", set time.Time fields in UTC, including fractional seconds
+//   - a single string sets a string slice field, as the "aud" claim may be
+//     either a string or a list of strings
+//   - nested claims, such as the map under "realm_access", fill nested
+//     structs and are checked for unknown claims too
+//   - embedded structs without a name in their tag, such as the registered
+//     claims of a JWT library, and fields tagged with "flatten" are filled
+//     from the same claims
+//
+// It returns the sorted, dotted paths of the claims without a matching
+// field, such as "realm_access.groups", so callers can reject or log
+// unexpected claims. It returns an error if s was not created from a
+// pointer. If claims can't be assigned to their fields, all of them are
+// reported with a FieldErrors.
+func (s *Struct) DecodeClaims(claims map[string]interface{}) ([]string, error) {
+	if !s.value.CanAddr() {
+		return nil, errNotStructPtr
+	}
+
+	var unknown []string
+	errs := s.decodeClaims(claims, "", &unknown)
+
+	sort.Strings(unknown)
+	return unknown, errs.err()
+}
+
+// decodeClaims sets the fields of s from claims and appends the paths of the
+// claims without a matching field, prefixed by prefix, to unknown.
+func (s *Struct) decodeClaims(claims map[string]interface{}, prefix string, unknown *[]string) FieldErrors {
+	var errs FieldErrors
+
+	used := make(map[string]bool)
+	s.claimFields(claims, used, prefix, unknown, &errs)
+
+	for name := range claims {
+		if !used[name] {
+			*unknown = append(*unknown, prefix+name)
+		}
+	}
+
+	return errs
+}
+
+// claimFields sets the fields of s from claims and marks the claims it used
+// in used. Flattened structs share the claims and used of s.
+func (s *Struct) claimFields(claims map[string]interface{}, used map[string]bool, prefix string, unknown *[]string, errs *FieldErrors) {
+	for _, field := range s.structFields() {
+		tag := field.Tag.Get(ClaimsTagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, tagOpts := s.fieldLayout().key(field, ClaimsTagName)
+		v := s.value.FieldByName(field.Name)
+
+		tagName, _ := parseTag(tag)
+		if tagOpts.Has("flatten") || (field.Anonymous && tagName == "" && envNested(field.Type)) {
+			var nestedErrs FieldErrors
+			s.flattenClaims(v, claims, used, prefix, unknown, &nestedErrs)
+
+			if len(nestedErrs) > 0 {
+				errs.add(field.Name, field.Type, "map[string]interface {}", nestedErrs)
+			}
+			continue
+		}
+
+		val, ok := claims[name]
+		if !ok {
+			continue
+		}
+
+		used[name] = true
+
+		if err := s.assignClaim(v, val, prefix+name+".", unknown); err != nil {
+			errs.add(field.Name, field.Type, fmt.Sprintf("%T", val), err)
+		}
+	}
+}
+
+// flattenClaims sets the fields of the flattened struct v from claims. A
+// nil pointer is only allocated if any of its fields is set.
+func (s *Struct) flattenClaims(v reflect.Value, claims map[string]interface{}, used map[string]bool, prefix string, unknown *[]string, errs *FieldErrors) {
+	if v.Kind() != reflect.Ptr {
+		s.nestedStruct(v.Addr().Interface()).claimFields(claims, used, prefix, unknown, errs)
+		return
+	}
+
+	elem := v
+	if v.IsNil() {
+		elem = reflect.New(v.Type().Elem())
+	}
+
+	n := len(used)
+	s.nestedStruct(elem.Interface()).claimFields(claims, used, prefix, unknown, errs)
+
+	if v.IsNil() && len(used) > n {
+		v.Set(elem)
+	}
+}
+
+// assignClaim sets v to the claim val as described in DecodeClaims. The
+// unknown claims of nested structs are prefixed by prefix.
+func (s *Struct) assignClaim(v reflect.Value, val interface{}, prefix string, unknown *[]string) error {
+	if val == nil {
+		return s.assign(v, val)
+	}
+
+	t := indirectType(v.Type())
+	given := reflect.ValueOf(val)
+
+	switch {
+	case t == timeType && isNumber(given):
+		f, err := convertNumber(given, float64Type)
+		if err != nil {
+			return err
+		}
+
+		sec, frac := math.Modf(f.Float())
+		val = time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC()
+	case envNested(t):
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(t))
+			}
+
+			v = v.Elem()
+		}
+
+		return s.nestedStruct(v.Addr().Interface()).decodeClaims(m, prefix, unknown).err()
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String && given.Kind() == reflect.String:
+		val = []interface{}{val}
+	}
+
+	return s.assign(v, val)
+}
+
+// DecodeClaims sets the fields of the struct pointed to by dst from the
+// claims of a JWT. For more info refer to Struct types DecodeClaims()
+// method. It returns an error if dst is not a pointer to struct.
+func DecodeClaims(claims map[string]interface{}, dst interface{}) ([]string, error) {
+	s, err := structPtr(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DecodeClaims(claims)
+}
@@ -0,0 +1,163 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type registeredClaims struct {
+	Issuer    string     `json:"iss,omitempty"`
+	Subject   string     `json:"sub,omitempty"`
+	Audience  []string   `json:"aud,omitempty"`
+	ExpiresAt time.Time  `json:"exp,omitempty"`
+	IssuedAt  *time.Time `json:"iat,omitempty"`
+}
+
+type realmAccess struct {
+	Roles []string `json:"roles"`
+}
+
+type tokenClaims struct {
+	registeredClaims
+	Email  string       `json:"email"`
+	Realm  *realmAccess `json:"realm_access"`
+	Scopes []string     `json:"scope"`
+	Secret string       `json:"-"`
+}
+
+type exportedClaims struct {
+	*Registered
+	Email string `json:"email"`
+}
+
+type Registered struct {
+	Subject string `json:"sub"`
+}
+
+func TestDecodeClaims(t *testing.T) {
+	var c tokenClaims
+
+	var claims map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"sub": "42",
+		"aud": "api",
+		"exp": 1700000000,
+		"iat": 1699999999.5,
+		"email": "a@example.com",
+		"realm_access": {"roles": ["admin"], "groups": ["ops"]},
+		"scope": ["read", "write"],
+		"azp": "web"
+	}`), &claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unknown, err := DecodeClaims(claims, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// registeredClaims is unexported, so it's skipped
+	if want := []string{"aud", "azp", "exp", "iat", "realm_access.groups", "sub"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown: got %q want %q", unknown, want)
+	}
+
+	if c.Email != "a@example.com" || !reflect.DeepEqual(c.Scopes, []string{"read", "write"}) {
+		t.Errorf("got %#v", c)
+	}
+
+	if c.Realm == nil || !reflect.DeepEqual(c.Realm.Roles, []string{"admin"}) {
+		t.Errorf("realm: got %#v", c.Realm)
+	}
+}
+
+func TestDecodeClaims_Registered(t *testing.T) {
+	var c registeredClaims
+
+	unknown, err := DecodeClaims(map[string]interface{}{
+		"iss": "https://issuer",
+		"aud": "api",
+		"exp": json.Number("1700000000"),
+		"iat": 1699999999.25,
+	}, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(unknown) != 0 {
+		t.Errorf("unknown: got %q", unknown)
+	}
+
+	want := registeredClaims{
+		Issuer:    "https://issuer",
+		Audience:  []string{"api"},
+		ExpiresAt: time.Unix(1700000000, 0).UTC(),
+	}
+
+	iat := time.Unix(1699999999, 250000000).UTC()
+	if c.IssuedAt == nil || !c.IssuedAt.Equal(iat) {
+		t.Errorf("iat: got %v want %v", c.IssuedAt, iat)
+	}
+
+	c.IssuedAt = nil
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+}
+
+func TestDecodeClaims_Embedded(t *testing.T) {
+	var c exportedClaims
+
+	unknown, err := DecodeClaims(map[string]interface{}{"email": "a@example.com"}, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(unknown) != 0 || c.Registered != nil {
+		t.Errorf("got %q and %#v, expected the embedded pointer to stay nil", unknown, c.Registered)
+	}
+
+	unknown, err = DecodeClaims(map[string]interface{}{"sub": "42", "jti": "x"}, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Registered == nil || c.Subject != "42" {
+		t.Errorf("got %#v", c.Registered)
+	}
+
+	if want := []string{"jti"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown: got %q want %q", unknown, want)
+	}
+}
+
+func TestDecodeClaims_Errors(t *testing.T) {
+	var c tokenClaims
+
+	_, err := DecodeClaims(map[string]interface{}{
+		"email":        42,
+		"realm_access": map[string]interface{}{"roles": "admin", "level": 1},
+	}, &c)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("got %v", err)
+	}
+
+	if errs[0].Path != "Email" || !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("got %v", errs[0])
+	}
+
+	// a single role is accepted like a single audience
+	if !reflect.DeepEqual(c.Realm.Roles, []string{"admin"}) {
+		t.Errorf("roles: got %q", c.Realm.Roles)
+	}
+
+	_, err = DecodeClaims(map[string]interface{}{}, c)
+	if !errors.Is(err, errNotStructPtr) {
+		t.Errorf("got %v", err)
+	}
+}
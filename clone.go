@@ -0,0 +1,264 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Clone returns a deep copy of s. Nested structs, pointers, slices, arrays,
+// maps and interfaces are copied recursively, so the copy can be modified
+// without affecting s. Pointers which point to the same value, including
+// pointer cycles, point to the same copy. Fields tagged with "-" are left as
+// zero values in the copy. Unexported fields, chans and funcs are copied as
+// is, i.e. they're shared with s.
+//
+// If s was created from a pointer, a pointer to the copy is returned,
+// otherwise the copy itself.
+func (s *Struct) Clone() interface{} {
+	c := &cloner{
+		tagName: s.TagName,
+		seen:    make(map[visit]reflect.Value),
+	}
+
+	return c.clone(reflect.ValueOf(s.raw)).Interface()
+}
+
+// CloneWith returns a deep copy of s like Clone, with the fields given by
+// their name or path, i.e. "Server.Port", set to the given values. Values
+// are converted as described in Fill and nil pointers along a path are
+// allocated in the copy. The values themselves are not copied. It returns an
+// error wrapping ErrFieldNotFound or ErrNotExported for invalid names and a
+// FieldErrors if values can't be assigned to their fields.
+func (s *Struct) CloneWith(fields map[string]interface{}) (interface{}, error) {
+	d := s.clonePtr()
+
+	var errs FieldErrors
+
+	for path, val := range fields {
+		v, _, err := fieldByPath(d.value, path, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := d.assign(v, val); err != nil {
+			errs.add(path, v.Type(), fmt.Sprintf("%T", val), err)
+		}
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return s.cloneResult(d), nil
+}
+
+// ClonePick returns a deep copy of s like Clone which only keeps the fields
+// given by their name or path, i.e. "Server.Host". All other fields,
+// including unexported ones, are zero values, such as for a public view of a
+// user record. It returns an error wrapping ErrFieldNotFound or
+// ErrNotExported for invalid names.
+func (s *Struct) ClonePick(names ...string) (interface{}, error) {
+	src := s.clonePtr()
+	d := s.nestedStruct(reflect.New(s.value.Type()).Interface())
+
+	for _, path := range names {
+		sv, ok, err := fieldByPath(src.value, path, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// fields behind nil pointers are zero values already
+		if !ok {
+			continue
+		}
+
+		dv, _, err := fieldByPath(d.value, path, true)
+		if err != nil {
+			return nil, err
+		}
+
+		dv.Set(sv)
+	}
+
+	return s.cloneResult(d), nil
+}
+
+// CloneOmit returns a deep copy of s like Clone with the fields given by
+// their name or path set to their zero value. For more info refer to Struct
+// types ZeroFields() method.
+func (s *Struct) CloneOmit(names ...string) (interface{}, error) {
+	d := s.clonePtr()
+
+	if err := d.ZeroFields(names...); err != nil {
+		return nil, err
+	}
+
+	return s.cloneResult(d), nil
+}
+
+// clonePtr returns a deep copy of s created from a pointer, so the fields of
+// the copy can be set.
+func (s *Struct) clonePtr() *Struct {
+	v := reflect.ValueOf(s.Clone())
+	if v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+
+	return s.nestedStruct(v.Interface())
+}
+
+// cloneResult returns the copy d of s as a pointer if s was created from a
+// pointer, otherwise as a struct.
+func (s *Struct) cloneResult(d *Struct) interface{} {
+	if reflect.ValueOf(s.raw).Kind() == reflect.Ptr {
+		return d.raw
+	}
+
+	return d.value.Interface()
+}
+
+// cloner deep copies values for Clone.
+type cloner struct {
+	// tagName is used to find the fields tagged with "-". If it's empty,
+	// all fields are copied.
+	tagName string
+
+	// seen holds the copies of the pointers copied so far.
+	seen map[visit]reflect.Value
+}
+
+// clone returns a deep copy of v.
+func (c *cloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		key := visit{ptr: v.Pointer(), typ: v.Type()}
+		if p, ok := c.seen[key]; ok {
+			return p
+		}
+
+		p := reflect.New(v.Type().Elem())
+		c.seen[key] = p
+		p.Elem().Set(c.clone(v.Elem()))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		i := reflect.New(v.Type()).Elem()
+		i.Set(c.clone(v.Elem()))
+		return i
+	case reflect.Struct:
+		t := v.Type()
+
+		// copies the unexported fields too
+		n := reflect.New(t).Elem()
+		n.Set(v)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if c.tagName != "" && field.Tag.Get(c.tagName) == "-" {
+				n.Field(i).Set(reflect.Zero(field.Type))
+				continue
+			}
+
+			n.Field(i).Set(c.clone(v.Field(i)))
+		}
+
+		return n
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		n := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(c.clone(v.Index(i)))
+		}
+
+		return n
+	case reflect.Array:
+		n := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(c.clone(v.Index(i)))
+		}
+
+		return n
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		n := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			n.SetMapIndex(c.clone(iter.Key()), c.clone(iter.Value()))
+		}
+
+		return n
+	}
+
+	return v
+}
+
+// Clone returns a deep copy of the given struct or pointer to struct. For
+// more info refer to Struct types Clone() method. It panics if s's kind is
+// not struct.
+func Clone(s interface{}) interface{} {
+	return New(s).Clone()
+}
+
+// CloneWith returns a deep copy of the given struct or pointer to struct with
+// the given fields set. For more info refer to Struct types CloneWith()
+// method. It panics if s's kind is not struct.
+func CloneWith(s interface{}, fields map[string]interface{}) (interface{}, error) {
+	return New(s).CloneWith(fields)
+}
+
+// ClonePick returns a deep copy of the given struct or pointer to struct
+// which only keeps the given fields. For more info refer to Struct types
+// ClonePick() method. It panics if s's kind is not struct.
+func ClonePick(s interface{}, names ...string) (interface{}, error) {
+	return New(s).ClonePick(names...)
+}
+
+// CloneOmit returns a deep copy of the given struct or pointer to struct
+// without the given fields. For more info refer to Struct types CloneOmit()
+// method. It panics if s's kind is not struct.
+func CloneOmit(s interface{}, names ...string) (interface{}, error) {
+	return New(s).CloneOmit(names...)
+}
+
+// CloneOf is the same as Clone, but returns the copy with the type of s. It
+// panics if s's kind is not struct.
+func CloneOf[T any](s T) T {
+	return Clone(s).(T)
+}
+
+// Update returns a deep copy of s, made with Clone, modified by the given
+// functions in order. s itself is left as is, so it can be shared safely:
+//
+//   next := structs.Update(cfg, func(c *Config) {
+//       c.Server.Port = 8080
+//   })
+//
+// It panics if T's kind is not struct.
+func Update[T any](s T, fns ...func(*T)) T {
+	c := CloneOf(s)
+
+	for _, fn := range fns {
+		fn(&c)
+	}
+
+	return c
+}
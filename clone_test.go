@@ -0,0 +1,215 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type cloneNode struct {
+	Name     string
+	Tags     []string
+	Meta     map[string][]int
+	Next     *cloneNode
+	Any      interface{}
+	Grid     [2][]int
+	Password string `structs:"-"`
+	secret   *int
+}
+
+func TestClone(t *testing.T) {
+	n := 1
+	orig := &cloneNode{
+		Name:     "a",
+		Tags:     []string{"x"},
+		Meta:     map[string][]int{"k": {1}},
+		Any:      &cloneNode{Name: "any"},
+		Grid:     [2][]int{{1}, {2}},
+		Password: "hunter2",
+		secret:   &n,
+	}
+	orig.Next = orig
+
+	c := Clone(orig).(*cloneNode)
+
+	if c == orig {
+		t.Fatal("Clone returned the same pointer")
+	}
+
+	if c.Next != c {
+		t.Error("the pointer cycle should point to the copy")
+	}
+
+	if c.Password != "" {
+		t.Errorf(`fields tagged with "-" should be zero, got %q`, c.Password)
+	}
+
+	if c.secret != orig.secret {
+		t.Error("unexported fields should be copied as is")
+	}
+
+	c.Tags[0] = "y"
+	c.Meta["k"][0] = 2
+	c.Any.(*cloneNode).Name = "changed"
+	c.Grid[0][0] = 3
+
+	if orig.Tags[0] != "x" || orig.Meta["k"][0] != 1 || orig.Any.(*cloneNode).Name != "any" || orig.Grid[0][0] != 1 {
+		t.Errorf("modifying the copy changed the original: %+v", orig)
+	}
+}
+
+func TestCloneOf(t *testing.T) {
+	orig := cloneNode{Name: "a", Tags: []string{"x"}}
+
+	c := CloneOf(orig)
+	c.Tags[0] = "y"
+
+	if !reflect.DeepEqual(orig.Tags, []string{"x"}) {
+		t.Errorf("modifying the copy changed the original: %+v", orig)
+	}
+
+	if c.Name != "a" {
+		t.Errorf("got %+v", c)
+	}
+}
+
+type cloneServer struct {
+	Host string
+	Port int
+}
+
+type cloneConfig struct {
+	Name   string
+	Server cloneServer
+	Backup *cloneServer
+	Tags   []string
+}
+
+func TestCloneWith(t *testing.T) {
+	orig := cloneConfig{
+		Name:   "a",
+		Server: cloneServer{"localhost", 80},
+		Tags:   []string{"x"},
+	}
+
+	c, err := CloneWith(orig, map[string]interface{}{
+		"Name":        "b",
+		"Server.Port": float64(8080),
+		"Backup.Host": "backup",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := cloneConfig{
+		Name:   "b",
+		Server: cloneServer{"localhost", 8080},
+		Backup: &cloneServer{Host: "backup"},
+		Tags:   []string{"x"},
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v want %+v", c, want)
+	}
+
+	if orig.Name != "a" || orig.Server.Port != 80 || orig.Backup != nil {
+		t.Errorf("the original was modified: %+v", orig)
+	}
+
+	p, err := CloneWith(&orig, map[string]interface{}{"Tags": []interface{}{"y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cp := p.(*cloneConfig); cp == &orig || !reflect.DeepEqual(cp.Tags, []string{"y"}) {
+		t.Errorf("got %+v", cp)
+	}
+
+	if _, err := CloneWith(orig, map[string]interface{}{"Missing": 1}); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound, got %v", err)
+	}
+
+	_, err = CloneWith(orig, map[string]interface{}{"Server.Port": "http"})
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || errs[0].Path != "Server.Port" {
+		t.Errorf("expected a FieldErrors for Server.Port, got %v", err)
+	}
+}
+
+type cloneUser struct {
+	Name     string
+	Email    string
+	Password string
+	Profile  *cloneServer
+	Admin    bool
+	token    string
+}
+
+func TestClonePickOmit(t *testing.T) {
+	u := &cloneUser{
+		Name:     "gopher",
+		Email:    "g@example.com",
+		Password: "hunter2",
+		Profile:  &cloneServer{Host: "h", Port: 1},
+		Admin:    true,
+		token:    "t",
+	}
+
+	picked, err := ClonePick(u, "Name", "Profile.Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &cloneUser{Name: "gopher", Profile: &cloneServer{Host: "h"}}
+	if !reflect.DeepEqual(picked, want) {
+		t.Errorf("ClonePick: got %+v want %+v", picked, want)
+	}
+
+	if picked.(*cloneUser).Profile == u.Profile {
+		t.Error("ClonePick: the pointer should be copied")
+	}
+
+	omitted, err := CloneOmit(*u, "Password", "Profile.Port")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantOmit := cloneUser{
+		Name:    "gopher",
+		Email:   "g@example.com",
+		Profile: &cloneServer{Host: "h"},
+		Admin:   true,
+		token:   "t",
+	}
+	if !reflect.DeepEqual(omitted, wantOmit) {
+		t.Errorf("CloneOmit: got %+v want %+v", omitted, wantOmit)
+	}
+
+	if u.Password != "hunter2" || u.Profile.Port != 1 {
+		t.Errorf("the original was modified: %+v", u)
+	}
+
+	if _, err := ClonePick(u, "token"); !errors.Is(err, ErrNotExported) {
+		t.Errorf("expected ErrNotExported, got %v", err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	orig := cloneConfig{Name: "a", Tags: []string{"x"}}
+
+	next := Update(orig,
+		func(c *cloneConfig) { c.Name = "b" },
+		func(c *cloneConfig) { c.Tags[0] = "y" },
+		func(c *cloneConfig) { c.Backup = &cloneServer{Port: 1} },
+	)
+
+	want := cloneConfig{Name: "b", Tags: []string{"y"}, Backup: &cloneServer{Port: 1}}
+	if !reflect.DeepEqual(next, want) {
+		t.Errorf("got %+v want %+v", next, want)
+	}
+
+	if orig.Name != "a" || orig.Tags[0] != "x" || orig.Backup != nil {
+		t.Errorf("the original was modified: %+v", orig)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// writeAccessors writes the Get and Set methods of the fields of t and the
+// StructsGet and StructsSet methods dispatching on the field's name.
+func (g *generator) writeAccessors(t *structType) error {
+	fields := t.converted()
+
+	obj := g.pkg.Scope().Lookup(t.name)
+	for _, f := range fields {
+		for _, name := range []string{"Get" + f.name, "Set" + f.name} {
+			if m, _, _ := types.LookupFieldOrMethod(obj.Type(), true, g.pkg, name); m != nil {
+				return fmt.Errorf("%s.%s: can't generate accessor, %s already has %s", t.name, f.name, t.name, name)
+			}
+		}
+	}
+
+	for _, f := range fields {
+		typ := g.typeString(f.typ)
+
+		g.printf("// Get%s returns the %s field of t.\n", f.name, f.name)
+		g.printf("func (t *%s) Get%s() %s {\nreturn t.%s\n}\n\n", t.name, f.name, typ, f.name)
+
+		g.printf("// Set%s sets the %s field of t to v.\n", f.name, f.name)
+		g.printf("func (t *%s) Set%s(v %s) {\nt.%s = v\n}\n\n", t.name, f.name, typ, f.name)
+	}
+
+	g.printf("// StructsGet returns the value of the field with the given name like\n")
+	g.printf("// structs.Field.Value. The boolean is false if there is no such field.\n")
+	g.printf("func (t *%s) StructsGet(name string) (interface{}, bool) {\n", t.name)
+	g.printf("switch name {\n")
+	for _, f := range fields {
+		g.printf("case %q:\nreturn t.%s, true\n", f.name, f.name)
+	}
+	g.printf("}\n\nreturn nil, false\n}\n\n")
+
+	g.use("fmt", "fmt")
+
+	g.printf("// StructsSet sets the field with the given name to v, which is converted\n")
+	g.printf("// as in StructsFill. It returns an error if there is no such field.\n")
+	g.printf("func (t *%s) StructsSet(name string, v interface{}) error {\n", t.name)
+	g.printf("switch name {\n")
+	for _, f := range fields {
+		g.printf("case %q:\n", f.name)
+		g.writeAssign(t, f, func(err string) {
+			g.printf("return fmt.Errorf(\"%s: %%w\", %s)\n", f.name, err)
+		})
+	}
+	g.printf("default:\nreturn fmt.Errorf(\"field %%q not found\", name)\n}\n\n")
+	g.printf("return nil\n}\n\n")
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const accessorsPkg = `package main
+
+import "fmt"
+
+type Address struct {
+	Street string
+}
+
+//structs:generate
+type User struct {
+	Name    string
+	Age     int
+	Home    *Address
+	Ignored string ` + "`structs:\"-\"`" + `
+}
+
+func main() {
+	var u User
+	u.SetName("Ann")
+	fmt.Println(u.GetName())
+
+	fmt.Println(u.StructsSet("Age", float64(42)), u.GetAge())
+	fmt.Println(u.StructsSet("Age", 1.5))
+	fmt.Println(u.StructsSet("Home", &Address{"Main"}), u.Home.Street)
+	fmt.Println(u.StructsSet("Ignored", "x"))
+
+	fmt.Println(u.StructsGet("Name"))
+	fmt.Println(u.StructsGet("Missing"))
+}
+`
+
+const accessorsOutput = `Ann
+<nil> 42
+Age: 1.5 is not an integer
+<nil> Main
+field "Ignored" not found
+Ann true
+<nil> false
+`
+
+func TestGenerate_Accessors(t *testing.T) {
+	g := newGenerator("structs")
+	g.accessors = true
+
+	if out := run(t, g, accessorsPkg); out != accessorsOutput {
+		t.Errorf("got:\n%s\nwant:\n%s", out, accessorsOutput)
+	}
+}
+
+func TestGenerate_AccessorConflict(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\ntype T struct {\n\tName string\n}\n\nfunc (t T) GetName() string { return t.Name }\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := newGenerator("structs")
+	g.accessors = true
+
+	if err := g.load(dir, []string{"T"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.generate(); err == nil || !strings.Contains(err.Error(), "GetName") {
+		t.Errorf("expected a conflict with GetName, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"time"
+)
+
+// defaultTagName is the tag name of the default values of the builders,
+// the same as the one of structs.ApplyDefaults.
+const defaultTagName = "default"
+
+// writeBuilder writes the TBuilder type of t, its NewTBuilder constructor
+// and a With method for each exported field.
+func (g *generator) writeBuilder(t *structType) error {
+	builder, ctor := t.name+"Builder", "New"+t.name+"Builder"
+	for _, name := range []string{builder, ctor} {
+		if g.pkg.Scope().Lookup(name) != nil {
+			return fmt.Errorf("%s: can't generate builder, %s is already declared", t.name, name)
+		}
+	}
+
+	var fields []*field
+	for _, f := range t.fields {
+		if f.exported {
+			fields = append(fields, f)
+		}
+	}
+
+	// the With calls setting the default values
+	var defaults []string
+	for _, f := range fields {
+		def, ok := f.tag.Lookup(defaultTagName)
+		if !ok {
+			continue
+		}
+
+		lit, err := g.defaultLiteral(withType(f.typ), def)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.name, f.name, err)
+		}
+
+		if lit == "" {
+			g.warnf("%s.%s: the builder doesn't support the default of %s fields", t.name, f.name, f.typ)
+			continue
+		}
+
+		defaults = append(defaults, fmt.Sprintf("With%s(%s)", f.name, lit))
+	}
+
+	g.printf("// %s builds a %s step by step, such as in tests:\n", builder, t.name)
+	g.printf("//\n//   v := %s()", ctor)
+	if len(fields) > 0 {
+		g.printf(".With%s(...)", fields[0].name)
+	}
+	g.printf(".Build()\n")
+	g.printf("type %s struct {\nv %s\n}\n\n", builder, t.name)
+
+	g.printf("// %s returns a builder of a %s whose fields are set to the values\n", ctor, t.name)
+	g.printf("// of their default tags, if any, and zero otherwise.\n")
+	g.printf("func %s() *%s {\n", ctor, builder)
+	g.printf("b := &%s{}\n", builder)
+	for _, d := range defaults {
+		g.printf("b.%s\n", d)
+	}
+	g.printf("return b\n}\n\n")
+
+	for _, f := range fields {
+		typ := withType(f.typ)
+
+		if typ != f.typ {
+			g.printf("// With%s sets the %s field to a pointer to a copy of v.\n", f.name, f.name)
+			g.printf("func (b *%s) With%s(v %s) *%s {\nb.v.%s = &v\nreturn b\n}\n\n", builder, f.name, g.typeString(typ), builder, f.name)
+			continue
+		}
+
+		g.printf("// With%s sets the %s field to v.\n", f.name, f.name)
+		g.printf("func (b *%s) With%s(v %s) *%s {\nb.v.%s = v\nreturn b\n}\n\n", builder, f.name, g.typeString(typ), builder, f.name)
+	}
+
+	g.printf("// Build returns the %s. Slices, maps and pointers are shared with\n", t.name)
+	g.printf("// the builder, so a builder can be reused for values which only differ\n")
+	g.printf("// in other fields.\n")
+	g.printf("func (b *%s) Build() %s {\nreturn b.v\n}\n\n", builder, t.name)
+
+	return nil
+}
+
+// withType returns the type of the argument of the With method of a field
+// of type t. Pointers are set from the value they point to, so tests don't
+// need a variable to take the address of.
+func withType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+
+	return t
+}
+
+// defaultLiteral returns the default value def of a field of type t as a Go
+// literal. It returns an empty string if defaults of t are not supported.
+func (g *generator) defaultLiteral(t types.Type, def string) (string, error) {
+	if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil &&
+		n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Duration" {
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", def, err)
+		}
+
+		return fmt.Sprintf("%d /* %s */", int64(d), d), nil
+	}
+
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return "", nil
+	}
+
+	switch {
+	case b.Info()&types.IsString != 0:
+		return strconv.Quote(def), nil
+	case b.Info()&types.IsBoolean != 0:
+		v, err := strconv.ParseBool(def)
+		if err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", def, err)
+		}
+
+		return strconv.FormatBool(v), nil
+	case b.Info()&types.IsNumeric != 0 && b.Info()&types.IsComplex == 0:
+		lit, _, err := numberArg(b, def)
+		if err != nil {
+			return "", fmt.Errorf("default: %w", err)
+		}
+
+		return lit, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const buildersPkg = `package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type Address struct {
+	Street string
+}
+
+//structs:generate
+type Server struct {
+	Host    string        ` + "`default:\"localhost\"`" + `
+	Port    uint16        ` + "`default:\"8080\"`" + `
+	Ratio   float64       ` + "`default:\"0.5\"`" + `
+	Debug   bool          ` + "`default:\"true\"`" + `
+	Timeout time.Duration ` + "`default:\"1m30s\"`" + `
+	Name    *string
+	Home    *Address
+	Tags    []string
+	secret  string
+}
+
+func main() {
+	s := NewServerBuilder().Build()
+	fmt.Println(s.Host, s.Port, s.Ratio, s.Debug, s.Timeout, s.Name == nil, s.Home == nil, s.Tags == nil)
+
+	b := NewServerBuilder().WithPort(0).WithName("api").WithHome(Address{"Main"})
+	s = b.WithTags([]string{"a"}).Build()
+	fmt.Println(s.Port, *s.Name, s.Home.Street, s.Tags)
+
+	t := b.WithName("web").Build()
+	fmt.Println(*s.Name, *t.Name)
+}
+`
+
+const buildersOutput = `localhost 8080 0.5 true 1m30s true true true
+0 api Main [a]
+api web
+`
+
+func TestGenerate_Builders(t *testing.T) {
+	g := newGenerator("structs")
+	g.builders = true
+
+	if out := run(t, g, buildersPkg); out != buildersOutput {
+		t.Errorf("got:\n%s\nwant:\n%s", out, buildersOutput)
+	}
+}
+
+func TestGenerate_BuilderErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"type T struct {\n\tName string\n}\n\ntype TBuilder struct{}\n", "TBuilder is already declared"},
+		{"type T struct {\n\tName string\n}\n\nfunc NewTBuilder() {}\n", "NewTBuilder is already declared"},
+		{"type T struct {\n\tPort uint8 `default:\"300\"`\n}\n", "T.Port"},
+		{"type T struct {\n\tDebug bool `default:\"yes\"`\n}\n", "T.Debug"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte("package p\n\n"+test.src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		g := newGenerator("structs")
+		g.builders = true
+
+		if err := g.load(dir, []string{"T"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.generate(); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: expected an error with %q, got %v", test.src, test.want, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import "fmt"
+
+// writeConsts writes a constant for the key of each field of t, such as
+// UserFieldEmail for the Email field of User.
+func (g *generator) writeConsts(t *structType) error {
+	fields := t.converted()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	g.printf("// Keys of the fields of %s.\n", t.name)
+	g.printf("const (\n")
+
+	for _, f := range fields {
+		name := t.name + "Field" + f.name
+		if g.pkg.Scope().Lookup(name) != nil {
+			return fmt.Errorf("%s.%s: can't generate constant, %s is already declared", t.name, f.name, name)
+		}
+
+		g.printf("%s = %q\n", name, f.key)
+	}
+
+	g.printf(")\n\n")
+	return nil
+}
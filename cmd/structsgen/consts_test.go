@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const constsPkg = `package main
+
+import "fmt"
+
+//structs:generate
+type User struct {
+	Name    string
+	Email   string ` + "`structs:\"email,omitempty\"`" + `
+	Ignored string ` + "`structs:\"-\"`" + `
+}
+
+func main() {
+	fmt.Println(UserFieldName, UserFieldEmail)
+}
+`
+
+func TestGenerate_Consts(t *testing.T) {
+	g := newGenerator("structs")
+	g.consts = true
+
+	if out := run(t, g, constsPkg); out != "Name email\n" {
+		t.Errorf("got %q", out)
+	}
+}
+
+func TestGenerate_ConstConflict(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nconst TFieldName = 1\n\ntype T struct {\n\tName string\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := newGenerator("structs")
+	g.consts = true
+
+	if err := g.load(dir, []string{"T"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.generate(); err == nil || !strings.Contains(err.Error(), "TFieldName") {
+		t.Errorf("expected a conflict with TFieldName, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// conversion is a pair of struct types to generate a converter for.
+type conversion struct {
+	src, dst *structType
+}
+
+// name returns the name of the converter function.
+func (c conversion) name() string {
+	return c.src.name + "To" + c.dst.name
+}
+
+// addConversions adds the conversions given as "Src:Dst" pairs.
+func (g *generator) addConversions(pairs []string) error {
+	for _, pair := range pairs {
+		src, dst, ok := strings.Cut(pair, ":")
+		if !ok || src == "" || dst == "" {
+			return fmt.Errorf("invalid conversion %q, want Src:Dst", pair)
+		}
+
+		s, err := g.structType(src)
+		if err != nil {
+			return err
+		}
+
+		d, err := g.structType(dst)
+		if err != nil {
+			return err
+		}
+
+		g.conversions = append(g.conversions, conversion{src: s, dst: d})
+	}
+
+	return nil
+}
+
+// converter returns the conversion from the type src into dst, if it's
+// generated.
+func (g *generator) converter(src, dst types.Type) (conversion, bool) {
+	name := func(t types.Type) string {
+		if named, ok := t.(*types.Named); ok && named.Obj().Pkg() == g.pkg {
+			return named.Obj().Name()
+		}
+		return ""
+	}
+
+	for _, c := range g.conversions {
+		if c.src.name == name(src) && c.dst.name == name(dst) {
+			return c, true
+		}
+	}
+
+	return conversion{}, false
+}
+
+// writeConversion writes the converter function of c. Fields are matched by
+// their keys and unmapped fields are reported as warnings and in the
+// function's doc comment.
+func (g *generator) writeConversion(c conversion) {
+	srcFields := make(map[string]*field)
+	for _, f := range c.src.converted() {
+		srcFields[f.key] = f
+	}
+
+	var body strings.Builder
+	var unmappedSrc, unmappedDst []string
+
+	mapped := make(map[string]bool)
+	for _, df := range c.dst.converted() {
+		sf, ok := srcFields[df.key]
+		if !ok {
+			unmappedDst = append(unmappedDst, df.name)
+			continue
+		}
+
+		assign, ok := g.convertField(sf, df)
+		if !ok {
+			g.warnf("%s: can't convert %s.%s of type %s into %s.%s of type %s",
+				c.name(), c.src.name, sf.name, sf.typ, c.dst.name, df.name, df.typ)
+			unmappedDst = append(unmappedDst, df.name)
+			continue
+		}
+
+		mapped[sf.name] = true
+		body.WriteString(assign)
+	}
+
+	for _, sf := range c.src.converted() {
+		if !mapped[sf.name] {
+			unmappedSrc = append(unmappedSrc, sf.name)
+		}
+	}
+
+	g.printf("// %s converts src into %s, matching the fields by their keys.\n", c.name(), c.dst.name)
+
+	if len(unmappedDst) > 0 || len(unmappedSrc) > 0 {
+		g.printf("//\n")
+	}
+
+	if len(unmappedDst) > 0 {
+		g.printf("// Unmapped fields of %s: %s.\n", c.dst.name, strings.Join(unmappedDst, ", "))
+		g.warnf("%s: unmapped fields of %s: %s", c.name(), c.dst.name, strings.Join(unmappedDst, ", "))
+	}
+
+	if len(unmappedSrc) > 0 {
+		g.printf("// Unmapped fields of %s: %s.\n", c.src.name, strings.Join(unmappedSrc, ", "))
+		g.warnf("%s: unmapped fields of %s: %s", c.name(), c.src.name, strings.Join(unmappedSrc, ", "))
+	}
+
+	g.printf("func %s(src *%s) %s {\n", c.name(), c.src.name, c.dst.name)
+	g.printf("var dst %s\n", c.dst.name)
+	g.printf("%s", body.String())
+	g.printf("return dst\n}\n\n")
+}
+
+// convertField returns the code assigning the field sf of src to the field
+// df of dst. The boolean is false if sf can't be converted into df.
+func (g *generator) convertField(sf, df *field) (string, bool) {
+	src, dst := "src."+sf.name, "dst."+df.name
+
+	if types.Identical(sf.typ, df.typ) {
+		return fmt.Sprintf("%s = %s\n", dst, src), true
+	}
+
+	if c, ok := g.converter(sf.typ, df.typ); ok {
+		return fmt.Sprintf("%s = %s(&%s)\n", dst, c.name(), src), true
+	}
+
+	sp, sok := sf.typ.(*types.Pointer)
+	dp, dok := df.typ.(*types.Pointer)
+	if sok && dok {
+		if c, ok := g.converter(sp.Elem(), dp.Elem()); ok {
+			return fmt.Sprintf("if %s != nil {\nv := %s(%s)\n%s = &v\n}\n", src, c.name(), src, dst), true
+		}
+	}
+
+	if types.ConvertibleTo(sf.typ, df.typ) && !integerToString(sf.typ, df.typ) {
+		typ := g.typeString(df.typ)
+		if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "<-") || strings.HasPrefix(typ, "func") {
+			typ = "(" + typ + ")"
+		}
+
+		return fmt.Sprintf("%s = %s(%s)\n", dst, typ, src), true
+	}
+
+	return "", false
+}
+
+// integerToString reports whether src is an integer and dst a string, which
+// is a valid conversion yielding a rune instead of the formatted integer.
+func integerToString(src, dst types.Type) bool {
+	s, sok := src.Underlying().(*types.Basic)
+	d, dok := dst.Underlying().(*types.Basic)
+
+	return sok && dok && s.Info()&types.IsInteger != 0 && d.Info()&types.IsString != 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const convertPkg = `package main
+
+import "fmt"
+
+type Level int
+
+type Address struct {
+	Street string
+}
+
+type AddressDTO struct {
+	Street string
+}
+
+type User struct {
+	ID       int
+	Name     string
+	Level    int
+	Password string
+	Home     Address
+	Work     *Address
+}
+
+type UserDTO struct {
+	ID       string ` + "`structs:\"ID\"`" + `
+	FullName string ` + "`structs:\"Name\"`" + `
+	Level    Level
+	Home     AddressDTO
+	Work     *AddressDTO
+	Extra    bool
+}
+
+func main() {
+	u := User{ID: 1, Name: "Ann", Level: 2, Password: "x", Home: Address{"Main"}, Work: &Address{"Office"}}
+	d := UserToUserDTO(&u)
+	fmt.Printf("%q %q %d %v %v %v\n", d.ID, d.FullName, d.Level, d.Home, *d.Work, d.Extra)
+
+	u = User{}
+	fmt.Println(UserToUserDTO(&u).Work == nil)
+}
+`
+
+const convertOutput = `"" "Ann" 2 {Main} {Office} false
+true
+`
+
+func TestGenerate_Convert(t *testing.T) {
+	g := newGenerator("structs")
+
+	out := run(t, g, convertPkg, "User:UserDTO", "Address:AddressDTO")
+	if out != convertOutput {
+		t.Errorf("got:\n%s\nwant:\n%s", out, convertOutput)
+	}
+
+	want := []string{
+		"UserToUserDTO: can't convert User.ID of type int into UserDTO.ID of type string",
+		"UserToUserDTO: unmapped fields of UserDTO: ID, Extra",
+		"UserToUserDTO: unmapped fields of User: ID, Password",
+	}
+
+	if !reflect.DeepEqual(g.warnings, want) {
+		t.Errorf("warnings: got %q want %q", g.warnings, want)
+	}
+}
+
+func TestAddConversions_Invalid(t *testing.T) {
+	g := newGenerator("structs")
+	for _, pair := range []string{"User", "User:", ":User"} {
+		if err := g.addConversions([]string{pair}); err == nil {
+			t.Errorf("%q: expected an error", pair)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+)
+
+// numberHelper returns the helper converting values into numbers of the
+// kind of b and the size of the number.
+func numberHelper(b *types.Basic) (helper, bits string, ok bool) {
+	switch b.Kind() {
+	case types.Int, types.Uint, types.Uintptr:
+		bits = "strconv.IntSize"
+	case types.Int8, types.Uint8:
+		bits = "8"
+	case types.Int16, types.Uint16:
+		bits = "16"
+	case types.Int32, types.Uint32, types.Float32:
+		bits = "32"
+	case types.Int64, types.Uint64, types.Float64:
+		bits = "64"
+	default:
+		return "", "", false
+	}
+
+	switch {
+	case b.Info()&types.IsUnsigned != 0:
+		helper = "structsgenUint"
+	case b.Info()&types.IsInteger != 0:
+		helper = "structsgenInt"
+	default:
+		helper = "structsgenFloat"
+	}
+
+	return helper, bits, true
+}
+
+// writeFill writes the StructsFill method of t.
+func (g *generator) writeFill(t *structType) error {
+	g.printf("// StructsFill sets the fields of t from m like structs.Fill. Errors of\n")
+	g.printf("// all fields are joined with errors.Join.\n")
+	g.printf("func (t *%s) StructsFill(m map[string]interface{}) error {\n", t.name)
+	g.printf("var errs []error\n")
+
+	g.use("errors", "errors")
+
+	for _, f := range t.converted() {
+		report := func(err string) {
+			g.printf("errs = append(errs, fmt.Errorf(\"%s: %%w\", %s))\n", f.name, err)
+		}
+
+		if f.opts.Has("flatten") {
+			nested, ptr := g.nested(t, f)
+			if nested == nil {
+				g.warnf("%s.%s: type %s is not generated and can't be flattened by StructsFill", t.name, f.name, f.typ)
+				continue
+			}
+
+			expr := "t." + f.name
+			if ptr {
+				g.printf("if %s == nil {\n%s = new(%s)\n}\n", expr, expr, nested.name)
+			}
+
+			g.printf("if err := %s.StructsFill(m); err != nil {\n", expr)
+			report("err")
+			g.printf("}\n")
+			continue
+		}
+
+		g.printf("if v, ok := m[%s]; ok {\n", strconv.Quote(f.key))
+		g.writeAssign(t, f, report)
+		g.printf("}\n")
+	}
+
+	g.printf("return errors.Join(errs...)\n}\n\n")
+	return nil
+}
+
+// writeAssign writes the assignment of the value v to the field f, which is
+// converted as in StructsFill. Errors are handled by the code written by
+// report, given the error expression.
+func (g *generator) writeAssign(t *structType, f *field, report func(err string)) {
+	g.use("fmt", "fmt")
+
+	expr := "t." + f.name
+	typ := g.typeString(f.typ)
+	nested, ptr := g.nested(t, f)
+
+	g.printf("switch v := v.(type) {\n")
+	g.printf("case nil:\n%s = %s\n", expr, g.zero(f.typ))
+	g.printf("case %s:\n%s = v\n", typ, expr)
+
+	if nested != nil {
+		g.printf("case map[string]interface{}:\n")
+		if ptr {
+			g.printf("if %s == nil {\n%s = new(%s)\n}\n", expr, expr, nested.name)
+		}
+
+		g.printf("if err := %s.StructsFill(v); err != nil {\n", expr)
+		report("err")
+		g.printf("}\n")
+	}
+
+	basic, _ := f.typ.Underlying().(*types.Basic)
+
+	// named types of the same kind, such as a string into a type Color string
+	if basic != nil && !types.Identical(f.typ, basic) {
+		switch {
+		case basic.Info()&types.IsString != 0:
+			g.printf("case string:\n%s = %s(v)\n", expr, typ)
+		case basic.Info()&types.IsBoolean != 0:
+			g.printf("case bool:\n%s = %s(v)\n", expr, typ)
+		}
+	}
+
+	g.printf("default:\n")
+
+	if helper, bits, ok := numberHelper(basicOf(basic)); ok {
+		g.helpers[helper] = true
+		if bits == "strconv.IntSize" {
+			g.use("strconv", "strconv")
+		}
+
+		g.printf("n, err := %s(v, %s)\n", helper, bits)
+		g.printf("if err != nil {\n")
+		report("err")
+		g.printf("} else {\n%s = %s(n)\n}\n", expr, typ)
+	} else {
+		report(fmt.Sprintf("fmt.Errorf(\"can't set %%T into %s\", v)", typ))
+	}
+
+	g.printf("}\n")
+}
+
+// basicOf returns b or an invalid type if b is nil.
+func basicOf(b *types.Basic) *types.Basic {
+	if b == nil {
+		return types.Typ[types.Invalid]
+	}
+
+	return b
+}
+
+// writeHelpers writes the helpers used by the generated methods.
+func (g *generator) writeHelpers() {
+	if len(g.helpers) == 0 {
+		return
+	}
+
+	g.use("math", "math")
+
+	g.printf("%s", numberHelperSrc)
+
+	if g.helpers["structsgenInt"] {
+		g.printf("%s", intHelperSrc)
+	}
+
+	if g.helpers["structsgenUint"] {
+		g.printf("%s", uintHelperSrc)
+	}
+
+	if g.helpers["structsgenFloat"] {
+		g.printf("%s", floatHelperSrc)
+	}
+}
+
+const numberHelperSrc = `
+// structsgenNumber normalizes v into one of int64, uint64 or float64.
+func structsgenNumber(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return uint64(v), nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case uintptr:
+		return uint64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case interface {
+		Int64() (int64, error)
+		Float64() (float64, error)
+	}:
+		// json.Number
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %v", v)
+		}
+
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("%T is not a number", v)
+}
+`
+
+const intHelperSrc = `
+// structsgenInt converts the number v into a signed integer of the given size.
+func structsgenInt(v interface{}, bits int) (int64, error) {
+	num, err := structsgenNumber(v)
+	if err != nil {
+		return 0, err
+	}
+
+	min := int64(-1) << (bits - 1)
+	max := ^min
+
+	switch num := num.(type) {
+	case int64:
+		if num >= min && num <= max {
+			return num, nil
+		}
+	case uint64:
+		if num <= uint64(max) {
+			return int64(num), nil
+		}
+	case float64:
+		if num != math.Trunc(num) {
+			return 0, fmt.Errorf("%v is not an integer", num)
+		}
+
+		if num >= math.Ldexp(-1, bits-1) && num < math.Ldexp(1, bits-1) {
+			return int64(num), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%v overflows int%d", num, bits)
+}
+`
+
+const uintHelperSrc = `
+// structsgenUint converts the number v into an unsigned integer of the given
+// size.
+func structsgenUint(v interface{}, bits int) (uint64, error) {
+	num, err := structsgenNumber(v)
+	if err != nil {
+		return 0, err
+	}
+
+	max := uint64(1)<<bits - 1
+
+	switch num := num.(type) {
+	case int64:
+		if num >= 0 && uint64(num) <= max {
+			return uint64(num), nil
+		}
+	case uint64:
+		if num <= max {
+			return num, nil
+		}
+	case float64:
+		if num != math.Trunc(num) {
+			return 0, fmt.Errorf("%v is not an integer", num)
+		}
+
+		if num >= 0 && num < math.Ldexp(1, bits) {
+			return uint64(num), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%v overflows uint%d", num, bits)
+}
+`
+
+const floatHelperSrc = `
+// structsgenFloat converts the number v into a float of the given size.
+func structsgenFloat(v interface{}, bits int) (float64, error) {
+	num, err := structsgenNumber(v)
+	if err != nil {
+		return 0, err
+	}
+
+	var f float64
+	switch num := num.(type) {
+	case int64:
+		f = float64(num)
+	case uint64:
+		f = float64(num)
+	case float64:
+		f = num
+	}
+
+	if bits == 32 && math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%v overflows float32", f)
+	}
+
+	return f, nil
+}
+`
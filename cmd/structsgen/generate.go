@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"sort"
+	"strconv"
+)
+
+// printf writes formatted code to the body of the generated file.
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// warnf records a warning about the generated code, unless it was already
+// recorded.
+func (g *generator) warnf(format string, args ...interface{}) {
+	w := fmt.Sprintf(format, args...)
+	for _, seen := range g.warnings {
+		if seen == w {
+			return
+		}
+	}
+
+	g.warnings = append(g.warnings, w)
+}
+
+// typeString returns t as written in the generated file, recording the
+// packages it refers to.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+
+		g.use(p.Path(), p.Name())
+		return p.Name()
+	})
+}
+
+// use records that the generated code uses the package with the given path.
+func (g *generator) use(path, name string) {
+	g.imports[path] = name
+}
+
+// generate returns the formatted source of the generated file.
+func (g *generator) generate() ([]byte, error) {
+	if len(g.types) == 0 && len(g.conversions) == 0 {
+		return nil, fmt.Errorf("no types annotated with %s and no conversions", directive)
+	}
+
+	for _, t := range g.types {
+		if err := g.writeType(t); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range g.conversions {
+		g.writeConversion(c)
+	}
+
+	g.writeHelpers()
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\n\npackage %s\n\n", generatedHeader, g.pkg.Name())
+
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+
+		sort.Strings(paths)
+
+		out.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&out, "\t%s\n", strconv.Quote(path))
+		}
+		out.WriteString(")\n\n")
+	}
+
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, out.Bytes())
+	}
+
+	return src, nil
+}
+
+// writeType writes the methods of t.
+func (g *generator) writeType(t *structType) error {
+	if g.consts {
+		if err := g.writeConsts(t); err != nil {
+			return err
+		}
+	}
+
+	if err := g.writeMap(t); err != nil {
+		return err
+	}
+
+	if err := g.writeValues(t); err != nil {
+		return err
+	}
+
+	g.writeNames(t)
+
+	if err := g.writeFill(t); err != nil {
+		return err
+	}
+
+	if g.validate {
+		if err := g.writeValidate(t); err != nil {
+			return err
+		}
+	}
+
+	if g.accessors {
+		if err := g.writeAccessors(t); err != nil {
+			return err
+		}
+	}
+
+	if g.builders {
+		return g.writeBuilder(t)
+	}
+
+	return nil
+}
+
+// converted returns the fields of t which are converted by Map and Values.
+func (t *structType) converted() []*field {
+	var fields []*field
+	for _, f := range t.fields {
+		if f.exported && !f.ignored {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// writeMap writes the StructsMap method of t.
+func (g *generator) writeMap(t *structType) error {
+	g.printf("// StructsMap converts t to a map[string]interface{} like structs.Map.\n")
+	g.printf("func (t *%s) StructsMap() map[string]interface{} {\n", t.name)
+	g.printf("m := make(map[string]interface{})\n")
+
+	for _, f := range t.converted() {
+		key := strconv.Quote(f.key)
+		expr := "t." + f.name
+
+		closeIf, err := g.omitEmpty(t, f, expr)
+		if err != nil {
+			return err
+		}
+
+		nested, ptr := g.nested(t, f)
+
+		switch {
+		case f.opts.Has("sensitive") || f.tag.Get("mask") != "":
+			g.use(structsPath, "structs")
+			g.printf("m[%s] = structs.MaskValue(%q, %q, %s)\n", key,
+				g.pkg.Name()+"."+t.name+"."+f.name, f.tag.Get("mask"), expr)
+		case f.opts.Has("string"):
+			if g.stringer(f.typ) {
+				g.printf("m[%s] = %s.String()\n", key, expr)
+			}
+		case nested != nil && ptr:
+			g.printf("if %s != nil {\n", expr)
+			g.writeNestedMap(key, expr, f.opts.Has("flatten"))
+			g.printf("} else {\nm[%s] = %s\n}\n", key, expr)
+		case nested != nil:
+			g.writeNestedMap(key, expr, f.opts.Has("flatten"))
+		default:
+			g.printf("m[%s] = %s\n", key, expr)
+		}
+
+		closeIf()
+	}
+
+	g.printf("return m\n}\n\n")
+	return nil
+}
+
+// writeNestedMap writes the conversion of the nested struct expr.
+func (g *generator) writeNestedMap(key, expr string, flatten bool) {
+	if flatten {
+		g.printf("for k, v := range %s.StructsMap() {\nm[k] = v\n}\n", expr)
+		return
+	}
+
+	g.printf("m[%s] = %s.StructsMap()\n", key, expr)
+}
+
+// writeValues writes the StructsValues method of t.
+func (g *generator) writeValues(t *structType) error {
+	g.printf("// StructsValues converts the values of t to a []interface{} like\n")
+	g.printf("// structs.Values.\n")
+	g.printf("func (t *%s) StructsValues() []interface{} {\n", t.name)
+	g.printf("var v []interface{}\n")
+
+	for _, f := range t.converted() {
+		expr := "t." + f.name
+
+		closeIf, err := g.omitEmpty(t, f, expr)
+		if err != nil {
+			return err
+		}
+
+		nested, ptr := g.nested(t, f)
+
+		switch {
+		case f.opts.Has("string"):
+			if g.stringer(f.typ) {
+				g.printf("v = append(v, %s.String())\n", expr)
+			}
+		case nested != nil && ptr:
+			g.printf("if %s != nil {\n", expr)
+			g.printf("v = append(v, %s.StructsValues()...)\n", expr)
+			g.printf("} else {\nv = append(v, %s)\n}\n", expr)
+		case nested != nil:
+			g.printf("v = append(v, %s.StructsValues()...)\n", expr)
+		default:
+			g.printf("v = append(v, %s)\n", expr)
+		}
+
+		closeIf()
+	}
+
+	g.printf("return v\n}\n\n")
+	return nil
+}
+
+// writeNames writes the StructsNames method of t.
+func (g *generator) writeNames(t *structType) {
+	var names []string
+	for _, f := range t.fields {
+		if !f.ignored {
+			names = append(names, strconv.Quote(f.name))
+		}
+	}
+
+	g.printf("// StructsNames returns the field names of t like structs.Names.\n")
+	g.printf("func (t *%s) StructsNames() []string {\n", t.name)
+	g.printf("return []string{")
+	for i, name := range names {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%s", name)
+	}
+	g.printf("}\n}\n\n")
+}
+
+// nested returns the generated type of the field if it's converted as a
+// nested struct. The boolean reports whether the field is a pointer.
+func (g *generator) nested(t *structType, f *field) (*structType, bool) {
+	if f.opts.Has("omitnested") {
+		return nil, false
+	}
+
+	nested, ptr := g.generated(f.typ)
+	if nested != nil {
+		return nested, ptr
+	}
+
+	typ := f.typ
+	if p, ok := typ.(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+
+	if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() == g.pkg {
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			g.warnf("%s.%s: type %s is not generated and kept as is", t.name, f.name, named.Obj().Name())
+		}
+	}
+
+	return nil, false
+}
+
+// stringer reports whether t implements fmt.Stringer.
+func (g *generator) stringer(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "String")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+
+	basic, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && basic.Kind() == types.String
+}
+
+// omitEmpty writes the condition of the "omitempty" option of the field, if
+// it has one. It returns a func which closes the condition.
+func (g *generator) omitEmpty(t *structType, f *field, expr string) (func(), error) {
+	if !f.opts.Has("omitempty") {
+		return func() {}, nil
+	}
+
+	cond, err := g.nonZero(expr, f.typ)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: omitempty: %w", t.name, f.name, err)
+	}
+
+	g.printf("if %s {\n", cond)
+	return func() { g.printf("}\n") }, nil
+}
+
+// nonZero returns the condition which reports whether expr of type t is not
+// its zero value.
+func (g *generator) nonZero(expr string, t types.Type) (string, error) {
+	return g.compareZero(expr, t, "!=")
+}
+
+// isZero returns the condition which reports whether expr of type t is its
+// zero value.
+func (g *generator) isZero(expr string, t types.Type) (string, error) {
+	return g.compareZero(expr, t, "==")
+}
+
+// compareZero returns the condition comparing expr of type t with its zero
+// value using op, which is either "==" or "!=".
+func (g *generator) compareZero(expr string, t types.Type, op string) (string, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return fmt.Sprintf("%s %s \"\"", expr, op), nil
+		case u.Info()&types.IsBoolean != 0:
+			if op == "==" {
+				return "!" + expr, nil
+			}
+			return expr, nil
+		case u.Info()&types.IsNumeric != 0:
+			return fmt.Sprintf("%s %s 0", expr, op), nil
+		case u.Kind() == types.UnsafePointer:
+			return fmt.Sprintf("%s %s nil", expr, op), nil
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Signature, *types.Chan, *types.Interface:
+		return fmt.Sprintf("%s %s nil", expr, op), nil
+	case *types.Struct, *types.Array:
+		if types.Comparable(t) {
+			return fmt.Sprintf("%s %s (%s{})", expr, op, g.typeString(t)), nil
+		}
+	}
+
+	return "", fmt.Errorf("comparing with the zero value is not supported for %s", t)
+}
+
+// zero returns the zero value of t.
+func (g *generator) zero(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct, *types.Array:
+		return g.typeString(t) + "{}"
+	}
+
+	return "nil"
+}
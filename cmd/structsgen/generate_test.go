@@ -0,0 +1,193 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const samplePkg = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fatih/structs"
+)
+
+type Color string
+
+//structs:generate
+type Address struct {
+	Street string ` + "`structs:\"street\"`" + `
+	Zip    int    ` + "`structs:\"zip,omitempty\"`" + `
+}
+
+//structs:generate
+type User struct {
+	Name     string
+	Age      uint8         ` + "`structs:\"age\"`" + `
+	Score    float32       ` + "`structs:\"score,omitempty\"`" + `
+	Password string        ` + "`structs:\"password,sensitive\"`" + `
+	Card     string        ` + "`structs:\"card\" mask:\"card\"`" + `
+	Color    Color         ` + "`structs:\"color\"`" + `
+	Timeout  time.Duration ` + "`structs:\"timeout,string\"`" + `
+	Home     Address       ` + "`structs:\"home\"`" + `
+	Work     *Address      ` + "`structs:\"work\"`" + `
+	Extra    Address       ` + "`structs:\",flatten\"`" + `
+	Tags     []string      ` + "`structs:\"tags,omitempty\"`" + `
+	Ignored  string        ` + "`structs:\"-\"`" + `
+	secret   string
+}
+
+func main() {
+	u := User{Name: "Ann", Age: 30, Password: "x", Card: "4242 4242 4242 4242", Timeout: time.Second, Home: Address{Street: "Main"}}
+	print(u.StructsMap())
+
+	// the sensitive fields are masked like with structs.Map
+	m, want := u.StructsMap(), structs.Map(&u)
+	fmt.Println(m["password"] == want["password"], m["card"] == want["card"])
+	fmt.Println(u.StructsValues())
+	fmt.Println(u.StructsNames())
+
+	var f User
+	err := f.StructsFill(map[string]interface{}{
+		"Name":  "Bob",
+		"age":   json.Number("42"),
+		"score": 1.5,
+		"color": "red",
+		"home":  map[string]interface{}{"street": "Side", "zip": float64(12345)},
+		"work":  map[string]interface{}{"street": "Office"},
+		"tags":  []string{"a"},
+	})
+	fmt.Println(err, f.Name, f.Age, f.Score, f.Color, f.Home, *f.Work, f.Tags)
+
+	err = f.StructsFill(map[string]interface{}{"age": 300, "Name": 1, "tags": nil})
+	fmt.Println(err)
+	fmt.Println(f.Tags == nil)
+}
+
+func print(m map[string]interface{}) {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("%s=%v\n", k, m[k])
+	}
+}
+`
+
+const sampleOutput = `Name=Ann
+age=30
+card=************4242
+color=
+home=map[street:Main]
+password=***
+street=
+timeout=1s
+work=<nil>
+true true
+[Ann 30 x 4242 4242 4242 4242  1s Main <nil> ]
+[Name Age Score Password Card Color Timeout Home Work Extra Tags secret]
+<nil> Bob 42 1.5 red {Side 12345} {Office 0} [a]
+Name: can't set int into string
+Age: 300 overflows uint8
+true
+`
+
+// run generates the code for the main package src with g and the given
+// conversions and returns the output of running it.
+func run(t *testing.T, g *generator, src string, conversions ...string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.load(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.addConversions(conversions); err != nil {
+		t.Fatal(err)
+	}
+
+	gen, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(gen), generatedHeader) {
+		t.Errorf("generated code doesn't start with the header")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "structs_gen.go"), gen, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the generated code imports the structs package from the GOPATH
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gopath := t.TempDir()
+	pkg := filepath.Join(gopath, "src", filepath.FromSlash(structsPath))
+	if err := os.MkdirAll(filepath.Dir(pkg), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(root, pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GO111MODULE=off", "GOPATH="+gopath)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s\n%s", err, out, gen)
+	}
+
+	// loading again skips the generated file
+	if err := newGenerator(g.tagName).load(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return string(out)
+}
+
+func TestGenerate(t *testing.T) {
+	if out := run(t, newGenerator("structs"), samplePkg); out != sampleOutput {
+		t.Errorf("got:\n%s\nwant:\n%s", out, sampleOutput)
+	}
+}
+
+func TestGenerate_UnsupportedOmitEmpty(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\ntype T struct {\n\tF func() `structs:\",omitempty\"`\n\tS struct{ B []byte } `structs:\",omitempty\"`\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := newGenerator("structs")
+	if err := g.load(dir, []string{"T"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.generate(); err == nil || !strings.Contains(err.Error(), "T.S") {
+		t.Errorf("expected an error for T.S, got %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// generatedHeader marks the files written by structsgen. They're skipped
+// when loading a package, so stale generated code doesn't break it.
+const generatedHeader = "// Code generated by structsgen. DO NOT EDIT."
+
+// directive annotates the types to generate if no types are given.
+const directive = "structs:generate"
+
+// structsPath is the import path of the structs package, whose MaskValue
+// masks the sensitive fields in the generated code.
+const structsPath = "github.com/fatih/structs"
+
+// generator holds the state of a single structsgen run.
+type generator struct {
+	tagName string
+
+	// accessors enables the generation of the field accessors.
+	accessors bool
+
+	// validate enables the generation of the validation methods.
+	validate bool
+
+	// builders enables the generation of the test builders.
+	builders bool
+
+	// consts enables the generation of the field key constants.
+	consts bool
+
+	pkg   *types.Package
+	types []*structType
+
+	// conversions are the pairs of types to generate converters for.
+	conversions []conversion
+
+	// imports maps the paths of the packages referenced by the generated
+	// code to their names.
+	imports map[string]string
+
+	// helpers are the names of the helpers used by the generated code.
+	helpers map[string]bool
+
+	warnings []string
+	buf      bytes.Buffer
+}
+
+// newGenerator returns a generator for fields tagged with tagName.
+func newGenerator(tagName string) *generator {
+	return &generator{
+		tagName: tagName,
+		imports: make(map[string]string),
+		helpers: make(map[string]bool),
+	}
+}
+
+// structType is a struct type to generate code for.
+type structType struct {
+	name   string
+	fields []*field
+}
+
+// field is a field of a struct type to generate code for.
+type field struct {
+	name     string
+	key      string
+	typ      types.Type
+	tag      reflect.StructTag
+	opts     tagOptions
+	exported bool
+	ignored  bool
+}
+
+// load parses and type checks the package in dir and collects the struct
+// types with the given names, or the annotated ones if names is empty.
+func (g *generator) load(dir string, names []string) error {
+	fset := token.NewFileSet()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if bytes.HasPrefix(src, []byte(generatedHeader)) {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no Go files in %s", dir)
+	}
+
+	// errors are ignored, as the package may use the methods of the code
+	// which is about to be generated
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+
+	g.pkg, _ = conf.Check(files[0].Name.Name, fset, files, nil)
+
+	if len(names) == 0 {
+		names = annotated(files)
+	}
+
+	for _, name := range names {
+		st, err := g.structType(name)
+		if err != nil {
+			return err
+		}
+
+		g.types = append(g.types, st)
+	}
+
+	return nil
+}
+
+// annotated returns the names of the types annotated with the directive,
+// sorted by name.
+func annotated(files []*ast.File) []string {
+	var names []string
+
+	hasDirective := func(doc *ast.CommentGroup) bool {
+		if doc == nil {
+			return false
+		}
+
+		for _, c := range doc.List {
+			if strings.TrimSpace(strings.TrimPrefix(c.Text, "//")) == directive {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if hasDirective(ts.Doc) || (len(gen.Specs) == 1 && hasDirective(gen.Doc)) {
+					names = append(names, ts.Name.Name)
+				}
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// structType returns the struct type with the given name.
+func (g *generator) structType(name string) (*structType, error) {
+	obj := g.pkg.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("type %s not found", name)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", name)
+	}
+
+	t := &structType{name: name}
+
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+
+		key, opts := parseTag(tag.Get(g.tagName))
+		if key == "" {
+			key = v.Name()
+		}
+
+		t.fields = append(t.fields, &field{
+			name:     v.Name(),
+			key:      key,
+			typ:      v.Type(),
+			tag:      tag,
+			opts:     opts,
+			exported: v.Exported(),
+			ignored:  tag.Get(g.tagName) == "-",
+		})
+	}
+
+	return t, nil
+}
+
+// generated returns the type of t if it's a struct type being generated or
+// a pointer to one. The boolean reports whether t is a pointer.
+func (g *generator) generated(t types.Type) (*structType, bool) {
+	ptr := false
+	if p, ok := t.(*types.Pointer); ok {
+		t, ptr = p.Elem(), true
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != g.pkg {
+		return nil, false
+	}
+
+	for _, st := range g.types {
+		if st.name == named.Obj().Name() {
+			return st, ptr
+		}
+	}
+
+	return nil, false
+}
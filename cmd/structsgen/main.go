@@ -0,0 +1,181 @@
+// Command structsgen generates reflection-free implementations of the Map,
+// Values, Names and Fill functions of the structs package for struct types,
+// for hot paths or platforms such as TinyGo where reflection is expensive or
+// limited. It's meant to be run with go generate:
+//
+//   //go:generate structsgen -type User,Order
+//
+// Without the -type flag, the types whose doc comment contains the
+// structs:generate directive are generated:
+//
+//   //structs:generate
+//   type User struct { ... }
+//
+// For each type T, the following methods are written to structs_gen.go in
+// the package's directory:
+//
+//   func (t *T) StructsMap() map[string]interface{}
+//   func (t *T) StructsValues() []interface{}
+//   func (t *T) StructsNames() []string
+//   func (t *T) StructsFill(m map[string]interface{}) error
+//
+// They follow the same rules as their reflection based counterparts for the
+// field names and the "-", "omitempty", "omitnested", "flatten", "string"
+// and "sensitive" tag options. Nested structs are converted if their type is
+// generated too, otherwise they're kept as is like with "omitnested".
+// Sensitive fields, and fields with a mask tag, are masked with MaskValue of
+// the structs package, so the maskers and the redaction policy apply as with
+// Map. Fields which are only listed in the redaction policy are not masked,
+// as they aren't known at generation time. Fill assigns values of the
+// field's type and converts numbers, it doesn't use sql.Scanner or
+// encoding.TextUnmarshaler. All types of a package must be generated with a
+// single invocation, as the helpers of the generated code are declared once
+// per file.
+//
+// With the -validate flag, a StructsValidate method is written which checks
+// the rules given in the validate tags of the fields and returns the errors
+// of all fields joined with errors.Join:
+//
+//   Name  string   `validate:"required,max=64"`
+//   Age   int      `validate:"min=18,max=130"`
+//   Code  string   `validate:"len=5"`
+//   Role  string   `validate:"oneof=admin user"`
+//   Tags  []string `validate:"min=1"`
+//   Home  *Address `validate:"required"`
+//
+// The rules are:
+//
+//   required  the field must not be its zero value
+//   min=N     numbers must be at least N, strings (in runes), slices,
+//             arrays and maps must have at least N elements
+//   max=N     like min, but at most N
+//   len=N     strings, slices, arrays and maps must have exactly N elements
+//   oneof=a b strings and integers must be one of the space separated values
+//
+// Rules other than required apply to the value a pointer points to, if it's
+// not nil. Nested structs whose types are generated are validated too. An
+// unknown rule, an invalid argument or a rule which doesn't apply to the
+// field's type is an error at generation time.
+//
+// With the -consts flag, a constant holding the key of each field which is
+// not tagged with "-" is written, so that code referring to keys, such as in
+// queries or allow lists, breaks at compile time when a field is renamed:
+//
+//   const (
+//       UserFieldName  = "Name"
+//       UserFieldEmail = "email" // Email string `structs:"email"`
+//   )
+//
+// It's an error if one of the names is already declared in the package.
+//
+// With the -convert flag, converter functions are written for pairs of
+// struct types, such as a DTO and its domain model:
+//
+//   //go:generate structsgen -convert User:UserDTO,UserDTO:User
+//
+//   func UserToUserDTO(src *User) UserDTO
+//   func UserDTOToUser(src *UserDTO) User
+//
+// Fields are matched by their keys, so a field may be renamed as long as its
+// tag keeps the key. Values of identical or convertible types are assigned,
+// nested structs and pointers to them are converted if a converter for their
+// types is generated too. The fields which can't be matched are reported as
+// warnings and listed in the converter's doc comment. The types of
+// conversions don't need to be generated otherwise.
+//
+// With the -accessors flag, a getter and setter is written for each field
+// which is not tagged with "-", along with methods to access the fields by
+// name with a switch instead of reflection:
+//
+//   func (t *T) GetName() string
+//   func (t *T) SetName(v string)
+//   func (t *T) StructsGet(name string) (interface{}, bool)
+//   func (t *T) StructsSet(name string, v interface{}) error
+//
+// StructsSet converts values like StructsFill. It's an error if T already has
+// a method or field with the name of an accessor.
+//
+// With the -builders flag, a fluent builder is written for each type, which
+// makes fixtures in tests short:
+//
+//   u := NewTBuilder().WithName("a").WithAge(3).Build()
+//
+// NewTBuilder starts from the values of the default tags, such as
+// `default:"8080"`, and from zero values otherwise. The With methods of
+// pointer fields take the value to point to. It's an error if TBuilder or
+// NewTBuilder is already declared.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("structsgen: ")
+
+	var (
+		typeNames = flag.String("type", "", "comma separated list of type names; defaults to the types annotated with structs:generate")
+		tagName   = flag.String("tag", "structs", "tag name of the struct fields")
+		output    = flag.String("output", "", "output file name; defaults to <dir>/structs_gen.go")
+		convert   = flag.String("convert", "", "comma separated list of Src:Dst type pairs to generate converters for")
+		consts    = flag.Bool("consts", false, "generate a constant for the key of each field")
+		validate  = flag.Bool("validate", false, "generate a StructsValidate method checking the rules of the validate tags")
+		accessors = flag.Bool("accessors", false, "generate Get and Set methods for each field and a StructsGet and StructsSet method")
+		builders  = flag.Bool("builders", false, "generate a TBuilder type with With methods for each field")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: structsgen [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	g := newGenerator(*tagName)
+	g.accessors = *accessors
+	g.builders = *builders
+	g.validate = *validate
+	g.consts = *consts
+	if err := g.load(dir, names); err != nil {
+		log.Fatal(err)
+	}
+
+	if *convert != "" {
+		if err := g.addConversions(strings.Split(*convert, ",")); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	src, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, w := range g.warnings {
+		log.Print(w)
+	}
+
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, "structs_gen.go")
+	}
+
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import "strings"
+
+// tagOptions contains a slice of tag options
+type tagOptions []string
+
+// Has returns true if the given option is available in tagOptions
+func (t tagOptions) Has(opt string) bool {
+	for _, tagOpt := range t {
+		if tagOpt == opt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTag splits a struct field's tag into its name and a list of options
+// which comes after a name, the same way the structs package does.
+func parseTag(tag string) (string, tagOptions) {
+	res := strings.Split(tag, ",")
+	return res[0], res[1:]
+}
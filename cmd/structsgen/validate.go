@@ -0,0 +1,262 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// validateTagName is the tag name of the validation rules.
+const validateTagName = "validate"
+
+// rule is a validation rule given in a field's validate tag, such as
+// "min=1".
+type rule struct {
+	name string
+	arg  string
+}
+
+// parseRules parses the rules of a validate tag.
+func parseRules(tag string) ([]rule, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	var rules []rule
+	for _, r := range strings.Split(tag, ",") {
+		name, arg, hasArg := strings.Cut(r, "=")
+
+		switch name {
+		case "required":
+			if hasArg {
+				return nil, fmt.Errorf("%s doesn't take an argument", name)
+			}
+		case "min", "max", "len", "oneof":
+			if arg == "" {
+				return nil, fmt.Errorf("%s requires an argument", name)
+			}
+		case "":
+			return nil, errors.New("empty rule")
+		default:
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+
+		rules = append(rules, rule{name: name, arg: arg})
+	}
+
+	return rules, nil
+}
+
+// writeValidate writes the StructsValidate method of t.
+func (g *generator) writeValidate(t *structType) error {
+	g.use("errors", "errors")
+
+	g.printf("// StructsValidate checks the fields of t against the rules given in their\n")
+	g.printf("// validate tags. Errors of all fields are joined with errors.Join.\n")
+	g.printf("func (t *%s) StructsValidate() error {\n", t.name)
+	g.printf("var errs []error\n")
+
+	for _, f := range t.converted() {
+		rules, err := parseRules(f.tag.Get(validateTagName))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.name, f.name, err)
+		}
+
+		if err := g.writeRules(t, f, rules); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.name, f.name, err)
+		}
+	}
+
+	g.printf("return errors.Join(errs...)\n}\n\n")
+	return nil
+}
+
+// writeRules writes the checks of the rules of the field f and the
+// validation of nested structs.
+func (g *generator) writeRules(t *structType, f *field, rules []rule) error {
+	expr, typ := "t."+f.name, f.typ
+
+	report := func(format string, args ...interface{}) {
+		msg := f.name + ": " + fmt.Sprintf(format, args...)
+		g.printf("errs = append(errs, errors.New(%s))\n", strconv.Quote(msg))
+	}
+
+	var checks []rule
+	for _, r := range rules {
+		if r.name != "required" {
+			checks = append(checks, r)
+			continue
+		}
+
+		cond, err := g.isZero(expr, typ)
+		if err != nil {
+			return fmt.Errorf("required: %w", err)
+		}
+
+		g.printf("if %s {\n", cond)
+		report("is required")
+		g.printf("}\n")
+	}
+
+	nested, _ := g.nested(t, f)
+	if len(checks) == 0 && nested == nil {
+		return nil
+	}
+
+	// the rules of pointers apply to the value they point to, if any
+	if p, ok := typ.(*types.Pointer); ok {
+		g.printf("if %s != nil {\n", expr)
+		defer g.printf("}\n")
+
+		expr, typ = "(*"+expr+")", p.Elem()
+	}
+
+	if nested != nil {
+		g.use("fmt", "fmt")
+		g.printf("if err := %s.StructsValidate(); err != nil {\n", expr)
+		g.printf("errs = append(errs, fmt.Errorf(\"%s: %%w\", err))\n}\n", f.name)
+	}
+
+	var min, max *float64
+	for _, r := range checks {
+		if r.name == "oneof" {
+			if err := g.writeOneOf(expr, typ, r.arg, report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		measure, arg, n, isLen, err := g.measure(expr, typ, r)
+		if err != nil {
+			return err
+		}
+
+		what := "must be"
+		if isLen {
+			what = "length must be"
+		}
+
+		switch r.name {
+		case "min":
+			min = &n
+			g.printf("if %s < %s {\n", measure, arg)
+			report("%s at least %s", what, r.arg)
+		case "max":
+			max = &n
+			g.printf("if %s > %s {\n", measure, arg)
+			report("%s at most %s", what, r.arg)
+		case "len":
+			g.printf("if %s != %s {\n", measure, arg)
+			report("%s %s", what, r.arg)
+		}
+
+		g.printf("}\n")
+	}
+
+	if min != nil && max != nil && *min > *max {
+		return fmt.Errorf("min %v is greater than max %v", *min, *max)
+	}
+
+	return nil
+}
+
+// measure returns the expression which is compared by the rule r, which is
+// either the value of a number or the length of a string, slice, array or
+// map, and the rule's argument as a Go literal and a float64. isLen reports
+// whether the length is compared.
+func (g *generator) measure(expr string, t types.Type, r rule) (measure, arg string, n float64, isLen bool, err error) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			g.use("unicode/utf8", "utf8")
+			measure, isLen = "utf8.RuneCountInString("+expr+")", true
+		case u.Info()&types.IsNumeric != 0 && u.Info()&types.IsComplex == 0:
+			arg, n, err = numberArg(u, r.arg)
+			if err != nil {
+				return "", "", 0, false, fmt.Errorf("%s: %w", r.name, err)
+			}
+
+			if r.name == "len" {
+				return "", "", 0, false, fmt.Errorf("len is not supported for %s", t)
+			}
+
+			return expr, arg, n, false, nil
+		}
+	case *types.Slice, *types.Array, *types.Map, *types.Chan:
+		measure, isLen = "len("+expr+")", true
+	}
+
+	if !isLen {
+		return "", "", 0, false, fmt.Errorf("%s is not supported for %s", r.name, t)
+	}
+
+	l, err := strconv.Atoi(r.arg)
+	if err != nil || l < 0 {
+		return "", "", 0, false, fmt.Errorf("%s: invalid length %q", r.name, r.arg)
+	}
+
+	return measure, r.arg, float64(l), true, nil
+}
+
+// numberArg parses the argument of a rule for a number of the kind of b. It
+// returns the argument as a Go literal and as a float64.
+func numberArg(b *types.Basic, arg string) (string, float64, error) {
+	_, bits, _ := numberHelper(b)
+	size := strconv.IntSize
+	if bits != "strconv.IntSize" {
+		size, _ = strconv.Atoi(bits)
+	}
+
+	switch {
+	case b.Info()&types.IsUnsigned != 0:
+		u, err := strconv.ParseUint(arg, 10, size)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid %s %q", b, arg)
+		}
+		return strconv.FormatUint(u, 10), float64(u), nil
+	case b.Info()&types.IsInteger != 0:
+		i, err := strconv.ParseInt(arg, 10, size)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid %s %q", b, arg)
+		}
+		return strconv.FormatInt(i, 10), float64(i), nil
+	}
+
+	f, err := strconv.ParseFloat(arg, size)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid %s %q", b, arg)
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64), f, nil
+}
+
+// writeOneOf writes the check of the oneof rule, whose space separated
+// values are the values allowed for strings and integers.
+func (g *generator) writeOneOf(expr string, t types.Type, arg string, report func(string, ...interface{})) error {
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok || b.Info()&(types.IsString|types.IsInteger) == 0 {
+		return fmt.Errorf("oneof is not supported for %s", t)
+	}
+
+	var conds []string
+	for _, v := range strings.Fields(arg) {
+		lit := strconv.Quote(v)
+		if b.Info()&types.IsInteger != 0 {
+			var err error
+			if lit, _, err = numberArg(b, v); err != nil {
+				return fmt.Errorf("oneof: %w", err)
+			}
+		}
+
+		conds = append(conds, expr+" != "+lit)
+	}
+
+	g.printf("if %s {\n", strings.Join(conds, " && "))
+	report("must be one of %s", strings.Join(strings.Fields(arg), " "))
+	g.printf("}\n")
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validatePkg = `package main
+
+import "fmt"
+
+//structs:generate
+type Address struct {
+	Zip string ` + "`validate:\"len=5\"`" + `
+}
+
+//structs:generate
+type User struct {
+	Name  string   ` + "`validate:\"required,max=5\"`" + `
+	Age   uint8    ` + "`validate:\"min=18,max=130\"`" + `
+	Score *float64 ` + "`validate:\"max=1.5\"`" + `
+	Role  string   ` + "`validate:\"oneof=admin user\"`" + `
+	Level int      ` + "`validate:\"oneof=1 2 3\"`" + `
+	Tags  []string ` + "`validate:\"min=1\"`" + `
+	Home  *Address ` + "`validate:\"required\"`" + `
+	Work  Address
+}
+
+func main() {
+	score := 2.0
+	u := User{Name: "Ünicode", Age: 10, Score: &score, Role: "root", Work: Address{Zip: "1"}}
+	fmt.Println(u.StructsValidate())
+
+	u = User{Name: "Ann", Age: 30, Role: "user", Level: 2, Tags: []string{"a"}, Home: &Address{"12345"}, Work: Address{"54321"}}
+	fmt.Println(u.StructsValidate())
+}
+`
+
+const validateOutput = `Name: length must be at most 5
+Age: must be at least 18
+Score: must be at most 1.5
+Role: must be one of admin user
+Level: must be one of 1 2 3
+Tags: length must be at least 1
+Home: is required
+Work: Zip: length must be 5
+<nil>
+`
+
+func TestGenerate_Validate(t *testing.T) {
+	g := newGenerator("structs")
+	g.validate = true
+
+	if out := run(t, g, validatePkg); out != validateOutput {
+		t.Errorf("got:\n%s\nwant:\n%s", out, validateOutput)
+	}
+}
+
+func TestGenerate_MalformedRules(t *testing.T) {
+	tests := []struct {
+		field string
+		err   string
+	}{
+		{"F int `validate:\"between=1\"`", `unknown rule "between"`},
+		{"F int `validate:\"min\"`", "min requires an argument"},
+		{"F int `validate:\"required=true\"`", "required doesn't take an argument"},
+		{"F uint8 `validate:\"max=300\"`", `max: invalid uint8 "300"`},
+		{"F int `validate:\"min=5,max=1\"`", "min 5 is greater than max 1"},
+		{"F bool `validate:\"min=1\"`", "min is not supported for bool"},
+		{"F int `validate:\"len=1\"`", "len is not supported for int"},
+		{"F string `validate:\"min=-1\"`", `min: invalid length "-1"`},
+		{"F []int `validate:\"oneof=1 2\"`", "oneof is not supported for []int"},
+		{"F int `validate:\"oneof=1 x\"`", `oneof: invalid int "x"`},
+		{"F struct{ B []byte } `validate:\"required\"`", "required: comparing with the zero value"},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		src := "package p\n\ntype T struct {\n\t" + tt.field + "\n}\n"
+		if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		g := newGenerator("structs")
+		g.validate = true
+
+		if err := g.load(dir, []string{"T"}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := g.generate()
+		if err == nil || !strings.Contains(err.Error(), "T.F: "+tt.err) {
+			t.Errorf("%s: got %v want %q", tt.field, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// knownOptions are the options of the structs tag.
+var knownOptions = map[string]bool{
+	"omitempty":  true,
+	"omitnested": true,
+	"flatten":    true,
+	"string":     true,
+	"sensitive":  true,
+	"transient":  true,
+}
+
+// checker checks the struct tags of packages.
+type checker struct {
+	tagName    string
+	sqlTagName string
+
+	fset *token.FileSet
+}
+
+// finding is a mistake found in a struct tag.
+type finding struct {
+	pos token.Position
+	msg string
+}
+
+func (f finding) String() string {
+	return fmt.Sprintf("%s: %s", f.pos, f.msg)
+}
+
+// checkDir checks the struct types of the package in dir, excluding tests.
+func (c *checker) checkDir(dir string) ([]finding, error) {
+	c.fset = token.NewFileSet()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := parser.ParseFile(c.fset, path, src, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	// type errors are ignored, the fields of types which can't be resolved
+	// are skipped
+	conf := types.Config{
+		Importer: importer.ForCompiler(c.fset, "source", nil),
+		Error:    func(error) {},
+	}
+
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf.Check(files[0].Name.Name, c.fset, files, info)
+
+	var findings []finding
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			expr, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+
+			if st, ok := info.Types[expr].Type.(*types.Struct); ok {
+				findings = append(findings, c.checkStruct(st)...)
+			}
+
+			return true
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].pos, findings[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+
+		return a.Offset < b.Offset
+	})
+
+	return findings, nil
+}
+
+// checkStruct checks the tags of the fields of st.
+func (c *checker) checkStruct(st *types.Struct) []finding {
+	var findings []finding
+	report := func(v *types.Var, format string, args ...interface{}) {
+		findings = append(findings, finding{pos: c.fset.Position(v.Pos()), msg: fmt.Sprintf(format, args...)})
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+
+		if !v.Exported() && !v.Embedded() {
+			for _, name := range []string{c.tagName, c.sqlTagName} {
+				if _, ok := tag.Lookup(name); ok {
+					report(v, "%s tag on unexported field %s is ignored", name, v.Name())
+				}
+			}
+			continue
+		}
+
+		value := tag.Get(c.tagName)
+		if value == "-" {
+			continue
+		}
+
+		_, opts := parseTag(value)
+		for _, opt := range opts {
+			if !knownOptions[opt] {
+				report(v, "unknown %s tag option %q on field %s", c.tagName, opt, v.Name())
+			}
+		}
+
+		if opts.Has("flatten") && flattened(v.Type()) == nil {
+			report(v, "flatten option on field %s of non-struct type %s", v.Name(), v.Type())
+		}
+	}
+
+	for _, tagName := range []string{c.tagName, c.sqlTagName} {
+		seen := make(map[string]key)
+		for _, k := range c.keys(st, tagName, "", nil, map[*types.Struct]bool{st: true}) {
+			prev, ok := seen[k.name]
+			if !ok {
+				seen[k.name] = k
+				continue
+			}
+
+			// duplicates within a flattened struct are reported on that
+			// struct itself
+			if prev.field == k.field {
+				continue
+			}
+
+			report(k.field, "duplicate %s key %q of fields %s and %s", tagName, k.name, prev.path, k.path)
+		}
+	}
+
+	return findings
+}
+
+// key is the key of a field of a struct, or of a field of a struct
+// flattened into it.
+type key struct {
+	name string
+
+	// path is the path of the field, such as "Address.Street"
+	path string
+
+	// field is the field of the checked struct the key belongs to
+	field *types.Var
+}
+
+// keys returns the keys of the fields of st for tagName. Fields flattened
+// with the "flatten" option of the structs tag and, for the SQL tag,
+// embedded structs without a name, are replaced by the keys of their fields.
+// Structs in seen are not flattened again, which would be a cycle.
+func (c *checker) keys(st *types.Struct, tagName, prefix string, top *types.Var, seen map[*types.Struct]bool) []key {
+	var keys []key
+
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+
+		value, ok := tag.Lookup(tagName)
+		if value == "-" || (!v.Exported() && !v.Embedded()) {
+			continue
+		}
+
+		field := top
+		if field == nil {
+			field = v
+		}
+
+		name, opts := parseTag(value)
+
+		flatten := tagName == c.tagName && opts.Has("flatten")
+		if tagName == c.sqlTagName && v.Embedded() && name == "" {
+			flatten = true
+		}
+
+		if flatten {
+			if nested := flattened(v.Type()); nested != nil && !seen[nested] {
+				seen[nested] = true
+				keys = append(keys, c.keys(nested, tagName, prefix+v.Name()+".", field, seen)...)
+				delete(seen, nested)
+				continue
+			}
+		}
+
+		if !v.Exported() {
+			continue
+		}
+
+		if !ok || name == "" {
+			name = v.Name()
+		}
+
+		keys = append(keys, key{name: name, path: prefix + v.Name(), field: field})
+	}
+
+	return keys
+}
+
+// flattened returns the struct type of t or of the type t points to, if any.
+func flattened(t types.Type) *types.Struct {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	st, _ := t.Underlying().(*types.Struct)
+	return st
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const checkPkg = `package p
+
+type Address struct {
+	Street string
+	City   string ` + "`structs:\"city\"`" + `
+}
+
+type Base struct {
+	ID int
+}
+
+type User struct {
+	Name    string  ` + "`structs:\"name,omitempy\"`" + `
+	Alias   string  ` + "`structs:\"name\"`" + `
+	City    string  ` + "`structs:\"city\"`" + `
+	Address Address ` + "`structs:\",flatten\"`" + `
+	Count   int     ` + "`structs:\",flatten\"`" + `
+	secret  string  ` + "`structs:\"secret\" db:\"secret\"`" + `
+	Ignored string  ` + "`structs:\"-\"`" + `
+	Base
+	ID int ` + "`db:\"ID\"`" + `
+}
+
+type Loop struct {
+	Name string
+	Next *Loop ` + "`structs:\",flatten\"`" + `
+}
+
+type Dup struct {
+	A, B string ` + "`structs:\"x\"`" + `
+}
+
+type Outer struct {
+	Dup ` + "`structs:\",flatten\"`" + `
+}
+`
+
+func TestCheckDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(checkPkg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &checker{tagName: "structs", sqlTagName: "db"}
+
+	findings, err := c.checkDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, strings.TrimPrefix(f.String(), filepath.Join(dir, "p.go")+":"))
+	}
+
+	want := []string{
+		`13:2: unknown structs tag option "omitempy" on field Name`,
+		`14:2: duplicate structs key "name" of fields Name and Alias`,
+		`16:2: duplicate structs key "city" of fields City and Address.City`,
+		`17:2: flatten option on field Count of non-struct type int`,
+		`18:2: structs tag on unexported field secret is ignored`,
+		`18:2: db tag on unexported field secret is ignored`,
+		`21:2: duplicate db key "ID" of fields Base.ID and ID`,
+		`30:5: duplicate structs key "x" of fields A and B`,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestExpand(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"a", "a/b", "a/testdata", "a/.hidden", "c"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if d != "c" {
+			if err := os.WriteFile(filepath.Join(dir, d, "x.go"), []byte("package x\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	dirs, err := expand([]string{dir + "/...", filepath.Join(dir, "a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "a"), filepath.Join(dir, "a/b")}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("got %q want %q", dirs, want)
+	}
+}
@@ -0,0 +1,126 @@
+// Command structsvet reports mistakes in the struct tags used by the structs
+// package, which would otherwise only show up at runtime:
+//
+//   - duplicate keys, including the keys of fields flattened with the
+//     "flatten" option or embedded structs flattened by the SQL helpers
+//   - unknown options, such as a misspelled "omitempty"
+//   - tags on unexported fields, which are ignored
+//
+// It's used like go vet, with the directories of the packages to check:
+//
+//   structsvet ./...
+//
+// Findings are printed as file:line:column: message and the exit status is 1
+// if there are any. Packages are type checked from source with the standard
+// library only, so embedded and flattened types of packages which can't be
+// imported are skipped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("structsvet: ")
+
+	tagName := flag.String("tag", "structs", "tag name of the struct fields")
+	sqlTagName := flag.String("sqltag", "db", "tag name used by the SQL helpers")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: structsvet [flags] [directories]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	dirs, err := expand(patterns)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c := &checker{tagName: *tagName, sqlTagName: *sqlTagName}
+
+	var findings []finding
+	for _, dir := range dirs {
+		f, err := c.checkDir(dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		findings = append(findings, f...)
+	}
+
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+// expand returns the directories of the given patterns, where a pattern
+// ending in "/..." matches the directory and all its subdirectories with Go
+// files. Directories named testdata or vendor and hidden ones are skipped.
+func expand(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	var dirs []string
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, pattern := range patterns {
+		root, ok := strings.CutSuffix(pattern, "/...")
+		if !ok {
+			add(filepath.Clean(pattern))
+			continue
+		}
+
+		if root == "" {
+			root = "/"
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				return nil
+			}
+
+			name := d.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+
+			if files, _ := filepath.Glob(filepath.Join(path, "*.go")); len(files) > 0 {
+				add(filepath.Clean(path))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
@@ -0,0 +1,193 @@
+package structs
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// Source is a layer of configuration for Load, such as defaults, a config
+// file, environment variables or command-line flags.
+type Source struct {
+	// Name identifies the source in the origins returned by Load and in
+	// errors, such as "env".
+	Name string
+
+	// Load sets fields of s and returns the paths of the fields it set,
+	// such as "DB.User".
+	Load func(s *Struct) ([]string, error)
+}
+
+// Load fills s from the given sources in order, so the values of later
+// sources take precedence over earlier ones. The usual order is:
+//
+//   origins, err := structs.New(&cfg).Load(
+//       structs.DefaultsSource(),
+//       structs.MapSource("config.json", file),
+//       structs.EnvSource("APP_"),
+//       structs.FlagSource(flag.CommandLine, ""),
+//   )
+//
+// It returns the name of the source which supplied the value of each field
+// that was set, keyed by the field's path, such as "DB.User". Fields which
+// are not set by any source keep their value and are not included. It
+// returns an error if s was not created from a pointer or if a source fails,
+// in which case the sources after it are not loaded.
+func (s *Struct) Load(sources ...Source) (map[string]string, error) {
+	if !s.value.CanAddr() {
+		return nil, errNotStructPtr
+	}
+
+	origins := make(map[string]string)
+
+	for _, src := range sources {
+		paths, err := src.Load(s)
+		if err != nil {
+			return origins, fmt.Errorf("%s: %w", src.Name, err)
+		}
+
+		for _, path := range paths {
+			origins[path] = src.Name
+		}
+	}
+
+	return origins, nil
+}
+
+// DefaultsSource returns the source named "defaults" which sets the fields
+// from their default tags, as used by LoadEnv. Unlike ApplyDefaults, it sets
+// fields which are not zero too.
+func DefaultsSource() Source {
+	src := defaultsSource(false)
+
+	return Source{
+		Name: "defaults",
+		Load: func(s *Struct) ([]string, error) {
+			return src.loadPrefix(s, "")
+		},
+	}
+}
+
+// EnvSource returns the source named "env" which sets the fields from
+// environment variables starting with prefix, as LoadEnv does. Unlike
+// LoadEnv, it doesn't use the default tags or check the "required" option,
+// as these values may be supplied by other sources.
+func EnvSource(prefix string) Source {
+	src := envSource(false)
+
+	return Source{
+		Name: "env",
+		Load: func(s *Struct) ([]string, error) {
+			return src.loadPrefix(s, prefix)
+		},
+	}
+}
+
+// FlagSource returns the source named "flags" which sets the fields from the
+// flags of fs which were set on the command line, as parsed by fs.Parse. The
+// names of the flags are derived as in RegisterFlags, but the flags don't
+// need to be defined by it.
+func FlagSource(fs *flag.FlagSet, prefix string) Source {
+	src := stringSource{
+		name: flagName,
+		sep:  "-",
+		lookup: func(_ reflect.StructField, name string) (string, bool, error) {
+			var f *flag.Flag
+			fs.Visit(func(visited *flag.Flag) {
+				if visited.Name == name {
+					f = visited
+				}
+			})
+
+			if f == nil {
+				return "", false, nil
+			}
+
+			return f.Value.String(), true, nil
+		},
+		sigil: "-",
+	}
+
+	return Source{
+		Name: "flags",
+		Load: func(s *Struct) ([]string, error) {
+			return src.loadPrefix(s, prefix)
+		},
+	}
+}
+
+// MapSource returns a source with the given name, such as the config file's
+// name, which fills the fields from m as Fill does.
+func MapSource(name string, m map[string]interface{}) Source {
+	return Source{
+		Name: name,
+		Load: func(s *Struct) ([]string, error) {
+			if err := s.Fill(m); err != nil {
+				return nil, err
+			}
+
+			return s.mapPaths(m), nil
+		},
+	}
+}
+
+// loadPrefix sets the fields of s from src, where the names of the values
+// start with prefix.
+func (src stringSource) loadPrefix(s *Struct, prefix string) ([]string, error) {
+	var errs FieldErrors
+	paths := s.loadStrings(src, prefix, &errs)
+
+	return paths, errs.err()
+}
+
+// mapPaths returns the paths of the fields of s which are filled from m by
+// Fill.
+func (s *Struct) mapPaths(m map[string]interface{}) []string {
+	var paths []string
+
+	for _, field := range s.structFields() {
+		v := s.value.FieldByName(field.Name)
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			continue
+		}
+
+		name, tagOpts := s.key(field)
+
+		nested := func(m map[string]interface{}) {
+			for _, path := range s.nestedStruct(v.Interface()).mapPaths(m) {
+				paths = append(paths, field.Name+"."+path)
+			}
+		}
+
+		if tagOpts.Has("flatten") {
+			nested(m)
+			continue
+		}
+
+		val, ok := m[name]
+		if !ok {
+			continue
+		}
+
+		if nm, ok := val.(map[string]interface{}); ok && indirectType(field.Type).Kind() == reflect.Struct {
+			nested(nm)
+			continue
+		}
+
+		paths = append(paths, field.Name)
+	}
+
+	return paths
+}
+
+// Load fills the struct pointed to by dst from the given sources. For more
+// info refer to Struct types Load() method. It returns an error if dst is
+// not a pointer to struct.
+func Load(dst interface{}, sources ...Source) (map[string]string, error) {
+	s, err := structPtr(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Load(sources...)
+}
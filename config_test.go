@@ -0,0 +1,102 @@
+package structs
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type configDatabase struct {
+	Host string `default:"localhost"`
+	User string `structs:"user"`
+}
+
+type config struct {
+	Port    int           `default:"8080"`
+	Timeout time.Duration `default:"5s"`
+	Debug   bool
+	Name    string
+	DB      configDatabase `structs:"db"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("APP_TIMEOUT", "10s")
+	t.Setenv("APP_DB_USER", "env-user")
+
+	var c config
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := RegisterFlags(fs, "", &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Parse([]string{"-debug", "-db-user", "flag-user"}); err != nil {
+		t.Fatal(err)
+	}
+
+	file := map[string]interface{}{
+		"Port":    float64(9090),
+		"Timeout": float64(time.Second),
+		"db":      map[string]interface{}{"user": "file-user"},
+	}
+
+	origins, err := Load(&c,
+		DefaultsSource(),
+		MapSource("config.json", file),
+		EnvSource("APP_"),
+		FlagSource(fs, ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := config{
+		Port:    9090,
+		Timeout: 10 * time.Second,
+		Debug:   true,
+		DB:      configDatabase{Host: "localhost", User: "flag-user"},
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+
+	wantOrigins := map[string]string{
+		"Port":    "config.json",
+		"Timeout": "env",
+		"Debug":   "flags",
+		"DB.Host": "defaults",
+		"DB.User": "flags",
+	}
+
+	if !reflect.DeepEqual(origins, wantOrigins) {
+		t.Errorf("origins: got %v want %v", origins, wantOrigins)
+	}
+}
+
+func TestLoad_Error(t *testing.T) {
+	t.Setenv("PORT", "http")
+
+	var c config
+	origins, err := Load(&c, DefaultsSource(), EnvSource(""), MapSource("file", nil))
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) || fieldErrs[0].Path != "Port" {
+		t.Fatalf("expected an error for Port, got %v", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "env: ") {
+		t.Errorf("expected the source's name in %q", err)
+	}
+
+	if origins["Port"] != "defaults" {
+		t.Errorf("origins: got %v", origins)
+	}
+
+	if _, err := Load(c); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package structs
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// isNumberKind returns true for the int, uint and float kinds.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// isNumber returns true if v is a json.Number or has a number kind.
+func isNumber(v reflect.Value) bool {
+	return v.Type() == jsonNumberType || isNumberKind(v.Kind())
+}
+
+// convertNumber converts the number n, a json.Number or a value of a number
+// kind, to a value of type t, which must have a number kind. It returns an
+// error if n doesn't fit into t, such as 300 into an int8, -1 into an uint or
+// 1.5 into an int.
+func convertNumber(n reflect.Value, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+
+	// normalize n into one of int64, uint64 or float64
+	var num interface{}
+	switch {
+	case n.Type() == jsonNumberType:
+		str := n.String()
+		if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+			num = i
+		} else if u, err := strconv.ParseUint(str, 10, 64); err == nil {
+			num = u
+		} else if f, err := strconv.ParseFloat(str, 64); err == nil {
+			num = f
+		} else {
+			return out, fmt.Errorf("%w: invalid number %q", ErrTypeMismatch, str)
+		}
+	case n.CanInt():
+		num = n.Int()
+	case n.CanUint():
+		num = n.Uint()
+	case n.CanFloat():
+		num = n.Float()
+	default:
+		return out, fmt.Errorf("%w: %s is not a number", ErrTypeMismatch, n.Type())
+	}
+
+	overflow := fmt.Errorf("%w: %v overflows %s", ErrTypeMismatch, num, t)
+
+	switch {
+	case out.CanInt():
+		var i int64
+		switch num := num.(type) {
+		case int64:
+			i = num
+		case uint64:
+			if num > math.MaxInt64 {
+				return out, overflow
+			}
+			i = int64(num)
+		case float64:
+			if num != math.Trunc(num) {
+				return out, fmt.Errorf("%w: %v has a fractional part", ErrTypeMismatch, num)
+			}
+			if num < math.MinInt64 || num >= math.MaxInt64 {
+				return out, overflow
+			}
+			i = int64(num)
+		}
+
+		if out.OverflowInt(i) {
+			return out, overflow
+		}
+
+		out.SetInt(i)
+	case out.CanUint():
+		var u uint64
+		switch num := num.(type) {
+		case int64:
+			if num < 0 {
+				return out, overflow
+			}
+			u = uint64(num)
+		case uint64:
+			u = num
+		case float64:
+			if num != math.Trunc(num) {
+				return out, fmt.Errorf("%w: %v has a fractional part", ErrTypeMismatch, num)
+			}
+			if num < 0 || num >= math.MaxUint64 {
+				return out, overflow
+			}
+			u = uint64(num)
+		}
+
+		if out.OverflowUint(u) {
+			return out, overflow
+		}
+
+		out.SetUint(u)
+	case out.CanFloat():
+		var f float64
+		switch num := num.(type) {
+		case int64:
+			f = float64(num)
+		case uint64:
+			f = float64(num)
+		case float64:
+			f = num
+		}
+
+		if out.OverflowFloat(f) {
+			return out, overflow
+		}
+
+		out.SetFloat(f)
+	default:
+		return out, fmt.Errorf("%w: %s is not a number", ErrTypeMismatch, t)
+	}
+
+	return out, nil
+}
+
+// textLeaf returns the value to use for val if val is a struct (or a pointer
+// to struct) implementing encoding.TextMarshaler. Such structs are never
+// exploded into their fields: if they have exported fields their text form is
+// returned, otherwise val is returned as is, like for time.Time. The boolean
+// is false if val is not such a struct.
+func textLeaf(val reflect.Value) (interface{}, bool) {
+	v := reflect.ValueOf(val.Interface())
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, false
+	}
+
+	m, ok := v.Interface().(encoding.TextMarshaler)
+	if !ok {
+		return nil, false
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	exported := false
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath == "" {
+			exported = true
+			break
+		}
+	}
+
+	if !exported {
+		return val.Interface(), true
+	}
+
+	text, err := m.MarshalText()
+	if err != nil {
+		return val.Interface(), true
+	}
+
+	return string(text), true
+}
+
+// unmarshalText sets v from the given string if v implements
+// encoding.TextUnmarshaler. The boolean is false if it doesn't.
+func unmarshalText(v reflect.Value, val interface{}) (bool, error) {
+	str, ok := val.(string)
+	if !ok || !v.CanAddr() {
+		return false, nil
+	}
+
+	u, ok := v.Addr().Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return false, nil
+	}
+
+	return true, u.UnmarshalText([]byte(str))
+}
+
+// MarshalerPolicy defines how nested structs implementing json.Marshaler are
+// converted.
+type MarshalerPolicy int
+
+const (
+	// MarshalerRecurse converts the struct like any other struct, ignoring
+	// its MarshalJSON method. This is the default.
+	MarshalerRecurse MarshalerPolicy = iota
+
+	// MarshalerJSON emits the output of MarshalJSON as a json.RawMessage,
+	// which is embedded as is when the result is encoded to JSON.
+	MarshalerJSON
+
+	// MarshalerRaw emits the struct as is, without recursing into it.
+	MarshalerRaw
+)
+
+// jsonLeaf returns the value to use for val according to the marshaler
+// policy of s if val is a struct (or a pointer to struct) implementing
+// json.Marshaler. The boolean is false if val is not such a struct or if the
+// policy is MarshalerRecurse.
+func (s *Struct) jsonLeaf(val reflect.Value) (interface{}, bool) {
+	if s.JSONMarshaler == MarshalerRecurse {
+		return nil, false
+	}
+
+	v := reflect.ValueOf(val.Interface())
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, false
+	}
+
+	m, ok := v.Interface().(json.Marshaler)
+	if !ok || reflect.Indirect(v).Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	if s.JSONMarshaler == MarshalerRaw {
+		return val.Interface(), true
+	}
+
+	b, err := m.MarshalJSON()
+	if err != nil {
+		panic(err)
+	}
+
+	return json.RawMessage(b), true
+}
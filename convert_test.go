@@ -0,0 +1,196 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestField_Set_Number(t *testing.T) {
+	type Numbers struct {
+		Int      int
+		Int8     int8
+		Uint     uint
+		Uint16   uint16
+		Float32  float32
+		Float64  float64
+		Duration time.Duration
+	}
+
+	tests := []struct {
+		field string
+		value interface{}
+		want  interface{}
+	}{
+		{"Int", float64(42), 42},
+		{"Int", json.Number("42"), 42},
+		{"Int", json.Number("1e3"), 1000},
+		{"Int8", int64(-128), int8(-128)},
+		{"Uint", json.Number("18446744073709551615"), uint(math.MaxUint64)},
+		{"Uint16", float64(65535), uint16(65535)},
+		{"Float32", json.Number("1.5"), float32(1.5)},
+		{"Float64", 7, float64(7)},
+		{"Duration", int64(time.Second), time.Second},
+	}
+
+	for _, test := range tests {
+		s := New(&Numbers{})
+		f := s.Field(test.field)
+
+		if err := f.Set(test.value); err != nil {
+			t.Errorf("Set(%#v) into %s should not fail: %s", test.value, test.field, err)
+			continue
+		}
+
+		if f.Value() != test.want {
+			t.Errorf("Set(%#v) into %s should be %#v, got: %#v", test.value, test.field, test.want, f.Value())
+		}
+	}
+
+	failing := []struct {
+		field string
+		value interface{}
+	}{
+		{"Int", float64(1.5)},
+		{"Int8", float64(300)},
+		{"Int8", json.Number("-129")},
+		{"Uint", -1},
+		{"Uint16", json.Number("1.5")},
+		{"Float32", math.MaxFloat64},
+		{"Int", json.Number("abc")},
+	}
+
+	for _, test := range failing {
+		s := New(&Numbers{})
+
+		err := s.Field(test.field).Set(test.value)
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Set(%#v) into %s should return ErrTypeMismatch, got: %v", test.value, test.field, err)
+		}
+	}
+}
+
+// textID is a struct with exported fields implementing encoding.TextMarshaler.
+type textID struct {
+	Prefix string
+	Number int
+}
+
+func (id textID) MarshalText() ([]byte, error) {
+	return []byte(id.Prefix + "-" + strconv.Itoa(id.Number)), nil
+}
+
+func (id *textID) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), "-", 2)
+	if len(parts) != 2 {
+		return errors.New("invalid id")
+	}
+
+	n, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return err
+	}
+
+	id.Prefix, id.Number = parts[0], n
+	return nil
+}
+
+func TestTextMarshaler(t *testing.T) {
+	type Order struct {
+		ID      textID
+		Parent  *textID
+		Addr    netip.Addr
+		Created time.Time
+	}
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	o := Order{
+		ID:      textID{"ord", 1},
+		Parent:  &textID{"ord", 0},
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Created: created,
+	}
+
+	m := Map(o)
+
+	if m["ID"] != "ord-1" || m["Parent"] != "ord-0" {
+		t.Errorf("Map should emit the text form of TextMarshalers, got: %#v", m)
+	}
+
+	if m["Addr"] != o.Addr || m["Created"] != created {
+		t.Errorf("Map should keep TextMarshalers without exported fields as is, got: %#v", m)
+	}
+
+	v := Values(o)
+	if !reflect.DeepEqual(v, []interface{}{"ord-1", "ord-0", o.Addr, created}) {
+		t.Errorf("Values should treat TextMarshalers as leaves, got: %#v", v)
+	}
+
+	var out Order
+	err := Fill(map[string]interface{}{
+		"ID":      "ord-1",
+		"Parent":  "ord-0",
+		"Addr":    "10.0.0.1",
+		"Created": "2020-01-02T03:04:05Z",
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, o) {
+		t.Errorf("Fill should use TextUnmarshalers, got: %+v", out)
+	}
+}
+
+// jsonMoney is a struct with exported fields implementing json.Marshaler.
+type jsonMoney struct {
+	Cents    int64
+	Currency string
+}
+
+func (m jsonMoney) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%d.%02d %s"`, m.Cents/100, m.Cents%100, m.Currency)), nil
+}
+
+func TestJSONMarshaler(t *testing.T) {
+	type Order struct {
+		Price jsonMoney
+	}
+
+	o := Order{Price: jsonMoney{Cents: 1050, Currency: "EUR"}}
+
+	if m := Map(o); !reflect.DeepEqual(m["Price"], map[string]interface{}{"Cents": int64(1050), "Currency": "EUR"}) {
+		t.Errorf("Map should recurse into json.Marshalers by default, got: %#v", m["Price"])
+	}
+
+	s := New(o)
+	s.JSONMarshaler = MarshalerJSON
+
+	m := s.Map()
+	if !reflect.DeepEqual(m["Price"], json.RawMessage(`"10.50 EUR"`)) {
+		t.Errorf("Map should emit the marshaled form with MarshalerJSON, got: %#v", m["Price"])
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"Price":"10.50 EUR"}` {
+		t.Errorf("Map output should encode to the marshaled form, got: %s", b)
+	}
+
+	s = New(o)
+	s.JSONMarshaler = MarshalerRaw
+
+	if v := s.Values(); !reflect.DeepEqual(v, []interface{}{o.Price}) {
+		t.Errorf("Values should emit the raw value with MarshalerRaw, got: %#v", v)
+	}
+}
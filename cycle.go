@@ -0,0 +1,139 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CyclePolicy defines how pointer cycles, such as a child pointing back to
+// its parent, are handled when converting a struct.
+type CyclePolicy int
+
+const (
+	// CycleNil replaces a pointer that points back to a struct which is
+	// already being converted with nil. This is the default.
+	CycleNil CyclePolicy = iota
+
+	// CycleSkip leaves out a pointer that points back to a struct which is
+	// already being converted.
+	CycleSkip
+
+	// CycleError panics with an error wrapping ErrCycle. MapStrict returns
+	// the error instead.
+	CycleError
+)
+
+// visit is a pointer that is being converted. The type is part of the key, as
+// a pointer to a struct and a pointer to its first field have the same
+// address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+
+	// other is the pointer ptr is compared with by Diff, if any.
+	other uintptr
+}
+
+// skipped is returned by nested for values that are left out due to
+// CycleSkip.
+type skipped struct{}
+
+// callState is the state of a top level call such as Map, which is shared
+// by the nested structs it converts.
+type callState struct {
+	// visiting holds the pointers being converted.
+	visiting map[visit]bool
+
+	// skipVolatile leaves the fields tagged with `etag:"-"` out of the
+	// output of Map, see ETag.
+	skipVolatile bool
+}
+
+// track returns the *Struct used by a top level call such as Map to track
+// the pointers being converted. Nested calls share the tracking of their
+// parent. s itself is left as is, so concurrent calls on the same *Struct
+// don't interfere.
+func (s *Struct) track() *Struct {
+	if s.state != nil {
+		return s
+	}
+
+	return s.withState(&callState{})
+}
+
+// withState returns a copy of s with the given state of a top level call.
+func (s *Struct) withState(state *callState) *Struct {
+	state.visiting = make(map[visit]bool)
+
+	if v := reflect.ValueOf(s.raw); v.Kind() == reflect.Ptr {
+		state.visiting[visit{ptr: v.Pointer(), typ: v.Type()}] = true
+	}
+
+	c := *s
+	c.state = state
+	return &c
+}
+
+// enter marks the pointer v as being converted. It returns false if v is
+// already being converted, which means there's a cycle. Values other than
+// non-nil pointers are ignored.
+func (s *Struct) enter(v reflect.Value) bool {
+	if v.Kind() != reflect.Ptr || v.IsNil() || s.state == nil {
+		return true
+	}
+
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if s.state.visiting[key] {
+		return false
+	}
+
+	s.state.visiting[key] = true
+	return true
+}
+
+// leave unmarks the pointer v after it was converted.
+func (s *Struct) leave(v reflect.Value) {
+	if v.Kind() != reflect.Ptr || v.IsNil() || s.state == nil {
+		return
+	}
+
+	delete(s.state.visiting, visit{ptr: v.Pointer(), typ: v.Type()})
+}
+
+// enterPair marks the pointers a and b, which are of the same type, as being
+// compared by Diff. It returns false if they're already being compared.
+func (s *Struct) enterPair(a, b reflect.Value) bool {
+	if s.state == nil {
+		return true
+	}
+
+	key := visit{ptr: a.Pointer(), typ: a.Type(), other: b.Pointer()}
+	if s.state.visiting[key] {
+		return false
+	}
+
+	s.state.visiting[key] = true
+	return true
+}
+
+// leavePair unmarks the pointers a and b after they were compared.
+func (s *Struct) leavePair(a, b reflect.Value) {
+	if s.state == nil {
+		return
+	}
+
+	delete(s.state.visiting, visit{ptr: a.Pointer(), typ: a.Type(), other: b.Pointer()})
+}
+
+// cycle returns the replacement for the cyclic pointer v according to the
+// cycle policy of s.
+func (s *Struct) cycle(v reflect.Value) interface{} {
+	switch s.OnCycle {
+	case CycleSkip:
+		return skipped{}
+	case CycleError:
+		panic(fmt.Errorf("%w: %s", ErrCycle, v.Type()))
+	}
+
+	return nil
+}
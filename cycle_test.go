@@ -0,0 +1,129 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type cycleNode struct {
+	Name     string
+	Parent   *cycleNode
+	Children []*cycleNode
+}
+
+func newCycleTree() *cycleNode {
+	root := &cycleNode{Name: "root"}
+	child := &cycleNode{Name: "child", Parent: root}
+	root.Children = []*cycleNode{child}
+	return root
+}
+
+func TestMap_Cycle(t *testing.T) {
+	m := Map(newCycleTree())
+
+	want := map[string]interface{}{
+		"Name":   "root",
+		"Parent": (*cycleNode)(nil),
+		"Children": []interface{}{
+			map[string]interface{}{
+				"Name":     "child",
+				"Parent":   nil,
+				"Children": []interface{}{},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Map should replace cycles with nil, got: %#v", m)
+	}
+
+	s := New(newCycleTree())
+	s.OnCycle = CycleSkip
+	m = s.Map()
+
+	child := m["Children"].([]interface{})[0].(map[string]interface{})
+	if _, ok := child["Parent"]; ok {
+		t.Errorf("Map should skip cycles with CycleSkip, got: %#v", child)
+	}
+
+	s = New(newCycleTree())
+	s.OnCycle = CycleError
+
+	if _, err := s.MapStrict(); !errors.Is(err, ErrCycle) {
+		t.Errorf("MapStrict should return ErrCycle, got: %v", err)
+	}
+}
+
+func TestMap_SharedPointer(t *testing.T) {
+	type Leaf struct {
+		Name string
+	}
+
+	type Pair struct {
+		A, B *Leaf
+	}
+
+	leaf := &Leaf{Name: "shared"}
+	s := New(&Pair{A: leaf, B: leaf})
+	s.OnCycle = CycleError
+
+	m := s.Map()
+	if !reflect.DeepEqual(m["A"], m["B"]) || m["B"] == nil {
+		t.Errorf("Map should convert shared pointers without a cycle, got: %#v", m)
+	}
+}
+
+func TestValues_Cycle(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	a := &Node{Name: "a"}
+	b := &Node{Name: "b", Next: a}
+	a.Next = b
+
+	v := Values(a)
+	if !reflect.DeepEqual(v, []interface{}{"a", "b", nil}) {
+		t.Errorf("Values should replace cycles with nil, got: %#v", v)
+	}
+
+	s := New(a)
+	s.OnCycle = CycleError
+
+	if _, err := s.ValuesStrict(); !errors.Is(err, ErrCycle) {
+		t.Errorf("ValuesStrict should return ErrCycle, got: %v", err)
+	}
+
+	if IsZero(a) {
+		t.Error("IsZero should be false for a cyclic list")
+	}
+
+	if HasZero(a) {
+		t.Error("HasZero should be false for a cyclic list")
+	}
+}
+
+func TestMap_CycleConcurrent(t *testing.T) {
+	s := New(newCycleTree())
+	want := s.Map()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if m := s.Map(); !reflect.DeepEqual(m, want) {
+					t.Errorf("Map should be safe for concurrent use, got: %#v", m)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
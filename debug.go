@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DebugValue prints a struct with the keys of Map and its sensitive fields
+// masked. It's returned by Debug.
+type DebugValue struct {
+	s *Struct
+}
+
+// Debug returns a wrapper of s which prints it with the keys of Map and its
+// sensitive fields masked, so debug prints and panics don't disclose
+// credentials:
+//
+//   fmt.Printf("%v\n", structs.Debug(u))  // {Name:gopher password:***}
+//   fmt.Printf("%#v\n", structs.Debug(u)) // main.User{Name:"gopher", password:"***"}
+//
+// It panics if s's kind is not struct.
+func Debug(s interface{}) DebugValue {
+	return DebugValue{s: New(s)}
+}
+
+// Debug returns a wrapper of s which prints it with its sensitive fields
+// masked. For more info refer to the package level Debug function.
+func (s *Struct) Debug() DebugValue {
+	return DebugValue{s: s}
+}
+
+// Format implements fmt.Formatter. The %#v verb prints the value in Go
+// syntax, all other verbs as with %+v.
+func (d DebugValue) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, d.GoString())
+		return
+	}
+
+	fmt.Fprint(f, d.String())
+}
+
+// String implements fmt.Stringer.
+func (d DebugValue) String() string {
+	var b strings.Builder
+	d.write(&b, false)
+	return b.String()
+}
+
+// GoString implements fmt.GoStringer.
+func (d DebugValue) GoString() string {
+	var b strings.Builder
+	b.WriteString(d.s.value.Type().String())
+	d.write(&b, true)
+	return b.String()
+}
+
+// write writes the fields of the struct to b in the order of the struct.
+func (d DebugValue) write(b *strings.Builder, goSyntax bool) {
+	b.WriteByte('{')
+
+	i := 0
+	for key, val := range d.s.All() {
+		if i > 0 {
+			writeSep(b, goSyntax)
+		}
+
+		writeDebug(b, key, val, goSyntax)
+		i++
+	}
+
+	b.WriteByte('}')
+}
+
+// writeDebug writes the key and value of the output of Map to b. Nested maps
+// are written with sorted keys.
+func writeDebug(b *strings.Builder, key string, val interface{}, goSyntax bool) {
+	b.WriteString(key)
+	b.WriteByte(':')
+
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		if goSyntax {
+			fmt.Fprintf(b, "%#v", val)
+		} else {
+			fmt.Fprintf(b, "%v", val)
+		}
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			writeSep(b, goSyntax)
+		}
+
+		writeDebug(b, k, m[k], goSyntax)
+	}
+	b.WriteByte('}')
+}
+
+// writeSep writes the separator between two fields, which is the same as the
+// one of the fmt package.
+func writeSep(b *strings.Builder, goSyntax bool) {
+	if goSyntax {
+		b.WriteString(", ")
+	} else {
+		b.WriteByte(' ')
+	}
+}
@@ -0,0 +1,47 @@
+package structs
+
+import (
+	"fmt"
+	"testing"
+)
+
+type debugAddress struct {
+	Zip  string
+	City string
+}
+
+type debugUser struct {
+	Name     string
+	Password string `structs:"password,sensitive"`
+	Age      int
+	Address  debugAddress
+}
+
+func TestDebug(t *testing.T) {
+	u := debugUser{
+		Name:     "gopher",
+		Password: "hunter2",
+		Age:      12,
+		Address:  debugAddress{"10115", "Berlin"},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%v", "{Name:gopher password:*** Age:12 Address:{City:Berlin Zip:10115}}"},
+		{"%+v", "{Name:gopher password:*** Age:12 Address:{City:Berlin Zip:10115}}"},
+		{"%s", "{Name:gopher password:*** Age:12 Address:{City:Berlin Zip:10115}}"},
+		{"%#v", `structs.debugUser{Name:"gopher", password:"***", Age:12, Address:{City:"Berlin", Zip:"10115"}}`},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, Debug(u)); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.format, got, tt.want)
+		}
+	}
+
+	if got := fmt.Sprint(Debug(&u)); got != tests[0].want {
+		t.Errorf("pointer: got %s", got)
+	}
+}
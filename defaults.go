@@ -0,0 +1,55 @@
+package structs
+
+import "reflect"
+
+// ApplyDefaults sets the fields of s which are zero to the value given in
+// their default tag, parsed as in LoadEnv:
+//
+//   type Config struct {
+//       Port    int           `default:"8080"`
+//       Timeout time.Duration `default:"30s"`
+//       Hosts   []string      `default:"a,b"`
+//       DB      *Database     // its fields' default tags are applied too
+//   }
+//
+// Fields of nested structs are set too, nil pointers to them are only
+// allocated if at least one of their fields has a default. It returns an
+// error if s was not created from a pointer. Defaults which can't be parsed
+// are reported with a FieldErrors.
+func (s *Struct) ApplyDefaults() error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	_, err := defaultsSource(true).loadPrefix(s, "")
+	return err
+}
+
+// defaultsSource returns the source of the default tags, restricted to the
+// fields which are zero if zeroOnly is true.
+func defaultsSource(zeroOnly bool) stringSource {
+	return stringSource{
+		name: func(field reflect.StructField, prefix string) (string, tagOptions, bool) {
+			return prefix + field.Name, nil, true
+		},
+		sep: ".",
+		lookup: func(field reflect.StructField, _ string) (string, bool, error) {
+			str, ok := field.Tag.Lookup(DefaultValueTagName)
+			return str, ok, nil
+		},
+		zeroOnly: zeroOnly,
+		sigil:    "default of ",
+	}
+}
+
+// ApplyDefaults sets the zero fields of the struct pointed to by dst to
+// their defaults. For more info refer to Struct types ApplyDefaults()
+// method. It returns an error if dst is not a pointer to struct.
+func ApplyDefaults(dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.ApplyDefaults()
+}
@@ -0,0 +1,63 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type defaultsDatabase struct {
+	Host string `default:"localhost"`
+	Port int    `default:"5432"`
+}
+
+type defaultsConfig struct {
+	Name    string        `default:"app"`
+	Port    int           `default:"8080"`
+	Timeout time.Duration `default:"30s"`
+	Hosts   []string      `default:"a,b"`
+	Debug   *bool         `default:"true"`
+	NoTag   string
+	DB      defaultsDatabase
+	Replica *defaultsDatabase
+	Cache   *struct{ Size int }
+}
+
+func TestApplyDefaults(t *testing.T) {
+	c := defaultsConfig{Name: "set", DB: defaultsDatabase{Port: 1}}
+
+	if err := ApplyDefaults(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	debug := true
+	want := defaultsConfig{
+		Name:    "set",
+		Port:    8080,
+		Timeout: 30 * time.Second,
+		Hosts:   []string{"a", "b"},
+		Debug:   &debug,
+		DB:      defaultsDatabase{Host: "localhost", Port: 1},
+		Replica: &defaultsDatabase{Host: "localhost", Port: 5432},
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+}
+
+func TestApplyDefaults_Invalid(t *testing.T) {
+	var c struct {
+		Port int `default:"http"`
+	}
+
+	var fieldErrs FieldErrors
+	if err := New(&c).ApplyDefaults(); !errors.As(err, &fieldErrs) || fieldErrs[0].Path != "Port" {
+		t.Errorf("expected an error for Port, got %v", err)
+	}
+
+	if err := ApplyDefaults(c); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMaxDepth(t *testing.T) {
+	type C struct {
+		Name string
+	}
+
+	type B struct {
+		C  C
+		Cs []C
+	}
+
+	type A struct {
+		B B `structs:",flatten"`
+	}
+
+	a := A{B: B{C: C{Name: "c"}, Cs: []C{{Name: "c"}}}}
+
+	s := New(a)
+	s.MaxDepth = 1
+
+	m := s.Map()
+	want := map[string]interface{}{
+		"C":  C{Name: "c"},
+		"Cs": []C{{Name: "c"}},
+	}
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Map should stop at MaxDepth, got: %#v", m)
+	}
+
+	s = New(a)
+	s.MaxDepth = 1
+
+	v := s.Values()
+	if !reflect.DeepEqual(v, []interface{}{C{Name: "c"}, []C{{Name: "c"}}}) {
+		t.Errorf("Values should stop at MaxDepth, got: %#v", v)
+	}
+
+	s = New(A{})
+	s.MaxDepth = 1
+
+	if !s.IsZero() {
+		t.Error("IsZero should compare structs below MaxDepth")
+	}
+
+	if m := Map(a); !reflect.DeepEqual(m["C"], map[string]interface{}{"Name": "c"}) {
+		t.Errorf("Map without MaxDepth should convert all levels, got: %#v", m)
+	}
+}
@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Change is a field whose value differs between two structs.
+type Change struct {
+	// Path is the dotted path of the field, such as "DB.Host".
+	Path string
+
+	// Old and New are the values of the field in the first and second
+	// struct.
+	Old, New interface{}
+}
+
+// Diff returns the changes of the fields of s in other, which must be of
+// the same type, in the order of the fields. Nested structs, and pointers to
+// them which are both non-nil, are compared field by field, while other
+// fields, including structs implementing encoding.TextUnmarshaler such as
+// time.Time, are compared as a whole: with their Equal method if they have
+// one, such as time.Time, and with reflect.DeepEqual otherwise. Unexported
+// fields and fields tagged with "-" are ignored. Pointer cycles are compared
+// once. It panics if other's type differs from s's.
+func (s *Struct) Diff(other interface{}) []Change {
+	o := strctVal(other)
+	if o.Type() != s.value.Type() {
+		panic(fmt.Errorf("%w: can't diff %s with %s", ErrTypeMismatch, s.value.Type(), o.Type()))
+	}
+
+	s = s.track()
+
+	a, b := reflect.ValueOf(s.raw), reflect.ValueOf(other)
+	if a.Kind() == reflect.Ptr && b.Kind() == reflect.Ptr {
+		s.enterPair(a, b)
+	}
+
+	return s.diff(o, "")
+}
+
+// diff returns the changes of the fields of s in o, with their paths
+// prefixed by prefix.
+func (s *Struct) diff(o reflect.Value, prefix string) []Change {
+	var changes []Change
+
+	for _, field := range s.structFields() {
+		a := s.value.FieldByName(field.Name)
+		b := o.FieldByName(field.Name)
+		path := prefix + field.Name
+
+		if envNested(field.Type) {
+			if a.Kind() == reflect.Ptr {
+				if a.IsNil() || b.IsNil() {
+					if a.IsNil() != b.IsNil() {
+						changes = append(changes, Change{Path: path, Old: a.Interface(), New: b.Interface()})
+					}
+					continue
+				}
+
+				// the pointers of a cycle are already being compared
+				if !s.enterPair(a, b) {
+					continue
+				}
+
+				changes = append(changes, s.nestedStruct(a.Interface()).diff(b.Elem(), path+".")...)
+				s.leavePair(a, b)
+				continue
+			}
+
+			changes = append(changes, s.nestedStruct(a.Interface()).diff(b, path+".")...)
+			continue
+		}
+
+		if !equal(a, b) {
+			changes = append(changes, Change{Path: path, Old: a.Interface(), New: b.Interface()})
+		}
+	}
+
+	return changes
+}
+
+// equal reports whether a and b, which are of the same type, are equal as
+// described in Diff.
+func equal(a, b reflect.Value) bool {
+	if m := a.MethodByName("Equal"); m.IsValid() {
+		t := m.Type()
+		if t.NumIn() == 1 && t.In(0) == a.Type() && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool {
+			if a.Kind() != reflect.Ptr || (!a.IsNil() && !b.IsNil()) {
+				return m.Call([]reflect.Value{b})[0].Bool()
+			}
+		}
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// Diff returns the changes of the fields of a in b. For more info refer to
+// Struct types Diff() method. It panics if a's kind is not struct or b's
+// type differs from a's.
+func Diff(a, b interface{}) []Change {
+	return New(a).Diff(b)
+}
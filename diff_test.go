@@ -0,0 +1,96 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type diffAddress struct {
+	City string
+	Zip  string
+}
+
+type diffUser struct {
+	Name    string
+	Tags    []string
+	Created time.Time
+	Home    diffAddress
+	Work    *diffAddress
+	Skip    string `structs:"-"`
+	secret  string
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+
+	a := diffUser{
+		Name:    "Ann",
+		Tags:    []string{"a"},
+		Created: now,
+		Home:    diffAddress{City: "Berlin", Zip: "10115"},
+		Work:    &diffAddress{City: "Berlin"},
+		Skip:    "x",
+		secret:  "x",
+	}
+
+	b := a
+	b.Tags = []string{"a", "b"}
+	b.Created = now.Round(0) // equal, but without the monotonic clock reading
+	b.Home.City = "Hamburg"
+	b.Work = &diffAddress{City: "Munich"}
+	b.Skip = "y"
+	b.secret = "y"
+
+	want := []Change{
+		{Path: "Tags", Old: []string{"a"}, New: []string{"a", "b"}},
+		{Path: "Home.City", Old: "Berlin", New: "Hamburg"},
+		{Path: "Work.City", Old: "Berlin", New: "Munich"},
+	}
+
+	if got := Diff(a, &b); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v want %#v", got, want)
+	}
+
+	b.Work = nil
+	if got := New(a).Diff(b); len(got) != 3 || got[2].Path != "Work" || got[2].New != (*diffAddress)(nil) {
+		t.Errorf("nil pointer: got %#v", got)
+	}
+
+	if got := Diff(a, a); len(got) != 0 {
+		t.Errorf("equal structs: got %#v", got)
+	}
+}
+
+func TestDiff_TypeMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic")
+		}
+	}()
+
+	Diff(diffUser{}, diffAddress{})
+}
+
+func TestDiff_Cycle(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	a := &Node{Name: "a"}
+	a.Next = &Node{Name: "b", Next: a}
+
+	b := &Node{Name: "a"}
+	b.Next = &Node{Name: "c", Next: b}
+
+	got := Diff(a, b)
+	want := []Change{{Path: "Next.Name", Old: "b", New: "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v want %#v", got, want)
+	}
+
+	if got := Diff(*a, *a); len(got) != 0 {
+		t.Errorf("equal cycles: got %#v", got)
+	}
+}
@@ -0,0 +1,48 @@
+package structs
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DuplicatePolicy defines how fields are handled whose key is already used
+// by another field, i.e. because of their tags, the KeyCase or flattening.
+type DuplicatePolicy int
+
+const (
+	// DuplicateLastWins overwrites the value of the previous field with the
+	// same key. This is the default.
+	DuplicateLastWins DuplicatePolicy = iota
+
+	// DuplicateError panics with an error wrapping ErrDuplicateKey.
+	// MapStrict returns the error instead.
+	DuplicateError
+
+	// DuplicateSuffix adds the lowest number starting from 2 to the key that
+	// makes it unique, i.e. "name2".
+	DuplicateSuffix
+)
+
+// dedupe returns the key to use for the given field, applying the
+// OnDuplicateKey policy of s if key was already used by another field. Keys
+// used are recorded in seen.
+func (s *Struct) dedupe(seen map[string]bool, field, key string) string {
+	if seen[key] {
+		switch s.OnDuplicateKey {
+		case DuplicateError:
+			panic(fmt.Errorf("%w %q at %s", ErrDuplicateKey, key, field))
+		case DuplicateSuffix:
+			for n := 2; ; n++ {
+				if k := key + strconv.Itoa(n); !seen[k] {
+					key = k
+					break
+				}
+			}
+
+			s.trace(field, TraceRename, key)
+		}
+	}
+
+	seen[key] = true
+	return key
+}
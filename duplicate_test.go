@@ -0,0 +1,65 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type duplicateInner struct {
+	Name string
+}
+
+type duplicate struct {
+	Name     string
+	Title    string         `structs:"Name"`
+	Inner    duplicateInner `structs:",flatten"`
+	UserName string
+	User     string `structs:"user_name"`
+}
+
+func TestMap_DuplicateKey(t *testing.T) {
+	d := duplicate{
+		Name:     "a",
+		Title:    "b",
+		Inner:    duplicateInner{"c"},
+		UserName: "d",
+		User:     "e",
+	}
+
+	s := New(d)
+	s.KeyCase = KeySnakeCase
+
+	want := map[string]interface{}{
+		"name":      "c",
+		"Name":      "b",
+		"user_name": "e",
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, want) {
+		t.Errorf("DuplicateLastWins: got %#v want %#v", m, want)
+	}
+
+	s.OnDuplicateKey = DuplicateSuffix
+	want = map[string]interface{}{
+		"name":       "a",
+		"Name":       "b",
+		"name2":      "c",
+		"user_name":  "d",
+		"user_name2": "e",
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, want) {
+		t.Errorf("DuplicateSuffix: got %#v want %#v", m, want)
+	}
+
+	s.OnDuplicateKey = DuplicateError
+	_, err := s.MapStrict()
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("DuplicateError: got %v", err)
+	}
+
+	if want := `duplicate key "name" at Inner`; err.Error() != want {
+		t.Errorf("got %q want %q", err, want)
+	}
+}
@@ -0,0 +1,506 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// FromMap returns a pointer to a new struct holding the values of m, so code
+// expecting structs, such as templates or encoders, can consume dynamic
+// data. The struct's type is built at runtime with a field for each key of
+// m, in the order of the keys:
+//
+//   - the field's name is the key converted to an exported identifier, i.e.
+//     "user_id" becomes "UserID", and its tag gives the key for the default
+//     tag name and for JSON, so Map returns the keys of m
+//   - map[string]interface{} values become nested structs
+//   - []interface{} values become slices of the type of their elements if
+//     all of them have the same type, and []interface{} otherwise
+//   - nil values become interface{} fields
+//   - other values keep their type
+//
+// Keys containing a comma, as well as "-" and the empty key, can't be given
+// in a tag, so Map returns the field's name for them. It returns an error if
+// the type can't be built.
+func FromMap(m map[string]interface{}) (interface{}, error) {
+	t, err := dynamicType(m)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.New(t)
+	fillDynamic(v.Elem(), m)
+	return v.Interface(), nil
+}
+
+// dynamicType returns the struct type of FromMap for m.
+func dynamicType(m map[string]interface{}) (reflect.Type, error) {
+	keys := sortedStrings(m)
+
+	b := NewTypeBuilder()
+	used := make(map[string]bool)
+
+	for _, k := range keys {
+		t, err := dynamicValueType(m[k])
+		if err != nil {
+			return nil, err
+		}
+
+		name := fieldName(k)
+		for i := 2; used[name]; i++ {
+			name = fieldName(k) + strconv.Itoa(i)
+		}
+		used[name] = true
+
+		var tag string
+		if k != "" && k != "-" && !strings.Contains(k, ",") {
+			tag = DefaultTagName + ":" + strconv.Quote(k) + " json:" + strconv.Quote(k)
+		}
+
+		b.AddField(name, t, tag)
+	}
+
+	return b.Build()
+}
+
+// dynamicValueType returns the type of the field of FromMap for v.
+func dynamicValueType(v interface{}) (reflect.Type, error) {
+	switch v := v.(type) {
+	case nil:
+		return interfaceType, nil
+	case map[string]interface{}:
+		return dynamicType(v)
+	case []interface{}:
+		var elem reflect.Type
+		for _, e := range v {
+			t, err := dynamicValueType(e)
+			if err != nil {
+				return nil, err
+			}
+
+			if elem != nil && t != elem {
+				return reflect.TypeOf(v), nil
+			}
+
+			elem = t
+		}
+
+		if elem == nil || elem == interfaceType {
+			return reflect.TypeOf(v), nil
+		}
+
+		return reflect.SliceOf(elem), nil
+	}
+
+	return reflect.TypeOf(v), nil
+}
+
+// fillDynamic sets v, whose type was returned by dynamicValueType for val,
+// to val.
+func fillDynamic(v reflect.Value, val interface{}) {
+	switch val := val.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		if v.Kind() == reflect.Struct {
+			for i, k := range sortedStrings(val) {
+				fillDynamic(v.Field(i), val[k])
+			}
+
+			return
+		}
+	case []interface{}:
+		if v.Type() != reflect.TypeOf(val) {
+			v.Set(reflect.MakeSlice(v.Type(), len(val), len(val)))
+			for i, e := range val {
+				fillDynamic(v.Index(i), e)
+			}
+
+			return
+		}
+	}
+
+	v.Set(reflect.ValueOf(val))
+}
+
+// sortedStrings returns the keys of m in increasing order.
+func sortedStrings(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldName converts the key k into an exported identifier, with the words
+// of k capitalized and initialisms in upper case.
+func fieldName(k string) string {
+	var b strings.Builder
+
+	for _, w := range splitWords(k) {
+		w = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+
+			return -1
+		}, w)
+
+		if w == "" {
+			continue
+		}
+
+		initialismsMu.RLock()
+		upper := initialisms[strings.ToUpper(w)]
+		initialismsMu.RUnlock()
+
+		if upper {
+			w = strings.ToUpper(w)
+		} else {
+			r := []rune(w)
+			w = string(unicode.ToUpper(r[0])) + string(r[1:])
+		}
+
+		b.WriteString(w)
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "F" + name
+	}
+
+	return name
+}
+
+// Extend returns a pointer to a new struct whose type has the exported
+// fields of s followed by the given fields, with the values of s copied
+// over, such as to add audit columns to a third party type:
+//
+//   v, err := structs.Extend(user,
+//       structs.FieldSpec{Name: "CreatedBy", Type: reflect.TypeOf(""), Tag: `db:"created_by"`},
+//   )
+//
+// The type is built as with the AddFields method of TypeBuilder. The values
+// are copied shallowly, so slices, maps and pointers are shared with s. It
+// returns an error if a field is invalid or already used by s.
+func (s *Struct) Extend(fields ...FieldSpec) (interface{}, error) {
+	b := NewTypeBuilder().AddFields(s.value.Type())
+	for _, f := range fields {
+		b.AddField(f.Name, f.Type, f.Tag)
+	}
+
+	t, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.New(t)
+	copyFields(v.Elem(), s.value)
+	return v.Interface(), nil
+}
+
+// copyFields sets the fields of the struct dst to the values of the fields
+// of the struct src with the same name and type.
+func copyFields(dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		f := dst.Type().Field(i)
+
+		sf, ok := src.Type().FieldByName(f.Name)
+		if !ok || len(sf.Index) != 1 || sf.Type != f.Type {
+			continue
+		}
+
+		dst.Field(i).Set(src.Field(sf.Index[0]))
+	}
+}
+
+// Extend returns a pointer to a new struct with the fields of the given
+// struct and the given fields. For more info refer to Struct types Extend()
+// method. It panics if s's kind is not struct.
+func Extend(s interface{}, fields ...FieldSpec) (interface{}, error) {
+	return New(s).Extend(fields...)
+}
+
+// mergedField is a field of a merged type and the index of the field of
+// the struct its value is copied from.
+type mergedField struct {
+	field reflect.StructField
+	src   int
+	index int
+}
+
+// MergeTypes returns a struct type with the exported fields of the struct
+// types a and b, or the struct types they point to, in this order. The
+// fields are added as with the AddFields method of TypeBuilder. Fields of b
+// whose name, or whose key in the output of Map with the default tag name,
+// is already used by a field of a are handled by policy:
+//
+//   - DuplicateLastWins replaces the field of a with the one of b, at the
+//     position of the field of a
+//   - DuplicateError returns an error wrapping ErrDuplicateKey
+//   - DuplicateSuffix adds the lowest number starting from 2 to the name of
+//     the field of b, and to the name in its tag, that makes both unique,
+//     i.e. "Name2"
+//
+// It returns an error if a or b is not a struct type.
+func MergeTypes(a, b reflect.Type, policy DuplicatePolicy) (reflect.Type, error) {
+	t, _, err := mergeTypes(a, b, policy)
+	return t, err
+}
+
+// mergeTypes returns the type of MergeTypes and the fields the values of
+// its fields are copied from.
+func mergeTypes(a, b reflect.Type, policy DuplicatePolicy) (reflect.Type, []mergedField, error) {
+	var fields []mergedField
+	names := make(map[string]int)
+	keys := make(map[string]int)
+
+	// remove drops the field at i, which is replaced by another one
+	remove := func(i int) {
+		delete(names, fields[i].field.Name)
+		delete(keys, mergeKey(fields[i].field))
+		fields[i].src = -1
+	}
+
+	for src, t := range []reflect.Type{indirectType(a), indirectType(b)} {
+		if t.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("%w: can't merge %s", ErrNotStruct, t)
+		}
+
+		for _, f := range exportedFields(t) {
+			mf := mergedField{field: f, src: src, index: f.Index[0]}
+			key := mergeKey(f)
+
+			i, byName := names[f.Name]
+			j, byKey := keys[key]
+			if key == "" {
+				byKey = false
+			}
+
+			if !byName && !byKey {
+				names[f.Name], keys[key] = len(fields), len(fields)
+				fields = append(fields, mf)
+				continue
+			}
+
+			switch policy {
+			case DuplicateError:
+				if !byName {
+					return nil, nil, fmt.Errorf("%w %q of %s in %s and %s", ErrDuplicateKey, key, f.Name, a, b)
+				}
+
+				return nil, nil, fmt.Errorf("%w %q in %s and %s", ErrDuplicateKey, f.Name, a, b)
+			case DuplicateSuffix:
+				tag, _ := f.Tag.Lookup(DefaultTagName)
+				name, _ := parseTag(tag)
+
+				for n := 2; byName || byKey; n++ {
+					suffix := strconv.Itoa(n)
+					mf.field.Name = f.Name + suffix
+					if name != "" {
+						mf.field.Tag = retag(f.Tag, DefaultTagName, name+suffix+strings.TrimPrefix(tag, name))
+					}
+
+					_, byName = names[mf.field.Name]
+					_, byKey = keys[mergeKey(mf.field)]
+				}
+
+				mf.field.Anonymous = false
+				names[mf.field.Name], keys[mergeKey(mf.field)] = len(fields), len(fields)
+				fields = append(fields, mf)
+			default:
+				// the field of b takes the position of the one it replaces
+				at := i
+				if !byName {
+					at = j
+				} else if byKey && j != i {
+					remove(j)
+				}
+
+				remove(at)
+				names[f.Name], keys[key] = at, at
+				fields[at] = mf
+			}
+		}
+	}
+
+	merged := fields[:0]
+	for _, f := range fields {
+		if f.src >= 0 {
+			merged = append(merged, f)
+		}
+	}
+
+	tb := NewTypeBuilder()
+	for _, f := range merged {
+		tb.add(reflect.StructField{Name: f.field.Name, Type: f.field.Type, Tag: f.field.Tag, Anonymous: f.field.Anonymous})
+	}
+
+	t, err := tb.Build()
+	return t, merged, err
+}
+
+// mergeKey returns the key of the field f in the output of Map with the
+// default tag name. It's empty for fields tagged with "-".
+func mergeKey(f reflect.StructField) string {
+	name, _ := parseTag(f.Tag.Get(DefaultTagName))
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+
+	return name
+}
+
+// retag returns tag with the value of the given tag name replaced by value.
+func retag(tag reflect.StructTag, tagName, value string) reflect.StructTag {
+	old, _ := tag.Lookup(tagName)
+	return reflect.StructTag(strings.Replace(string(tag),
+		tagName+":"+strconv.Quote(old), tagName+":"+strconv.Quote(value), 1))
+}
+
+// Merge returns a pointer to a new struct whose type has the fields of the
+// structs a and b, as returned by MergeTypes, with their values copied over,
+// such as to compose an API response from multiple domain objects:
+//
+//   v, err := structs.Merge(user, account, structs.DuplicateSuffix)
+//
+// The values are copied shallowly, so slices, maps and pointers are shared
+// with a and b. It returns an error if a or b is not a struct or a pointer to
+// struct.
+func Merge(a, b interface{}, policy DuplicatePolicy) (interface{}, error) {
+	va, err := structVal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	vb, err := structVal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	t, fields, err := mergeTypes(va.Type(), vb.Type(), policy)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.New(t).Elem()
+	for i, f := range fields {
+		src := va
+		if f.src == 1 {
+			src = vb
+		}
+
+		v.Field(i).Set(src.Field(f.index))
+	}
+
+	return v.Addr().Interface(), nil
+}
+
+// PickType returns a struct type with the exported fields of the struct type
+// t, or the struct type t points to, with the given names only, in the order
+// they're declared, such as for a public view of a type. The fields are
+// added as with the AddFields method of TypeBuilder. It returns an error
+// wrapping ErrFieldNotFound if t has no exported field with one of the
+// names.
+func PickType(t reflect.Type, names ...string) (reflect.Type, error) {
+	return subsetType(t, names, true)
+}
+
+// OmitType returns a struct type with the exported fields of the struct type
+// t, or the struct type t points to, except the ones with the given names.
+// For more info refer to PickType.
+func OmitType(t reflect.Type, names ...string) (reflect.Type, error) {
+	return subsetType(t, names, false)
+}
+
+// subsetType returns the type of PickType if pick is true and the type of
+// OmitType otherwise.
+func subsetType(t reflect.Type, names []string, pick bool) (reflect.Type, error) {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s", ErrNotStruct, t)
+	}
+
+	fields := exportedFields(t)
+
+	exported := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		exported[f.Name] = true
+	}
+
+	given := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !exported[name] {
+			return nil, fmt.Errorf("%w: %s in %s", ErrFieldNotFound, name, t)
+		}
+
+		given[name] = true
+	}
+
+	b := NewTypeBuilder()
+	for _, f := range fields {
+		if given[f.Name] == pick {
+			b.add(reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag, Anonymous: f.Anonymous})
+		}
+	}
+
+	return b.Build()
+}
+
+// Pick returns a pointer to a new struct whose type has the fields of s with
+// the given names only, as returned by PickType, with their values copied
+// over:
+//
+//   public, err := structs.Pick(user, "ID", "Name")
+//
+// The values are copied shallowly, so slices, maps and pointers are shared
+// with s. It returns an error wrapping ErrFieldNotFound if s has no exported
+// field with one of the names.
+func (s *Struct) Pick(names ...string) (interface{}, error) {
+	return s.subset(names, true)
+}
+
+// Omit returns a pointer to a new struct whose type has the fields of s
+// except the ones with the given names, as returned by OmitType, with their
+// values copied over. For more info refer to Struct types Pick() method.
+func (s *Struct) Omit(names ...string) (interface{}, error) {
+	return s.subset(names, false)
+}
+
+// subset returns the result of Pick if pick is true and the result of Omit
+// otherwise.
+func (s *Struct) subset(names []string, pick bool) (interface{}, error) {
+	t, err := subsetType(s.value.Type(), names, pick)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.New(t)
+	copyFields(v.Elem(), s.value)
+	return v.Interface(), nil
+}
+
+// Pick returns a pointer to a new struct with the fields of the given
+// struct with the given names only. For more info refer to Struct types
+// Pick() method. It panics if s's kind is not struct.
+func Pick(s interface{}, names ...string) (interface{}, error) {
+	return New(s).Pick(names...)
+}
+
+// Omit returns a pointer to a new struct with the fields of the given struct
+// except the ones with the given names. For more info refer to Struct types
+// Omit() method. It panics if s's kind is not struct.
+func Omit(s interface{}, names ...string) (interface{}, error) {
+	return New(s).Omit(names...)
+}
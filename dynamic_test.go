@@ -0,0 +1,298 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromMap(t *testing.T) {
+	m := map[string]interface{}{
+		"user_id": 42,
+		"name":    "Ann",
+		"address": map[string]interface{}{"city": "Oslo"},
+		"tags":    []interface{}{"a", "b"},
+		"mixed":   []interface{}{"a", 1},
+		"lines":   []interface{}{map[string]interface{}{"sku": "x"}, map[string]interface{}{"sku": "y"}},
+		"note":    nil,
+		"a,b":     true,
+		"9lives":  1.5,
+	}
+
+	v, err := FromMap(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ := reflect.TypeOf(v).Elem()
+
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		names = append(names, typ.Field(i).Name)
+	}
+
+	wantNames := []string{"F9lives", "Ab", "Address", "Lines", "Mixed", "Name", "Note", "Tags", "UserID"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("got %v want %v", names, wantNames)
+	}
+
+	for name, want := range map[string]string{
+		"UserID":  "int",
+		"Tags":    "[]string",
+		"Mixed":   "[]interface {}",
+		"Note":    "interface {}",
+		"Address": "struct { City string \"structs:\\\"city\\\" json:\\\"city\\\"\" }",
+	} {
+		f, _ := typ.FieldByName(name)
+		if got := f.Type.String(); got != want {
+			t.Errorf("%s: got %s want %s", name, got, want)
+		}
+	}
+
+	lines, _ := typ.FieldByName("Lines")
+	if lines.Type.Kind() != reflect.Slice || lines.Type.Elem().Kind() != reflect.Struct {
+		t.Errorf("Lines: got %s", lines.Type)
+	}
+
+	got := Map(v)
+	delete(got, "Ab")
+	delete(m, "a,b")
+
+	// nested structs are converted back to maps
+	b1, _ := json.Marshal(got)
+	b2, _ := json.Marshal(m)
+	if string(b1) != string(b2) {
+		t.Errorf("got %s want %s", b1, b2)
+	}
+
+	j, _ := json.Marshal(v)
+	if string(j) != `{"9lives":1.5,"Ab":true,"address":{"city":"Oslo"},"lines":[{"sku":"x"},{"sku":"y"}],"mixed":["a",1],"name":"Ann","note":null,"tags":["a","b"],"user_id":42}` {
+		t.Errorf("got %s", j)
+	}
+}
+
+func TestFromMap_UntaggedKeys(t *testing.T) {
+	v, err := FromMap(map[string]interface{}{"-": 1, "": 2, "a,b": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := Map(v)
+	want := map[string]interface{}{"F": 2, "F2": 1, "Ab": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	for key, want := range map[string]string{
+		"name":      "Name",
+		"user_id":   "UserID",
+		"userName":  "UserName",
+		"http-port": "HTTPPort",
+		"a b":       "Ab",
+		"":          "F",
+		"_":         "F",
+		"1st":       "F1st",
+	} {
+		if got := fieldName(key); got != want {
+			t.Errorf("%q: got %q want %q", key, got, want)
+		}
+	}
+}
+
+type dynamicAudit struct {
+	Version int
+}
+
+type dynamicUser struct {
+	dynamicAudit
+	time.Time
+	ID     int `db:"id"`
+	Tags   []string
+	secret string
+}
+
+func TestExtend(t *testing.T) {
+	u := dynamicUser{
+		dynamicAudit: dynamicAudit{Version: 2},
+		Time:         time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		ID:           1,
+		Tags:         []string{"a"},
+		secret:       "x",
+	}
+
+	v, err := Extend(&u, FieldSpec{Name: "CreatedBy", Type: reflect.TypeOf(""), Tag: `db:"created_by"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ := reflect.TypeOf(v).Elem()
+	if typ.NumField() != 4 {
+		t.Fatalf("got %s", typ)
+	}
+
+	if f := typ.Field(0); f.Name != "Time" || f.Anonymous {
+		t.Errorf("got %+v, want a field named after the embedded type with methods", f)
+	}
+
+	if f, _ := typ.FieldByName("CreatedBy"); f.Tag.Get("db") != "created_by" {
+		t.Errorf("got %+v", f)
+	}
+
+	if err := SetPath(v, "CreatedBy", "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"Time":      u.Time,
+		"ID":        1,
+		"Tags":      []string{"a"},
+		"CreatedBy": "admin",
+	}
+
+	if got := Map(v); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	if _, err := Extend(u, FieldSpec{Name: "ID", Type: reflect.TypeOf(0)}); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("got %v", err)
+	}
+}
+
+type dynamicAccount struct {
+	ID      string
+	Balance float64
+}
+
+func TestMerge(t *testing.T) {
+	u := dynamicUser{ID: 1, Tags: []string{"a"}}
+	a := &dynamicAccount{ID: "acc", Balance: 1.5}
+
+	tests := []struct {
+		policy DuplicatePolicy
+		want   map[string]interface{}
+	}{
+		{DuplicateLastWins, map[string]interface{}{"Time": time.Time{}, "ID": "acc", "Tags": []string{"a"}, "Balance": 1.5}},
+		{DuplicateSuffix, map[string]interface{}{"Time": time.Time{}, "ID": 1, "Tags": []string{"a"}, "ID2": "acc", "Balance": 1.5}},
+	}
+
+	for _, test := range tests {
+		v, err := Merge(u, a, test.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := Map(v); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d: got %v want %v", test.policy, got, test.want)
+		}
+	}
+
+	typ, err := MergeTypes(reflect.TypeOf(u), reflect.TypeOf(a), DuplicateLastWins)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f := typ.Field(1); f.Name != "ID" || f.Type.Kind() != reflect.String {
+		t.Errorf("got %+v, want the ID of the account at the position of the user's", f)
+	}
+
+	if _, err := Merge(u, a, DuplicateError); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("got %v", err)
+	}
+
+	if _, err := Merge(u, 1, DuplicateError); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("got %v", err)
+	}
+}
+
+type dynamicKeyed struct {
+	UserID int    `structs:"id"`
+	Name   string `structs:"name,omitempty"`
+}
+
+type dynamicKeyedOther struct {
+	AccountID string `structs:"id,omitempty" db:"account_id"`
+	Name      string
+}
+
+func TestMerge_TagKey(t *testing.T) {
+	a := dynamicKeyed{UserID: 1, Name: "x"}
+	b := dynamicKeyedOther{AccountID: "acc", Name: "y"}
+
+	if _, err := Merge(a, b, DuplicateError); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("got %v, want the collision on the id key", err)
+	}
+
+	tests := []struct {
+		policy DuplicatePolicy
+		want   map[string]interface{}
+	}{
+		{DuplicateLastWins, map[string]interface{}{"id": "acc", "Name": "y"}},
+		{DuplicateSuffix, map[string]interface{}{"id": 1, "name": "x", "id2": "acc", "Name2": "y"}},
+	}
+
+	for _, test := range tests {
+		v, err := Merge(a, b, test.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := Map(v); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d: got %v want %v", test.policy, got, test.want)
+		}
+	}
+
+	typ, err := MergeTypes(reflect.TypeOf(a), reflect.TypeOf(b), DuplicateSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f := typ.Field(2); f.Name != "AccountID2" || f.Tag != `structs:"id2,omitempty" db:"account_id"` {
+		t.Errorf("got %+v, want the account ID with a suffixed key", f)
+	}
+}
+
+func TestPickOmit(t *testing.T) {
+	u := dynamicUser{ID: 1, Tags: []string{"a"}, secret: "x"}
+
+	picked, err := Pick(u, "Tags", "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := Map(picked), map[string]interface{}{"ID": 1, "Tags": []string{"a"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	if f := reflect.TypeOf(picked).Elem().Field(0); f.Name != "ID" || f.Tag.Get("db") != "id" {
+		t.Errorf("got %+v, want the ID field first with its tag", f)
+	}
+
+	omitted, err := Omit(&u, "Time", "Tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := Map(omitted), map[string]interface{}{"ID": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	typ, err := OmitType(reflect.TypeOf(&u))
+	if err != nil || typ.NumField() != 3 {
+		t.Errorf("got %v, %v", typ, err)
+	}
+
+	for _, names := range [][]string{{"Missing"}, {"secret"}} {
+		if _, err := Pick(u, names...); !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("%v: got %v", names, err)
+		}
+	}
+
+	if _, err := PickType(reflect.TypeOf(0)); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,456 @@
+package structs
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// AttributeValue is a DynamoDB attribute value in the shape of the DynamoDB
+// JSON format. It has a single key describing the type of the value, such as
+// {"S": "gopher"}, {"N": "42"}, {"BOOL": true}, {"NULL": true}, {"B": []byte},
+// {"M": map[string]AttributeValue} or {"L": []AttributeValue}.
+type AttributeValue map[string]interface{}
+
+// DynamoDB converts the given struct to a DynamoDB item, where the keys of the
+// map are the field names and the values are the attribute values of the
+// fields. Field names and the "-" and "omitempty" options are handled as in
+// Map, so setting TagName to "dynamodbav" reuses the tags of the AWS SDK.
+// Values are converted as follows:
+//
+//   string                          => S
+//   bool                            => BOOL
+//   int, uint and float kinds       => N
+//   []byte                          => B
+//   nil pointers, maps and slices   => NULL
+//   encoding.TextMarshaler          => S
+//   struct and map[string]T         => M
+//   slice and array                 => L
+//
+// It panics if a value can't be represented as an attribute value, such as a
+// chan or a map with non-string keys.
+func (s *Struct) DynamoDB() map[string]AttributeValue {
+	out := make(map[string]AttributeValue)
+
+	for _, field := range s.structFields() {
+		val := s.value.FieldByName(field.Name)
+
+		name, tagOpts := s.key(field)
+
+		if tagOpts.Has("omitempty") && isZeroValue(val) {
+			continue
+		}
+
+		out[name] = s.toAttributeValue(val)
+	}
+
+	return out
+}
+
+// FromDynamoDB sets the fields of s from the given DynamoDB item. It's the
+// inverse of DynamoDB, numbers are parsed according to the field's kind and
+// nil pointers are allocated as needed. Attributes without a matching field
+// are ignored. It returns an error if s was not created from a pointer. If
+// attribute values don't fit into their fields, all of them are reported
+// with a FieldErrors.
+func (s *Struct) FromDynamoDB(item map[string]AttributeValue) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	var errs FieldErrors
+
+	for _, field := range s.structFields() {
+		name, _ := s.key(field)
+
+		av, ok := item[name]
+		if !ok {
+			continue
+		}
+
+		if err := s.fromAttributeValue(av, s.value.FieldByName(field.Name)); err != nil {
+			errs.add(field.Name, field.Type, attributeType(av), err)
+		}
+	}
+
+	return errs.err()
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// toAttributeValue converts v to an attribute value.
+func (s *Struct) toAttributeValue(v reflect.Value) AttributeValue {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return AttributeValue{"NULL": true}
+		}
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			panic(err)
+		}
+
+		return AttributeValue{"S": string(text)}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return s.toAttributeValue(v.Elem())
+	case reflect.String:
+		return AttributeValue{"S": v.String()}
+	case reflect.Bool:
+		return AttributeValue{"BOOL": v.Bool()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return AttributeValue{"N": strconv.FormatInt(v.Int(), 10)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return AttributeValue{"N": strconv.FormatUint(v.Uint(), 10)}
+	case reflect.Float32, reflect.Float64:
+		return AttributeValue{"N": strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())}
+	case reflect.Struct:
+		n := New(v.Interface())
+		n.TagName = s.TagName
+		return AttributeValue{"M": n.DynamoDB()}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			panic(fmt.Errorf("%w: map key is not string", ErrUnsupportedKind))
+		}
+
+		m := make(map[string]AttributeValue, v.Len())
+		for _, k := range v.MapKeys() {
+			m[k.String()] = s.toAttributeValue(v.MapIndex(k))
+		}
+
+		return AttributeValue{"M": m}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return AttributeValue{"B": b}
+		}
+
+		l := make([]AttributeValue, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			l[i] = s.toAttributeValue(v.Index(i))
+		}
+
+		return AttributeValue{"L": l}
+	}
+
+	panic(fmt.Errorf("%w: %s", ErrUnsupportedKind, v.Kind()))
+}
+
+// fromAttributeValue sets v from the given attribute value.
+func (s *Struct) fromAttributeValue(av AttributeValue, v reflect.Value) error {
+	if null, _ := av["NULL"].(bool); null {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return s.fromAttributeValue(av, v.Elem())
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if str, ok := av["S"].(string); ok {
+			return u.UnmarshalText([]byte(str))
+		}
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		i, err := attributeInterface(av)
+		if err != nil {
+			return err
+		}
+
+		if i == nil {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(i))
+		}
+
+		return nil
+	}
+
+	switch {
+	case av["S"] != nil:
+		str, ok := av["S"].(string)
+		if !ok || v.Kind() != reflect.String {
+			return fmt.Errorf("%w: can't set S into %s", ErrTypeMismatch, v.Type())
+		}
+
+		v.SetString(str)
+	case av["BOOL"] != nil:
+		b, ok := av["BOOL"].(bool)
+		if !ok || v.Kind() != reflect.Bool {
+			return fmt.Errorf("%w: can't set BOOL into %s", ErrTypeMismatch, v.Type())
+		}
+
+		v.SetBool(b)
+	case av["N"] != nil:
+		n, _ := av["N"].(string)
+		return setNumber(v, n)
+	case av["B"] != nil:
+		b, err := attributeBytes(av["B"])
+		if err != nil {
+			return err
+		}
+
+		if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: can't set B into %s", ErrTypeMismatch, v.Type())
+		}
+
+		if v.Kind() == reflect.Array {
+			if v.Len() < len(b) {
+				return fmt.Errorf("%w: can't set B of length %d into %s", ErrTypeMismatch, len(b), v.Type())
+			}
+
+			v.Set(reflect.Zero(v.Type()))
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+
+		v.SetBytes(b)
+	case av["M"] != nil:
+		m, err := attributeMap(av["M"])
+		if err != nil {
+			return err
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			n := New(v.Addr().Interface())
+			n.TagName = s.TagName
+			return n.FromDynamoDB(m)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return fmt.Errorf("%w: can't set M into %s", ErrTypeMismatch, v.Type())
+			}
+
+			out := reflect.MakeMapWithSize(v.Type(), len(m))
+			for k, elem := range m {
+				e := reflect.New(v.Type().Elem()).Elem()
+				if err := s.fromAttributeValue(elem, e); err != nil {
+					return err
+				}
+
+				out.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), e)
+			}
+
+			v.Set(out)
+		default:
+			return fmt.Errorf("%w: can't set M into %s", ErrTypeMismatch, v.Type())
+		}
+	case av["L"] != nil:
+		l, err := attributeList(av["L"])
+		if err != nil {
+			return err
+		}
+
+		switch v.Kind() {
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), len(l), len(l)))
+		case reflect.Array:
+			if v.Len() < len(l) {
+				return fmt.Errorf("%w: can't set L of length %d into %s", ErrTypeMismatch, len(l), v.Type())
+			}
+		default:
+			return fmt.Errorf("%w: can't set L into %s", ErrTypeMismatch, v.Type())
+		}
+
+		for i, elem := range l {
+			if err := s.fromAttributeValue(elem, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported attribute value: %v", av)
+	}
+
+	return nil
+}
+
+// attributeType returns the type of the attribute value, such as "S".
+func attributeType(av AttributeValue) string {
+	for k := range av {
+		return k
+	}
+
+	return "empty attribute value"
+}
+
+// setNumber parses n according to v's kind and sets it to v.
+func setNumber(v reflect.Value, n string) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(n, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(n, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(n, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("%w: can't set N into %s", ErrTypeMismatch, v.Type())
+	}
+
+	return nil
+}
+
+// attributeInterface converts av into a plain Go value as used by Map. N is
+// converted to float64.
+func attributeInterface(av AttributeValue) (interface{}, error) {
+	switch {
+	case av["NULL"] != nil:
+		return nil, nil
+	case av["S"] != nil:
+		return av["S"], nil
+	case av["BOOL"] != nil:
+		return av["BOOL"], nil
+	case av["N"] != nil:
+		n, _ := av["N"].(string)
+		return strconv.ParseFloat(n, 64)
+	case av["B"] != nil:
+		return attributeBytes(av["B"])
+	case av["M"] != nil:
+		m, err := attributeMap(av["M"])
+		if err != nil {
+			return nil, err
+		}
+
+		out := make(map[string]interface{}, len(m))
+		for k, elem := range m {
+			if out[k], err = attributeInterface(elem); err != nil {
+				return nil, err
+			}
+		}
+
+		return out, nil
+	case av["L"] != nil:
+		l, err := attributeList(av["L"])
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]interface{}, len(l))
+		for i, elem := range l {
+			if out[i], err = attributeInterface(elem); err != nil {
+				return nil, err
+			}
+		}
+
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("unsupported attribute value: %v", av)
+}
+
+// attributeBytes returns the bytes of a B value. Decoded JSON contains base64
+// strings instead of []byte.
+func attributeBytes(b interface{}) ([]byte, error) {
+	switch b := b.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		return base64.StdEncoding.DecodeString(b)
+	}
+
+	return nil, fmt.Errorf("%w: wrong B type: %T", ErrTypeMismatch, b)
+}
+
+// attributeMap returns the items of an M value. Decoded JSON contains
+// map[string]interface{} instead of map[string]AttributeValue.
+func attributeMap(m interface{}) (map[string]AttributeValue, error) {
+	switch m := m.(type) {
+	case map[string]AttributeValue:
+		return m, nil
+	case map[string]interface{}:
+		out := make(map[string]AttributeValue, len(m))
+		for k, elem := range m {
+			av, ok := attributeValue(elem)
+			if !ok {
+				return nil, fmt.Errorf("%w: wrong M item type: %T", ErrTypeMismatch, elem)
+			}
+
+			out[k] = av
+		}
+
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("%w: wrong M type: %T", ErrTypeMismatch, m)
+}
+
+// attributeList returns the items of an L value. Decoded JSON contains
+// []interface{} instead of []AttributeValue.
+func attributeList(l interface{}) ([]AttributeValue, error) {
+	switch l := l.(type) {
+	case []AttributeValue:
+		return l, nil
+	case []interface{}:
+		out := make([]AttributeValue, len(l))
+		for i, elem := range l {
+			av, ok := attributeValue(elem)
+			if !ok {
+				return nil, fmt.Errorf("%w: wrong L item type: %T", ErrTypeMismatch, elem)
+			}
+
+			out[i] = av
+		}
+
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("%w: wrong L type: %T", ErrTypeMismatch, l)
+}
+
+// attributeValue converts v into an AttributeValue if possible.
+func attributeValue(v interface{}) (AttributeValue, bool) {
+	switch v := v.(type) {
+	case AttributeValue:
+		return v, true
+	case map[string]interface{}:
+		return AttributeValue(v), true
+	}
+
+	return nil, false
+}
+
+// DynamoDB converts the given struct to a DynamoDB item. For more info refer
+// to Struct types DynamoDB() method. It panics if s's kind is not struct.
+func DynamoDB(s interface{}) map[string]AttributeValue {
+	return New(s).DynamoDB()
+}
+
+// FromDynamoDB sets the fields of the struct pointed to by dst from the given
+// DynamoDB item. For more info refer to Struct types FromDynamoDB() method.
+// It returns an error if dst is not a pointer to struct.
+func FromDynamoDB(item map[string]AttributeValue, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.FromDynamoDB(item)
+}
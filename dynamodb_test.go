@@ -0,0 +1,102 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type dynamoItem struct {
+	ID      string `dynamodbav:"id"`
+	Count   int
+	Price   float64
+	Active  bool
+	Data    []byte
+	Tags    []string
+	Attrs   map[string]int
+	Owner   *dynamoOwner
+	Created time.Time
+	Note    string `dynamodbav:",omitempty"`
+	Skip    string `dynamodbav:"-"`
+}
+
+type dynamoOwner struct {
+	Name string `dynamodbav:"name"`
+}
+
+func TestDynamoDB(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	item := dynamoItem{
+		ID:      "42",
+		Count:   3,
+		Price:   1.5,
+		Active:  true,
+		Data:    []byte("data"),
+		Tags:    []string{"a"},
+		Owner:   &dynamoOwner{Name: "fatih"},
+		Created: created,
+		Skip:    "skip",
+	}
+
+	s := New(item)
+	s.TagName = "dynamodbav"
+	m := s.DynamoDB()
+
+	want := map[string]AttributeValue{
+		"id":      {"S": "42"},
+		"Count":   {"N": "3"},
+		"Price":   {"N": "1.5"},
+		"Active":  {"BOOL": true},
+		"Data":    {"B": []byte("data")},
+		"Tags":    {"L": []AttributeValue{{"S": "a"}}},
+		"Attrs":   {"NULL": true},
+		"Owner":   {"M": map[string]AttributeValue{"name": {"S": "fatih"}}},
+		"Created": {"S": "2020-01-02T03:04:05Z"},
+	}
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("DynamoDB should return\n%v\ngot\n%v", want, m)
+	}
+
+	var out dynamoItem
+	d := New(&out)
+	d.TagName = "dynamodbav"
+
+	if err := d.FromDynamoDB(m); err != nil {
+		t.Fatal(err)
+	}
+
+	item.Skip = ""
+	if !reflect.DeepEqual(out, item) {
+		t.Errorf("FromDynamoDB should return\n%+v\ngot\n%+v", item, out)
+	}
+}
+
+func TestFromDynamoDB_JSON(t *testing.T) {
+	data := `{
+		"Count": {"N": "7"},
+		"Data": {"B": "ZGF0YQ=="},
+		"Attrs": {"M": {"x": {"N": "1"}}},
+		"Tags": {"L": [{"S": "a"}, {"S": "b"}]}
+	}`
+
+	var item map[string]AttributeValue
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatal(err)
+	}
+
+	var out dynamoItem
+	if err := FromDynamoDB(item, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Count != 7 || string(out.Data) != "data" || out.Attrs["x"] != 1 ||
+		!reflect.DeepEqual(out.Tags, []string{"a", "b"}) {
+		t.Errorf("FromDynamoDB should decode JSON items, got: %+v", out)
+	}
+
+	if err := FromDynamoDB(map[string]AttributeValue{"Count": {"S": "x"}}, &out); err == nil {
+		t.Error("FromDynamoDB should return an error for mismatching types")
+	}
+}
@@ -0,0 +1,470 @@
+package structs
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// EnvTagName is the tag name which gives the name of the environment
+	// variable of a field, such as `env:"PORT"`, for LoadEnv.
+	EnvTagName = "env"
+
+	// DefaultValueTagName is the tag name which gives the default value of a
+	// field, such as `default:"8080"`, in the same format as the environment
+	// variables read by LoadEnv.
+	DefaultValueTagName = "default"
+
+	// ErrRequired is returned if a required value is missing.
+	ErrRequired = errors.New("required value is missing")
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// LoadEnv sets the fields of s from environment variables. The name of a
+// field's variable is given in its env tag, or derived from the field's name
+// in SCREAMING_SNAKE_CASE, and is prefixed with the given prefix. Fields
+// tagged with "-" are ignored:
+//
+//   type Config struct {
+//       // read from APP_PORT, 8080 if it's not set
+//       Port int `default:"8080"`
+//
+//       // read from APP_DATABASE_URL, it's an error if it's not set
+//       DatabaseURL string `env:"DATABASE_URL,required"`
+//
+//       // read from APP_TIMEOUT, such as "1m30s"
+//       Timeout time.Duration
+//
+//       // read from APP_HOSTS, such as "a,b,c"
+//       Hosts []string
+//
+//       // read from APP_LABELS, such as "env:prod,team:core"
+//       Labels map[string]string
+//
+//       // read from APP_DB_USER, APP_DB_PASSWORD, ...
+//       DB Database `env:"DB"`
+//   }
+//
+//   err := structs.New(&cfg).LoadEnv("APP_")
+//
+// Variables which are set, even to the empty string, override the field's
+// value, otherwise the field's default tag is used if it has one. Values are
+// parsed as in FromRedisHash, except for durations, which are parsed with
+// time.ParseDuration, and slices, arrays and maps, whose elements are
+// separated by commas. Arrays must be given all of their elements. Nested
+// structs are loaded with their field's name followed by an underscore as a
+// prefix. Nil pointers to them are only allocated, and their required fields
+// only checked, if at least one of their fields is set. It returns an error
+// if s was not created from a pointer. Invalid values and missing required
+// variables are reported with a FieldErrors.
+func (s *Struct) LoadEnv(prefix string) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	var errs FieldErrors
+	s.loadStrings(envSource(true), prefix, &errs)
+
+	return errs.err()
+}
+
+// stringSource describes a source of values given as strings, such as the
+// environment, whose fields are read by loadStrings.
+type stringSource struct {
+	// name returns the name of the value of the field and its tag options.
+	// The boolean is false if the field is ignored.
+	name func(field reflect.StructField, prefix string) (string, tagOptions, bool)
+
+	// sep separates the name of nested structs from their fields.
+	sep string
+
+	// lookup returns the value of the field with the given name. The
+	// boolean is false if there is no value.
+	lookup func(field reflect.StructField, name string) (string, bool, error)
+
+	// required enables the "required" tag option.
+	required bool
+
+	// zeroOnly restricts the source to the fields which are zero.
+	zeroOnly bool
+
+	// sigil is the prefix of names in errors, such as "$" for variables.
+	sigil string
+}
+
+// envSource returns the source of the environment variables, falling back
+// to the default tags if defaults is true.
+func envSource(defaults bool) stringSource {
+	return stringSource{
+		name: envName,
+		sep:  "_",
+		lookup: func(field reflect.StructField, name string) (string, bool, error) {
+			str, ok := os.LookupEnv(name)
+			if !ok && defaults {
+				str, ok = field.Tag.Lookup(DefaultValueTagName)
+			}
+
+			return str, ok, nil
+		},
+		required: defaults,
+		sigil:    "$",
+	}
+}
+
+// loadStrings sets the fields of s from src, where the names of the values
+// start with prefix. It returns the paths of the fields which were set.
+func (s *Struct) loadStrings(src stringSource, prefix string, errs *FieldErrors) []string {
+	var paths []string
+
+	for _, field := range s.structFields() {
+		name, tagOpts, ok := src.name(field, prefix)
+		if !ok {
+			continue
+		}
+
+		v := s.value.FieldByName(field.Name)
+
+		if envNested(field.Type) {
+			nested := reflect.New(indirectType(field.Type))
+			switch {
+			case v.Kind() == reflect.Struct:
+				nested = v.Addr()
+			case !v.IsNil():
+				nested = v
+			}
+
+			var nestedErrs FieldErrors
+			nestedPaths := s.nestedStruct(nested.Interface()).loadStrings(src, name+src.sep, &nestedErrs)
+
+			// nil pointers are optional, unless one of their fields is set
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				if len(nestedPaths) == 0 {
+					nestedErrs = nestedErrs.without(ErrRequired)
+				} else {
+					v.Set(nested)
+				}
+			}
+
+			for _, path := range nestedPaths {
+				paths = append(paths, field.Name+"."+path)
+			}
+
+			if len(nestedErrs) > 0 {
+				errs.add(field.Name, field.Type, src.sigil+name, nestedErrs)
+			}
+			continue
+		}
+
+		if src.zeroOnly && !isZeroValue(v) {
+			continue
+		}
+
+		str, ok, err := src.lookup(field, name)
+		if err != nil {
+			errs.add(field.Name, field.Type, src.sigil+name, err)
+			continue
+		}
+
+		if !ok {
+			if src.required && tagOpts.Has("required") {
+				errs.add(field.Name, field.Type, src.sigil+name, fmt.Errorf("%w: %s is not set", ErrRequired, name))
+			}
+			continue
+		}
+
+		if err := parseEnv(v, str); err != nil {
+			errs.add(field.Name, field.Type, src.sigil+name, err)
+			continue
+		}
+
+		paths = append(paths, field.Name)
+	}
+
+	return paths
+}
+
+// envName returns the name of the environment variable of the given field
+// and its env tag options. The boolean is false if the field is tagged with
+// "-".
+func envName(field reflect.StructField, prefix string) (string, tagOptions, bool) {
+	tag := field.Tag.Get(EnvTagName)
+	if tag == "-" {
+		return "", nil, false
+	}
+
+	name, tagOpts := parseTag(tag)
+	if name == "" {
+		name = KeyScreamingSnakeCase.Convert(field.Name)
+	}
+
+	return prefix + name, tagOpts, true
+}
+
+// envNested reports whether fields of type t are structs, or pointers to
+// structs, whose fields are read from their own variables. Structs
+// implementing encoding.TextUnmarshaler, such as time.Time, are read from a
+// single variable.
+func envNested(t reflect.Type) bool {
+	t = indirectType(t)
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// indirectType returns the type t points to, or t if it's not a pointer.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+
+	return t
+}
+
+// parseEnv parses the value str of an environment variable and sets it to
+// v, as described in LoadEnv.
+func parseEnv(v reflect.Value, str string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return parseEnv(v.Elem(), str)
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return parseString(v, str)
+	}
+
+	switch {
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8:
+		parts := splitList(str)
+
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), len(parts), len(parts)))
+		} else if len(parts) != v.Len() {
+			return fmt.Errorf("%w: got %d elements for %s", ErrTypeMismatch, len(parts), v.Type())
+		}
+
+		for i, part := range parts {
+			if err := parseEnv(v.Index(i), part); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+		return nil
+	case v.Kind() == reflect.Map:
+		parts := splitList(str)
+
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(parts)))
+		for _, part := range parts {
+			k, elem, ok := strings.Cut(part, ":")
+			if !ok {
+				return fmt.Errorf("%w: %q is not a key:value pair", ErrTypeMismatch, part)
+			}
+
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := parseEnv(key, strings.TrimSpace(k)); err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := parseEnv(e, strings.TrimSpace(elem)); err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+
+			v.SetMapIndex(key, e)
+		}
+
+		return nil
+	}
+
+	return parseString(v, str)
+}
+
+// splitList splits a comma separated list, trimming the spaces around its
+// elements. The empty string is an empty list.
+func splitList(str string) []string {
+	if strings.TrimSpace(str) == "" {
+		return nil
+	}
+
+	parts := strings.Split(str, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+
+	return parts
+}
+
+// LoadEnv sets the fields of the struct pointed to by dst from environment
+// variables. For more info refer to Struct types LoadEnv() method. It returns
+// an error if dst is not a pointer to struct.
+func LoadEnv(prefix string, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.LoadEnv(prefix)
+}
+
+// Env returns the environment variables of s, which is the inverse of
+// LoadEnv: the names of the variables are derived from the fields as in
+// LoadEnv, prefixed with the given prefix, and values are formatted so that
+// LoadEnv parses them back. Nested structs are exported with their field's
+// name followed by an underscore as a prefix and nil pointers are left out.
+// Fields whose env tag has the "omitempty" option are left out if they are
+// zero. Sensitive fields are not masked, as the variables are meant for
+// child processes. It panics if a value can't be formatted, such as a slice
+// element containing a comma.
+func (s *Struct) Env(prefix string) map[string]string {
+	out := make(map[string]string)
+	s.env(prefix, out)
+
+	return out
+}
+
+// env adds the environment variables of s to out.
+func (s *Struct) env(prefix string, out map[string]string) {
+	for _, field := range s.structFields() {
+		name, tagOpts, ok := envName(field, prefix)
+		if !ok {
+			continue
+		}
+
+		v := s.value.FieldByName(field.Name)
+
+		if tagOpts.Has("omitempty") && isZeroValue(v) {
+			continue
+		}
+
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			continue
+		}
+
+		if envNested(field.Type) {
+			s.nestedStruct(v.Interface()).env(name+"_", out)
+			continue
+		}
+
+		str, err := formatEnv(v)
+		if err != nil {
+			panic(fmt.Errorf("%s: %w", field.Name, err))
+		}
+
+		out[name] = str
+	}
+}
+
+// Environ returns the environment variables of s as sorted "KEY=value"
+// strings, as used by os/exec.Cmd.Env. For more info refer to Struct types
+// Env() method.
+func (s *Struct) Environ(prefix string) []string {
+	env := s.Env(prefix)
+
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// formatEnv returns v formatted as the value of an environment variable, as
+// described in Env.
+func formatEnv(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+
+		return formatEnv(v.Elem())
+	}
+
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		return formatString(v)
+	}
+
+	element := func(e reflect.Value) (string, error) {
+		str, err := formatEnv(e)
+		if err == nil && strings.Contains(str, ",") {
+			err = fmt.Errorf("%w: %q contains a comma", ErrTypeMismatch, str)
+		}
+
+		return str, err
+	}
+
+	switch {
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			str, err := element(v.Index(i))
+			if err != nil {
+				return "", fmt.Errorf("index %d: %w", i, err)
+			}
+
+			parts[i] = str
+		}
+
+		return strings.Join(parts, ","), nil
+	case v.Kind() == reflect.Map:
+		var parts []string
+		for _, k := range v.MapKeys() {
+			key, err := element(k)
+			if err == nil && strings.Contains(key, ":") {
+				err = fmt.Errorf("%w: %q contains a colon", ErrTypeMismatch, key)
+			}
+			if err != nil {
+				return "", fmt.Errorf("key %v: %w", k, err)
+			}
+
+			elem, err := element(v.MapIndex(k))
+			if err != nil {
+				return "", fmt.Errorf("key %v: %w", k, err)
+			}
+
+			parts = append(parts, key+":"+elem)
+		}
+
+		sort.Strings(parts)
+		return strings.Join(parts, ","), nil
+	}
+
+	return formatString(v)
+}
+
+// Env returns the environment variables of s. For more info refer to Struct
+// types Env() method. It panics if s's kind is not struct.
+func Env(s interface{}, prefix string) map[string]string {
+	return New(s).Env(prefix)
+}
+
+// Environ returns the environment variables of s as "KEY=value" strings.
+// For more info refer to Struct types Environ() method. It panics if s's
+// kind is not struct.
+func Environ(s interface{}, prefix string) []string {
+	return New(s).Environ(prefix)
+}
@@ -0,0 +1,182 @@
+package structs
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type envDatabase struct {
+	User     string
+	Password string `env:"PASS,required"`
+}
+
+type envConfig struct {
+	Port        int    `default:"8080"`
+	DatabaseURL string `env:"DATABASE_URL"`
+	Timeout     time.Duration
+	Hosts       []string
+	Ports       []uint16
+	Labels      map[string]string
+	Addr        netip.Addr
+	Debug       *bool
+	Skip        string `env:"-"`
+	DB          envDatabase
+	Cache       *envDatabase
+	Replica     *envDatabase
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("APP_DATABASE_URL", "postgres://")
+	t.Setenv("APP_TIMEOUT", "1m30s")
+	t.Setenv("APP_HOSTS", "a, b,c")
+	t.Setenv("APP_PORTS", "80,443")
+	t.Setenv("APP_LABELS", "env:prod, team:core")
+	t.Setenv("APP_ADDR", "10.0.0.1")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_SKIP", "x")
+	t.Setenv("APP_DB_USER", "admin")
+	t.Setenv("APP_DB_PASS", "secret")
+	t.Setenv("APP_CACHE_PASS", "")
+
+	var c envConfig
+	if err := LoadEnv("APP_", &c); err != nil {
+		t.Fatal(err)
+	}
+
+	debug := true
+	want := envConfig{
+		Port:        8080,
+		DatabaseURL: "postgres://",
+		Timeout:     90 * time.Second,
+		Hosts:       []string{"a", "b", "c"},
+		Ports:       []uint16{80, 443},
+		Labels:      map[string]string{"env": "prod", "team": "core"},
+		Addr:        netip.MustParseAddr("10.0.0.1"),
+		Debug:       &debug,
+		DB:          envDatabase{User: "admin", Password: "secret"},
+		Cache:       &envDatabase{},
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+}
+
+func TestLoadEnv_Errors(t *testing.T) {
+	t.Setenv("APP_PORT", "http")
+	t.Setenv("APP_TIMEOUT", "10")
+	t.Setenv("APP_PORTS", "80,x")
+
+	var c envConfig
+	err := New(&c).LoadEnv("APP_")
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected FieldErrors, got %v", err)
+	}
+
+	var paths []string
+	for _, e := range fieldErrs {
+		paths = append(paths, e.Path)
+	}
+
+	if want := []string{"Port", "Timeout", "Ports", "DB.Password"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %q want %q", paths, want)
+	}
+
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("expected ErrRequired, got %v", err)
+	}
+
+	if err := LoadEnv("APP_", c); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	debug := false
+	c := envConfig{
+		Port:    80,
+		Timeout: time.Minute,
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]string{"team": "core", "env": "prod"},
+		Addr:    netip.MustParseAddr("::1"),
+		Debug:   &debug,
+		Skip:    "x",
+		DB:      envDatabase{User: "admin", Password: "secret"},
+	}
+
+	want := []string{
+		"APP_ADDR=::1",
+		"APP_DATABASE_URL=",
+		"APP_DB_PASS=secret",
+		"APP_DB_USER=admin",
+		"APP_DEBUG=false",
+		"APP_HOSTS=a,b",
+		"APP_LABELS=env:prod,team:core",
+		"APP_PORT=80",
+		"APP_PORTS=",
+		"APP_TIMEOUT=1m0s",
+	}
+
+	if got := Environ(c, "APP_"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	// LoadEnv parses the variables back
+	for k, v := range Env(c, "APP_") {
+		t.Setenv(k, v)
+	}
+
+	var out envConfig
+	if err := LoadEnv("APP_", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Skip = ""
+	c.Ports = []uint16{}
+	if !reflect.DeepEqual(out, c) {
+		t.Errorf("LoadEnv: got %#v want %#v", out, c)
+	}
+}
+
+func TestEnv_Array(t *testing.T) {
+	type Config struct {
+		Weights [3]float64
+		Names   [2]string
+	}
+
+	c := Config{Weights: [3]float64{0.5, 1, 2}, Names: [2]string{"a", "b"}}
+
+	for k, v := range Env(c, "APP_") {
+		t.Setenv(k, v)
+	}
+
+	var out Config
+	if err := LoadEnv("APP_", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out != c {
+		t.Errorf("got %#v want %#v", out, c)
+	}
+
+	t.Setenv("APP_NAMES", "a,b,c")
+
+	if err := LoadEnv("APP_", &out); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch for a length mismatch, got %v", err)
+	}
+}
+
+func TestEnv_Comma(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic")
+		}
+	}()
+
+	Env(envConfig{Hosts: []string{"a,b"}}, "")
+}
@@ -0,0 +1,155 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrNotStruct is returned if the given value is not a struct or a
+	// pointer to struct.
+	ErrNotStruct = errors.New("not struct")
+
+	// ErrFieldNotFound is returned if a struct has no field with the given
+	// name.
+	ErrFieldNotFound = errors.New("field not found")
+
+	// ErrNotExported is returned when setting a field that is not exported.
+	ErrNotExported = errors.New("field is not exported")
+
+	// ErrNotSettable is returned when setting a field that is not
+	// addressable, i.e. because the struct was not passed as a pointer.
+	ErrNotSettable = errors.New("field is not settable")
+
+	// ErrTypeMismatch is returned if a value's type doesn't match the type
+	// of its destination.
+	ErrTypeMismatch = errors.New("type mismatch")
+
+	// ErrCycle is returned if a struct contains a pointer cycle and the
+	// CycleError policy is used.
+	ErrCycle = errors.New("pointer cycle")
+
+	// ErrUnsupportedKind is returned for values that can't be converted,
+	// such as a chan or a func.
+	ErrUnsupportedKind = errors.New("unsupported kind")
+
+	// ErrDuplicateKey is returned if two fields have the same key and the
+	// DuplicateError policy is used.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrInvalidPath is returned if a path expression is malformed or if a
+	// value expanded by ExpandPath is a dot segment, such as "..".
+	ErrInvalidPath = errors.New("invalid path")
+
+	// ErrIndexOutOfRange is returned if a path expression indexes a slice
+	// or array beyond its length.
+	ErrIndexOutOfRange = errors.New("index out of range")
+
+	// ErrKeyNotFound is returned if a path expression indexes a map with a
+	// key it doesn't hold.
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrNilPointer is returned if a path expression goes through a nil
+	// pointer or interface, or if a row of InsertValues is a nil pointer.
+	ErrNilPointer = errors.New("nil pointer")
+
+	// ErrNoPartitionKey is returned if a struct has no fields tagged with
+	// partition.
+	ErrNoPartitionKey = errors.New("no partition key")
+)
+
+// FieldError describes the failure to set a single field while decoding a
+// value into a struct.
+type FieldError struct {
+	// Path is the dotted path of the field, such as "Owner.Name".
+	Path string
+
+	// Type is the Go type of the field.
+	Type reflect.Type
+
+	// Got describes the type of the offending value, such as "string" or
+	// the DynamoDB attribute type "N".
+	Got string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the error message including the field path and types.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s, got %s): %s", e.Path, e.Type, e.Got, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is a list of field errors. It's returned by the decoding
+// functions, such as FromDynamoDB, so all failing fields are reported at once
+// instead of stopping at the first one.
+type FieldErrors []*FieldError
+
+// Error returns the messages of all field errors separated by "; ".
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the field errors, so errors.Is and errors.As match any of
+// them.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// add appends an error for the field at path. If err is a FieldErrors of a
+// nested struct, its errors are added with their paths prefixed by path.
+func (e *FieldErrors) add(path string, typ reflect.Type, got string, err error) {
+	if nested, ok := err.(FieldErrors); ok {
+		for _, n := range nested {
+			n.Path = path + "." + n.Path
+			*e = append(*e, n)
+		}
+
+		return
+	}
+
+	*e = append(*e, &FieldError{
+		Path: path,
+		Type: typ,
+		Got:  got,
+		Err:  err,
+	})
+}
+
+// without returns the errors of e which don't match target.
+func (e FieldErrors) without(target error) FieldErrors {
+	var out FieldErrors
+	for _, err := range e {
+		if !errors.Is(err, target) {
+			out = append(out, err)
+		}
+	}
+
+	return out
+}
+
+// err returns e as an error, or nil if e is empty.
+func (e FieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
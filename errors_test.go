@@ -0,0 +1,102 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	type A struct {
+		Name string
+		age  int
+	}
+
+	if _, err := MapE(1); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("MapE should return ErrNotStruct, got: %v", err)
+	}
+
+	if err := Scan(&fakeRows{}, A{}); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("Scan should return ErrNotStruct, got: %v", err)
+	}
+
+	s := New(&A{})
+
+	if err := s.Field("Name").Set(1); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Set should return ErrTypeMismatch, got: %v", err)
+	}
+
+	if err := s.Field("age").Set(1); !errors.Is(err, ErrNotExported) {
+		t.Errorf("Set should return ErrNotExported, got: %v", err)
+	}
+
+	if err := New(A{}).Field("Name").Set("fatih"); !errors.Is(err, ErrNotSettable) {
+		t.Errorf("Set should return ErrNotSettable, got: %v", err)
+	}
+
+	if err := FromRedisHash(map[string]string{"Name": "x"}, &struct{ Name chan int }{}); err == nil {
+		t.Error("FromRedisHash should return an error for a chan field")
+	}
+
+	if err := FromDynamoDB(map[string]AttributeValue{"Name": {"N": "1"}}, &A{}); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("FromDynamoDB should return ErrTypeMismatch, got: %v", err)
+	}
+
+	if _, err := MapStrict(struct{ F func() }{}); !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("MapStrict should return ErrUnsupportedKind, got: %v", err)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("Field should panic with ErrFieldNotFound, got: %v", err)
+		}
+	}()
+
+	s.Field("Unknown")
+}
+
+func TestFieldErrors(t *testing.T) {
+	type Owner struct {
+		Name string
+		Age  int
+	}
+
+	type Item struct {
+		ID    int
+		Owner Owner
+	}
+
+	item := map[string]AttributeValue{
+		"ID": {"S": "x"},
+		"Owner": {"M": map[string]AttributeValue{
+			"Name": {"S": "fatih"},
+			"Age":  {"BOOL": true},
+		}},
+	}
+
+	err := FromDynamoDB(item, &Item{})
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("FromDynamoDB should return FieldErrors, got: %v", err)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("FromDynamoDB should report all failing fields, got: %v", errs)
+	}
+
+	want := "ID (int, got S): type mismatch: can't set S into int; " +
+		"Owner.Age (int, got BOOL): type mismatch: can't set BOOL into int"
+	if err.Error() != want {
+		t.Errorf("FieldErrors message should be\n%q\ngot\n%q", want, err.Error())
+	}
+
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Error("FieldErrors should match ErrTypeMismatch")
+	}
+
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "ID" {
+		t.Errorf("FieldErrors should match a *FieldError, got: %v", fe)
+	}
+}
@@ -0,0 +1,32 @@
+package structs
+
+import (
+	"encoding/hex"
+)
+
+var (
+	// ETagTagName is the tag name which leaves volatile fields, such as a
+	// last access time, out of an ETag with `etag:"-"`.
+	ETagTagName = "etag"
+)
+
+// ETag returns a strong entity tag of s for the ETag header of an HTTP
+// response, such as "\"9f86d081884c7d659a2feaa0c55ad015\"". It's the first
+// 16 bytes of Hash256 in hex, quoted, so it changes whenever the output of
+// Map changes. Fields tagged with `etag:"-"` are left out, so they don't
+// change the ETag:
+//
+//   // Seen changes on every request, but not the representation.
+//   Seen time.Time `etag:"-"`
+//
+// The ETag can be compared to the If-None-Match header of a request as is.
+func (s *Struct) ETag() string {
+	sum := s.withState(&callState{skipVolatile: true}).Hash256()
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ETag returns a strong entity tag of the given struct. For more info refer
+// to Struct types ETag() method. It panics if s's kind is not struct.
+func ETag(s interface{}) string {
+	return New(s).ETag()
+}
@@ -0,0 +1,83 @@
+package structs
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type etagAddress struct {
+	City    string
+	Visited time.Time `etag:"-"`
+}
+
+type etagDoc struct {
+	ID      int
+	Body    string
+	Seen    time.Time `etag:"-"`
+	Address etagAddress
+}
+
+func TestETag(t *testing.T) {
+	d := etagDoc{ID: 1, Body: "hello", Address: etagAddress{City: "Berlin"}}
+
+	tag := ETag(d)
+	if len(tag) != 34 || tag[0] != '"' || tag[33] != '"' {
+		t.Fatalf("got %s", tag)
+	}
+
+	d.Seen = time.Now()
+	d.Address.Visited = time.Now()
+	if got := ETag(&d); got != tag {
+		t.Errorf("volatile fields changed the ETag: got %s want %s", got, tag)
+	}
+
+	d.Address.City = "Paris"
+	if got := ETag(d); got == tag {
+		t.Error("ETag didn't change")
+	}
+}
+
+func TestETag_Map(t *testing.T) {
+	d := etagDoc{ID: 1, Seen: time.Now()}
+
+	s := New(d)
+	s.ETag()
+
+	// the volatile fields are only left out of the ETag
+	if _, ok := s.Map()["Seen"]; !ok {
+		t.Error("Seen is missing from Map")
+	}
+}
+
+func TestETag_Concurrent(t *testing.T) {
+	s := New(etagDoc{ID: 1, Seen: time.Now()})
+	tag := s.ETag()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			if got := s.ETag(); got != tag {
+				t.Errorf("got %s want %s", got, tag)
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			if _, ok := s.Map()["Seen"]; !ok {
+				t.Error("Seen is missing from Map")
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+}
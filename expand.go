@@ -0,0 +1,233 @@
+package structs
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Expand replaces the placeholders in the string fields of s, such as after
+// loading a config:
+//
+//   type Config struct {
+//       DataDir string // "${HOME}/app"
+//       LogDir  string // "${DataDir}/logs"
+//       Addr    string // "${HOST:-localhost}:${Port}"
+//       Port    int
+//   }
+//
+// A placeholder ${NAME} is replaced with the value of the field at the path
+// NAME, such as "DataDir" or "DB.Host", or else with the environment
+// variable NAME. ${NAME:-default} is replaced with default if there is no
+// such field or variable, or if its value is empty. Fields which aren't
+// strings are formatted as in Env, string fields are expanded before their
+// value is used. "$$" is replaced with a single "$", other dollar signs are
+// kept as is. Strings, pointers to strings and slices of strings are
+// expanded, including the ones of nested structs, while unexported fields
+// and fields tagged with "-" are ignored. It returns an error if s was not
+// created from a pointer. Placeholders without a value, unterminated ones
+// and fields referring to themselves are reported with a FieldErrors.
+func (s *Struct) Expand() error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	e := &expander{
+		fields: make(map[string]reflect.Value),
+		state:  make(map[string]int),
+	}
+	e.collect(s, "")
+
+	var errs FieldErrors
+	for _, path := range e.paths {
+		v := e.fields[path]
+		if !isStringField(v.Type()) {
+			continue
+		}
+
+		if err := e.expandField(path); err != nil {
+			errs.add(path, v.Type(), "string", err)
+		}
+	}
+
+	return errs.err()
+}
+
+// expander expands the placeholders of the fields of a struct.
+type expander struct {
+	// fields are the fields by their paths, in paths order
+	fields map[string]reflect.Value
+	paths  []string
+
+	// state is 1 while a field is expanded and 2 once it's done
+	state map[string]int
+}
+
+// collect adds the fields of s to e, with their paths prefixed by prefix.
+func (e *expander) collect(s *Struct, prefix string) {
+	for _, field := range s.structFields() {
+		v := s.value.FieldByName(field.Name)
+		path := prefix + field.Name
+
+		e.fields[path] = v
+		e.paths = append(e.paths, path)
+
+		if envNested(field.Type) {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					continue
+				}
+
+				v = v.Elem()
+			}
+
+			e.collect(s.nestedStruct(v.Addr().Interface()), path+".")
+		}
+	}
+}
+
+// isStringField reports whether fields of type t are expanded.
+func isStringField(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.String
+}
+
+// expandField expands the field at path, unless it was already expanded.
+func (e *expander) expandField(path string) error {
+	switch e.state[path] {
+	case 1:
+		return fmt.Errorf("%w: ${%s} refers to itself", ErrCycle, path)
+	case 2:
+		return nil
+	}
+
+	e.state[path] = 1
+	defer func() { e.state[path] = 2 }()
+
+	v := e.fields[path]
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			str, err := e.expand(v.Index(i).String())
+			if err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+
+			v.Index(i).SetString(str)
+		}
+
+		return nil
+	}
+
+	str, err := e.expand(v.String())
+	if err != nil {
+		return err
+	}
+
+	v.SetString(str)
+	return nil
+}
+
+// expand returns str with its placeholders replaced.
+func (e *expander) expand(str string) (string, error) {
+	if !strings.Contains(str, "$") {
+		return str, nil
+	}
+
+	var b strings.Builder
+
+	for {
+		i := strings.IndexByte(str, '$')
+		if i < 0 || i == len(str)-1 {
+			b.WriteString(str)
+			return b.String(), nil
+		}
+
+		b.WriteString(str[:i])
+
+		switch str[i+1] {
+		case '$':
+			b.WriteByte('$')
+			str = str[i+2:]
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			str = str[i+1:]
+			continue
+		}
+
+		end := strings.IndexByte(str[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated placeholder in %q", ErrTypeMismatch, str[i:])
+		}
+
+		name, def, hasDefault := strings.Cut(str[i+2:i+end], ":-")
+
+		val, ok, err := e.lookup(name)
+		if err != nil {
+			return "", err
+		}
+
+		if hasDefault && val == "" {
+			val, ok = def, true
+		}
+
+		if !ok {
+			return "", fmt.Errorf("%w: ${%s} is not set", ErrRequired, name)
+		}
+
+		b.WriteString(val)
+		str = str[i+end+1:]
+	}
+}
+
+// lookup returns the value of the field at path name, or else of the
+// environment variable name.
+func (e *expander) lookup(name string) (string, bool, error) {
+	v, ok := e.fields[name]
+	if !ok {
+		val, ok := os.LookupEnv(name)
+		return val, ok, nil
+	}
+
+	if isStringField(v.Type()) {
+		if err := e.expandField(name); err != nil {
+			return "", false, err
+		}
+	}
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", false, nil
+	}
+
+	str, err := formatEnv(v)
+	if err != nil {
+		return "", false, err
+	}
+
+	return str, true, nil
+}
+
+// Expand replaces the placeholders in the string fields of the struct
+// pointed to by dst. For more info refer to Struct types Expand() method.
+// It returns an error if dst is not a pointer to struct.
+func Expand(dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Expand()
+}
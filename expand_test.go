@@ -0,0 +1,91 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type expandDatabase struct {
+	Host string
+	URL  string
+}
+
+type expandConfig struct {
+	LogDir  string
+	DataDir string
+	Addr    string
+	Port    int
+	Price   string
+	Paths   []string
+	Note    *string
+	DB      expandDatabase
+	Skip    string `structs:"-"`
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("EXPAND_HOME", "/home/ann")
+	t.Setenv("EXPAND_EMPTY", "")
+
+	note := "${EXPAND_EMPTY:-empty}"
+	c := expandConfig{
+		LogDir:  "${DataDir}/logs",
+		DataDir: "${EXPAND_HOME}/app",
+		Addr:    "${EXPAND_HOST:-localhost}:${Port}",
+		Port:    8080,
+		Price:   "$$5 or $5",
+		Paths:   []string{"${LogDir}/a", "b"},
+		Note:    &note,
+		DB:      expandDatabase{Host: "db", URL: "postgres://${DB.Host}/${EXPAND_EMPTY}"},
+		Skip:    "${EXPAND_HOME}",
+	}
+
+	if err := Expand(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	want := expandConfig{
+		LogDir:  "/home/ann/app/logs",
+		DataDir: "/home/ann/app",
+		Addr:    "localhost:8080",
+		Port:    8080,
+		Price:   "$5 or $5",
+		Paths:   []string{"/home/ann/app/logs/a", "b"},
+		Note:    &note,
+		DB:      expandDatabase{Host: "db", URL: "postgres://db/"},
+		Skip:    "${EXPAND_HOME}",
+	}
+
+	if !reflect.DeepEqual(c, want) || note != "empty" {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	c := expandConfig{
+		LogDir:  "${DataDir}",
+		DataDir: "${LogDir}",
+		Addr:    "${EXPAND_UNSET}",
+		Price:   "${Port",
+	}
+
+	err := New(&c).Expand()
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected FieldErrors, got %v", err)
+	}
+
+	var paths []string
+	for _, e := range fieldErrs {
+		paths = append(paths, e.Path)
+	}
+
+	if want := []string{"LogDir", "Addr", "Price"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %q want %q", paths, want)
+	}
+
+	if !errors.Is(err, ErrCycle) || !errors.Is(err, ErrRequired) {
+		t.Errorf("expected ErrCycle and ErrRequired, got %v", err)
+	}
+}
@@ -0,0 +1,383 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// FakeTagName is the tag name which selects the kind of data Fake
+	// generates for a field, such as `fake:"email"`.
+	FakeTagName = "fake"
+)
+
+// maxFakeDepth is the number of pointers, slices and maps Fake follows into
+// nested structs, so recursive types are filled with a finite tree.
+const maxFakeDepth = 3
+
+// fakeEpoch is the start of the range of the times generated by Fake.
+var fakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	fakeFirstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Frances", "Grace", "Ken", "Linus", "Margaret", "Radia", "Rob"}
+	fakeLastNames  = []string{"Allen", "Hamilton", "Hopper", "Kernighan", "Knuth", "Liskov", "Lovelace", "Perlman", "Pike", "Ritchie", "Thompson", "Turing"}
+	fakeWords      = []string{"alpha", "bravo", "cloud", "delta", "engine", "forest", "garden", "harbor", "island", "jungle", "kernel", "lemon", "meadow", "network", "orbit", "pixel", "quartz", "river", "signal", "timber"}
+	fakeCities     = []string{"Amsterdam", "Berlin", "Buenos Aires", "Cairo", "Lagos", "Lisbon", "Mumbai", "Osaka", "Oslo", "Seoul", "Sydney", "Toronto"}
+	fakeCountries  = []string{"Argentina", "Australia", "Canada", "Egypt", "Germany", "India", "Japan", "Netherlands", "Nigeria", "Norway", "Portugal", "South Korea"}
+)
+
+// fakers generate the values of the kinds which can be given in the fake
+// tag.
+var fakers = map[string]func(r *rand.Rand) string{
+	"first_name": func(r *rand.Rand) string { return pick(r, fakeFirstNames) },
+	"last_name":  func(r *rand.Rand) string { return pick(r, fakeLastNames) },
+	"name": func(r *rand.Rand) string {
+		return pick(r, fakeFirstNames) + " " + pick(r, fakeLastNames)
+	},
+	"username": func(r *rand.Rand) string {
+		return strings.ToLower(pick(r, fakeFirstNames)) + strconv.Itoa(r.IntN(100))
+	},
+	"email": func(r *rand.Rand) string {
+		return strings.ToLower(pick(r, fakeFirstNames)+"."+pick(r, fakeLastNames)) + "@example.com"
+	},
+	"word": func(r *rand.Rand) string { return pick(r, fakeWords) },
+	"sentence": func(r *rand.Rand) string {
+		words := make([]string, 4+r.IntN(5))
+		for i := range words {
+			words[i] = pick(r, fakeWords)
+		}
+
+		s := strings.Join(words, " ")
+		return strings.ToUpper(s[:1]) + s[1:] + "."
+	},
+	"url": func(r *rand.Rand) string {
+		return "https://example.com/" + pick(r, fakeWords) + "/" + strconv.Itoa(r.IntN(1000))
+	},
+	"uuid": func(r *rand.Rand) string {
+		var b [16]byte
+		for i := range b {
+			b[i] = byte(r.UintN(256))
+		}
+
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	},
+	"phone": func(r *rand.Rand) string {
+		return fmt.Sprintf("+1-555-%03d-%04d", r.IntN(1000), r.IntN(10000))
+	},
+	"ipv4": func(r *rand.Rand) string {
+		// the TEST-NET-1 block of RFC 5737
+		return fmt.Sprintf("192.0.2.%d", 1+r.IntN(254))
+	},
+	"city":    func(r *rand.Rand) string { return pick(r, fakeCities) },
+	"country": func(r *rand.Rand) string { return pick(r, fakeCountries) },
+}
+
+// pick returns a random element of list.
+func pick(r *rand.Rand, list []string) string {
+	return list[r.IntN(len(list))]
+}
+
+// fakeLimits are the bounds given by the validate tag of a field. They limit
+// the value of numbers and the length of strings, slices and maps.
+type fakeLimits struct {
+	min, max *float64
+	oneof    []string
+}
+
+// parseFakeLimits returns the limits given by the rules of the validate tag.
+func parseFakeLimits(tag string) fakeLimits {
+	var l fakeLimits
+	for _, r := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(r, "=")
+
+		if name == "oneof" {
+			l.oneof = strings.Fields(arg)
+			continue
+		}
+
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			continue
+		}
+
+		switch name {
+		case "min":
+			l.min = &n
+		case "max":
+			l.max = &n
+		case "len":
+			l.min, l.max = &n, &n
+		}
+	}
+
+	return l
+}
+
+// bounds returns the range of the limits, using def as the default range.
+// A range whose bounds contradict each other is fixed by its lower bound.
+func (l fakeLimits) bounds(def [2]float64) (lo, hi float64) {
+	lo, hi = def[0], def[1]
+
+	switch {
+	case l.min != nil && l.max != nil:
+		lo, hi = *l.min, *l.max
+	case l.min != nil:
+		lo = *l.min
+		hi = lo + (def[1] - def[0])
+	case l.max != nil:
+		hi = *l.max
+		lo = math.Min(def[0], hi)
+	}
+
+	if hi < lo {
+		hi = lo
+	}
+
+	return lo, hi
+}
+
+// Fake fills every field of s with random, plausible data, such as for test
+// fixtures or load-test payloads. Values are generated by type:
+//
+//   - strings are random words and bools, numbers, times and durations
+//     random values of their type
+//   - pointers are allocated, slices and maps get one to three elements
+//   - nested structs are filled recursively. Pointers, slices and maps of
+//     structs more than three levels deep are left empty, so recursive
+//     types end.
+//   - wrappers registered with RegisterWrapper are set from a random inner
+//     value
+//   - interfaces, chans, funcs and other types implementing
+//     encoding.TextUnmarshaler are left as is
+//
+// The fake tag selects the kind of a string, such as `fake:"email"`, and
+// the value is parsed as in LoadEnv for fields of other types, so
+// `fake:"uuid"` works for a uuid.UUID and `fake:"ipv4"` for a netip.Addr.
+// The elements of slices are generated with the kind of their field. The
+// kinds are "name", "first_name", "last_name", "username", "email",
+// "word", "sentence", "url", "uuid", "phone", "ipv4", "city" and
+// "country". String fields without a fake tag whose name is one of these
+// kinds in snake_case, such as Email or FirstName, use that kind. Fields
+// tagged with `fake:"-"` are left as is.
+//
+// The min, max, len and oneof rules of the validate tag, as described in
+// JSONSchema, limit the generated values, so fixtures of validated structs
+// pass their validation.
+//
+// The given source of randomness makes the data reproducible. A random
+// source is used if r is nil. It returns an error if s was not created from
+// a pointer. Unknown kinds and values that can't be parsed are reported with
+// a FieldErrors.
+func (s *Struct) Fake(r *rand.Rand) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	if r == nil {
+		r = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	var errs FieldErrors
+	s.fake(r, 0, &errs)
+
+	return errs.err()
+}
+
+// fake fills the fields of s, which is nested depth levels below the struct
+// passed to Fake.
+func (s *Struct) fake(r *rand.Rand, depth int, errs *FieldErrors) {
+	for _, field := range s.structFields() {
+		kind := field.Tag.Get(FakeTagName)
+		if kind == "-" {
+			continue
+		}
+
+		limits := parseFakeLimits(field.Tag.Get(ValidateTagName))
+
+		// the kind given by the name doesn't respect the limits
+		if kind == "" && limits.min == nil && limits.max == nil &&
+			indirectType(field.Type).Kind() == reflect.String {
+			if name := KeySnakeCase.Convert(field.Name); fakers[name] != nil {
+				kind = name
+			}
+		}
+		v := s.value.FieldByName(field.Name)
+
+		var err error
+		if len(limits.oneof) > 0 {
+			err = parseEnv(v, pick(r, limits.oneof))
+		} else {
+			err = s.fakeValue(r, v, kind, limits, depth)
+		}
+
+		if err != nil {
+			errs.add(field.Name, field.Type, fmt.Sprintf("fake %q", kind), err)
+		}
+	}
+}
+
+// fakeValue sets v to a random value of the given kind, or of its type if
+// kind is empty, within the limits.
+func (s *Struct) fakeValue(r *rand.Rand, v reflect.Value, kind string, limits fakeLimits, depth int) error {
+	t := v.Type()
+
+	if kind != "" && (t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType)) {
+		gen, ok := fakers[kind]
+		if !ok {
+			return fmt.Errorf("unknown fake kind %q", kind)
+		}
+
+		return parseEnv(v, gen(r))
+	}
+
+	if w, ok := registered(t); ok && w.wrap != nil {
+		inner := reflect.New(w.inner).Elem()
+		if err := s.fakeValue(r, inner, kind, limits, depth); err != nil {
+			return err
+		}
+
+		v.Set(w.wrap(inner, true))
+		return nil
+	}
+
+	switch {
+	case t == timeType:
+		d := time.Duration(r.Int64N(int64(5 * 365 * 24 * time.Hour)))
+		v.Set(reflect.ValueOf(fakeEpoch.Add(d).Truncate(time.Second)))
+		return nil
+	case t == durationType:
+		lo, hi := limits.bounds([2]float64{float64(time.Second), float64(time.Hour)})
+		v.SetInt(int64(lo + r.Float64()*(hi-lo)))
+		return nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if depth >= maxFakeDepth && envNested(t) {
+			return nil
+		}
+
+		elem := reflect.New(t.Elem())
+		if err := s.fakeValue(r, elem.Elem(), kind, limits, depth); err != nil {
+			return err
+		}
+
+		v.Set(elem)
+	case reflect.Bool:
+		v.SetBool(r.IntN(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lo, hi := limits.bounds([2]float64{0, 1000})
+		max := float64(int64(1)<<(t.Bits()-1) - 1)
+		lo, hi = math.Max(lo, -max-1), math.Max(math.Min(hi, max), lo)
+		v.SetInt(int64(math.Ceil(lo)) + r.Int64N(int64(math.Floor(hi))-int64(math.Ceil(lo))+1))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lo, hi := limits.bounds([2]float64{0, 1000})
+		max := math.Ldexp(1, t.Bits()) - 1
+		lo, hi = math.Max(lo, 0), math.Max(math.Min(hi, max), lo)
+		v.SetUint(uint64(math.Ceil(lo)) + r.Uint64N(uint64(math.Floor(hi))-uint64(math.Ceil(lo))+1))
+	case reflect.Float32, reflect.Float64:
+		lo, hi := limits.bounds([2]float64{0, 1000})
+		v.SetFloat(math.Round((lo+r.Float64()*(hi-lo))*100) / 100)
+	case reflect.String:
+		if limits.min == nil && limits.max == nil {
+			v.SetString(pick(r, fakeWords))
+			return nil
+		}
+
+		lo, hi := limits.bounds([2]float64{1, 10})
+		b := make([]byte, int(lo)+r.IntN(int(hi)-int(lo)+1))
+		for i := range b {
+			b[i] = byte('a' + r.IntN(26))
+		}
+
+		v.SetString(string(b))
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, 8+r.IntN(9))
+			for i := range b {
+				b[i] = byte(r.UintN(256))
+			}
+
+			v.SetBytes(b)
+			return nil
+		}
+
+		if depth+1 >= maxFakeDepth && envNested(t.Elem()) {
+			return nil
+		}
+
+		lo, hi := limits.bounds([2]float64{1, 3})
+		n := int(lo) + r.IntN(int(hi)-int(lo)+1)
+
+		slice := reflect.MakeSlice(t, n, n)
+		for i := 0; i < n; i++ {
+			if err := s.fakeValue(r, slice.Index(i), kind, fakeLimits{}, depth+1); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+		v.Set(slice)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := s.fakeValue(r, v.Index(i), kind, fakeLimits{}, depth+1); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+	case reflect.Map:
+		if depth+1 >= maxFakeDepth && envNested(t.Elem()) {
+			return nil
+		}
+
+		lo, hi := limits.bounds([2]float64{1, 3})
+		n := int(lo) + r.IntN(int(hi)-int(lo)+1)
+
+		m := reflect.MakeMapWithSize(t, n)
+		for i := 0; i < n; i++ {
+			key := reflect.New(t.Key()).Elem()
+			elem := reflect.New(t.Elem()).Elem()
+
+			if err := s.fakeValue(r, key, "", fakeLimits{}, depth+1); err != nil {
+				return fmt.Errorf("key: %w", err)
+			}
+
+			if err := s.fakeValue(r, elem, "", fakeLimits{}, depth+1); err != nil {
+				return fmt.Errorf("key %v: %w", key.Interface(), err)
+			}
+
+			m.SetMapIndex(key, elem)
+		}
+
+		v.Set(m)
+	case reflect.Struct:
+		var nestedErrs FieldErrors
+		s.nestedStruct(v.Addr().Interface()).fake(r, depth+1, &nestedErrs)
+
+		if len(nestedErrs) > 0 {
+			return nestedErrs
+		}
+	}
+
+	return nil
+}
+
+// Fake fills every field of the struct pointed to by dst with random,
+// plausible data. For more info refer to Struct types Fake() method. It
+// returns an error if dst is not a pointer to struct.
+func Fake(dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Fake(nil)
+}
@@ -0,0 +1,133 @@
+package structs
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeNode struct {
+	Name     string
+	Children []*fakeNode
+}
+
+type fakeUser struct {
+	ID        int64
+	Email     string
+	FirstName string
+	Phone     string     `fake:"phone"`
+	Addr      netip.Addr `fake:"ipv4"`
+	Aliases   []string   `fake:"username"`
+	Age       uint8      `validate:"min=18,max=99"`
+	Code      string     `validate:"len=4"`
+	Plan      string     `validate:"oneof=free pro"`
+	Score     float64
+	Active    bool
+	Created   time.Time
+	Timeout   time.Duration
+	Labels    map[string]int
+	Tree      fakeNode
+	Manager   *fakeUser
+	Optional  optional[int]
+	Skipped   string `fake:"-"`
+	Handler   func()
+}
+
+func TestFake(t *testing.T) {
+	var u fakeUser
+	if err := New(&u).Fake(rand.New(rand.NewPCG(1, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	if !regexp.MustCompile(`^[a-z]+\.[a-z]+@example\.com$`).MatchString(u.Email) {
+		t.Errorf("Email: got %q", u.Email)
+	}
+
+	if u.FirstName == "" || strings.Contains(u.FirstName, " ") {
+		t.Errorf("FirstName: got %q", u.FirstName)
+	}
+
+	if !strings.HasPrefix(u.Phone, "+1-555-") || !u.Addr.Is4() || len(u.Aliases) == 0 {
+		t.Errorf("got %q, %v and %q", u.Phone, u.Addr, u.Aliases)
+	}
+
+	if u.Age < 18 || u.Age > 99 || len(u.Code) != 4 || (u.Plan != "free" && u.Plan != "pro") {
+		t.Errorf("validate: got %d, %q and %q", u.Age, u.Code, u.Plan)
+	}
+
+	if u.Created.Before(fakeEpoch) || u.Timeout < time.Second || u.Timeout > time.Hour {
+		t.Errorf("got %v and %v", u.Created, u.Timeout)
+	}
+
+	if len(u.Labels) == 0 || u.Tree.Name == "" || u.Manager == nil || u.Manager.Email == "" {
+		t.Errorf("got %#v", u)
+	}
+
+	if _, ok := u.Optional.get(); !ok {
+		t.Error("Optional: expected a valid wrapper")
+	}
+
+	if u.Skipped != "" || u.Handler != nil {
+		t.Errorf("got %q, expected Skipped and Handler to be left as is", u.Skipped)
+	}
+
+	// the same seed generates the same data
+	var again fakeUser
+	if err := New(&again).Fake(rand.New(rand.NewPCG(1, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(again, u) {
+		t.Error("expected the same data for the same seed")
+	}
+}
+
+func TestFake_Recursive(t *testing.T) {
+	var n fakeNode
+	if err := Fake(&n); err != nil {
+		t.Fatal(err)
+	}
+
+	var depth func(n *fakeNode) int
+	depth = func(n *fakeNode) int {
+		d := 0
+		for _, c := range n.Children {
+			if cd := depth(c) + 1; cd > d {
+				d = cd
+			}
+		}
+
+		return d
+	}
+
+	if d := depth(&n); d == 0 || d > maxFakeDepth {
+		t.Errorf("got a depth of %d", d)
+	}
+}
+
+func TestFake_Errors(t *testing.T) {
+	var dst struct {
+		Name  string `fake:"planet"`
+		Count int    `fake:"email"`
+	}
+
+	err := Fake(&dst)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("got %v", err)
+	}
+
+	if errs[0].Path != "Name" || errs[1].Path != "Count" {
+		t.Errorf("got %v", err)
+	}
+
+	if err := Fake(dst); !errors.Is(err, errNotStructPtr) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -1,22 +1,18 @@
 package structs
 
 import (
-	"errors"
+	"database/sql"
 	"fmt"
 	"reflect"
 )
 
-var (
-	errNotExported = errors.New("field is not exported")
-	errNotSettable = errors.New("field is not settable")
-)
-
 // Field represents a single struct field that encapsulates high level
 // functions around the field.
 type Field struct {
 	value      reflect.Value
 	field      reflect.StructField
 	defaultTag string
+	layout     fieldLayout
 }
 
 // Tag returns the value associated with key in the tag string. If there is no
@@ -25,6 +21,13 @@ func (f *Field) Tag(key string) string {
 	return f.field.Tag.Get(key)
 }
 
+// TagOk returns the value associated with key in the tag string. The
+// boolean is false if there is no such key in the tag, so keys with an empty
+// value, such as `pii:""`, can be told apart from missing ones.
+func (f *Field) TagOk(key string) (string, bool) {
+	return f.field.Tag.Lookup(key)
+}
+
 // Value returns the underlying value of the field. It panics if the field
 // is not exported.
 func (f *Field) Value() interface{} {
@@ -42,8 +45,14 @@ func (f *Field) IsExported() bool {
 }
 
 // IsZero returns true if the given field is not initialized (has a zero value).
-// It panics if the field is not exported.
+// Fields of the database/sql Null types and wrapper types registered with
+// RegisterWrapper are zero if they're not valid. It panics if the field is not
+// exported.
 func (f *Field) IsZero() bool {
+	if null, ok := unwrap(f.value); ok {
+		return null == nil
+	}
+
 	zero := reflect.Zero(f.value.Type()).Interface()
 	current := f.Value()
 
@@ -55,29 +64,61 @@ func (f *Field) Name() string {
 	return f.field.Name
 }
 
+// Key returns the key of the given field as it appears in the output of Map,
+// which is the name given in the field's tag or the field's name converted
+// with the KeyCase of the Struct it belongs to. The KeyPrefix and KeySuffix
+// of the Struct are added to it.
+func (f *Field) Key() string {
+	key, _ := f.layout.key(f.field, f.defaultTag)
+	return key
+}
+
 // Kind returns the fields kind, such as "string", "map", "bool", etc ..
 func (f *Field) Kind() reflect.Kind {
 	return f.value.Kind()
 }
 
-// Set sets the field to given value v. It returns an error if the field is not
-// settable (not addressable or not exported) or if the given value's type
-// doesn't match the fields type.
+// Set sets the field to given value v. If the kind of v doesn't match the
+// field's kind, but the field implements sql.Scanner, the field is set with
+// its Scan() method. Numbers, including json.Number, are converted to the
+// field's number type if they fit into it, i.e. float64(42) can be set to an
+// int field, but 1.5 or 300 can't be set to an int8 field. It returns an
+// error if the field is not settable (not addressable or not exported) or if
+// the given value's type doesn't match the fields type.
 func (f *Field) Set(val interface{}) error {
 	// we can't set unexported fields, so be sure this field is exported
 	if !f.IsExported() {
-		return errNotExported
+		return ErrNotExported
 	}
 
 	// do we get here? not sure...
 	if !f.value.CanSet() {
-		return errNotSettable
+		return ErrNotSettable
 	}
 
 	given := reflect.ValueOf(val)
 
+	// convert numbers such as float64 or json.Number decoded from JSON into
+	// the field's number type
+	if isNumberKind(f.value.Kind()) && given.IsValid() && isNumber(given) && given.Type() != f.value.Type() {
+		n, err := convertNumber(given, f.value.Type())
+		if err != nil {
+			return err
+		}
+
+		f.value.Set(n)
+		return nil
+	}
+
+	// let types such as sql.NullString convert the value themselves
 	if f.value.Kind() != given.Kind() {
-		return fmt.Errorf("wrong kind. got: %s want: %s", given.Kind(), f.value.Kind())
+		if scanner, ok := f.value.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(val)
+		}
+	}
+
+	if f.value.Kind() != given.Kind() {
+		return fmt.Errorf("%w: wrong kind. got: %s want: %s", ErrTypeMismatch, given.Kind(), f.value.Kind())
 	}
 
 	f.value.Set(given)
@@ -100,7 +141,7 @@ func (f *Field) Zero() error {
 //
 // It panics if field is not exported or if field's kind is not struct
 func (f *Field) Fields() []*Field {
-	return getFields(f.value, f.defaultTag)
+	return getFields(f.value, f.defaultTag, f.layout)
 }
 
 // Field returns the field from a nested struct. It panics if the nested struct
@@ -108,15 +149,20 @@ func (f *Field) Fields() []*Field {
 func (f *Field) Field(name string) *Field {
 	field, ok := f.FieldOk(name)
 	if !ok {
-		panic("field not found")
+		panic(ErrFieldNotFound)
 	}
 
 	return field
 }
 
 // FieldOk returns the field from a nested struct. The boolean returns whether
-// the field was found (true) or not (false).
+// the field was found (true) or not (false). A nil pointer to a struct has no
+// fields.
 func (f *Field) FieldOk(name string) (*Field, bool) {
+	if f.value.Kind() == reflect.Ptr && f.value.IsNil() {
+		return nil, false
+	}
+
 	value := &f.value
 	// value must be settable so we need to make sure it holds the address of the
 	// variable and not a copy, so we can pass the pointer to strctVal instead of a
@@ -134,8 +180,15 @@ func (f *Field) FieldOk(name string) (*Field, bool) {
 		return nil, false
 	}
 
+	fv, ok := fieldByIndex(v, field.Index)
+	if !ok {
+		return nil, false
+	}
+
 	return &Field{
-		field: field,
-		value: v.FieldByName(name),
+		field:      field,
+		value:      fv,
+		defaultTag: f.defaultTag,
+		layout:     f.layout,
 	}, true
 }
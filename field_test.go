@@ -1,6 +1,7 @@
 package structs
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 )
@@ -99,7 +100,7 @@ func TestField_Set(t *testing.T) {
 	// let's access an unexported field, which should give an error
 	f = s.Field("d")
 	err = f.Set("large")
-	if err != errNotExported {
+	if err != ErrNotExported {
 		t.Error(err)
 	}
 
@@ -142,8 +143,8 @@ func TestField_NotSettable(t *testing.T) {
 
 	s := New(a[4])
 
-	if err := s.Field("A").Set("newValue"); err != errNotSettable {
-		t.Errorf("Trying to set non-settable field should error with %q. Got %q instead.", errNotSettable, err)
+	if err := s.Field("A").Set("newValue"); err != ErrNotSettable {
+		t.Errorf("Trying to set non-settable field should error with %q. Got %q instead.", ErrNotSettable, err)
 	}
 }
 
@@ -184,7 +185,7 @@ func TestField_Zero(t *testing.T) {
 	// let's access an unexported field, which should give an error
 	f = s.Field("d")
 	err = f.Zero()
-	if err != errNotExported {
+	if err != ErrNotExported {
 		t.Error(err)
 	}
 
@@ -268,6 +269,21 @@ func TestField_Tag(t *testing.T) {
 	}
 }
 
+func TestField_TagOk(t *testing.T) {
+	s := New(struct {
+		A string `pii:""`
+		B string
+	}{})
+
+	if v, ok := s.Field("A").TagOk("pii"); !ok || v != "" {
+		t.Errorf("got %q, %v for an empty tag", v, ok)
+	}
+
+	if _, ok := s.Field("B").TagOk("pii"); ok {
+		t.Error("got true for a missing tag")
+	}
+}
+
 func TestField_Value(t *testing.T) {
 	s := newStruct()
 
@@ -395,3 +411,44 @@ func TestField_FieldOk(t *testing.T) {
 		t.Errorf("The value of 'e' should be 'example, got: %s", val)
 	}
 }
+
+func TestField_IsZero_SQLNull(t *testing.T) {
+	type Row struct {
+		Name sql.NullString
+	}
+
+	s := New(&Row{Name: sql.NullString{String: "stale"}})
+	if !s.Field("Name").IsZero() {
+		t.Error("IsZero should be true for an invalid Null type")
+	}
+
+	s = New(&Row{Name: sql.NullString{Valid: true}})
+	if s.Field("Name").IsZero() {
+		t.Error("IsZero should be false for a valid Null type")
+	}
+}
+
+func TestField_Set_SQLScanner(t *testing.T) {
+	type Row struct {
+		Name sql.NullString
+	}
+
+	r := &Row{}
+	s := New(r)
+
+	if err := s.Field("Name").Set("fatih"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Name.Valid || r.Name.String != "fatih" {
+		t.Errorf("Set should use sql.Scanner, got: %+v", r.Name)
+	}
+
+	if err := s.Field("Name").Set(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Name.Valid {
+		t.Errorf("Set with nil should invalidate the Null type, got: %+v", r.Name)
+	}
+}
@@ -0,0 +1,196 @@
+package structs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Fill sets the fields of s from the given map, which is the inverse of Map.
+// Keys are matched with the field names, or the names given in the field's
+// tag, and fields tagged with "-" are ignored. Keys without a matching field
+// are ignored too. Values are assigned as follows:
+//
+//   - values assignable to the field are set as is
+//   - wrapper types registered with RegisterWrapper are set from their
+//     inner value
+//   - numbers, including json.Number, are converted to the field's number
+//     type if they fit into it
+//   - fields implementing sql.Scanner are set with their Scan() method
+//   - strings are set with UnmarshalText() if the field implements
+//     encoding.TextUnmarshaler, such as time.Time or netip.Addr
+//   - map[string]interface{} values fill nested structs and maps
+//   - []interface{} values fill slices and arrays
+//
+// Nil pointers, such as the pointers to nested structs of a zero value
+// config, are allocated on demand. Fields tagged with "flatten" are filled
+// from the same map, just like Map flattens them. If s.Secrets is set, the
+// secrets of the fields with a secret tag are resolved afterwards. It returns
+// an error if s was not created from a pointer. If values can't be assigned
+// to their fields, all of them are reported with a FieldErrors.
+func (s *Struct) Fill(m map[string]interface{}) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	var errs FieldErrors
+
+	for _, field := range s.structFields() {
+		val := s.value.FieldByName(field.Name)
+
+		name, tagOpts := s.key(field)
+
+		if tagOpts.Has("flatten") {
+			if err := s.fillFlatten(val, m); err != nil {
+				errs.add(field.Name, field.Type, "map[string]interface {}", err)
+			}
+			continue
+		}
+
+		v, ok := m[name]
+		if !ok {
+			continue
+		}
+
+		if err := s.assign(val, v); err != nil {
+			errs.add(field.Name, field.Type, fmt.Sprintf("%T", v), err)
+		}
+	}
+
+	if s.Secrets != nil {
+		s.loadStrings(secretSource(context.Background(), s.Secrets), "", &errs)
+	}
+
+	return errs.err()
+}
+
+// fillFlatten fills the flattened struct v from m.
+func (s *Struct) fillFlatten(v reflect.Value, m map[string]interface{}) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: can't flatten %s", ErrTypeMismatch, v.Type())
+	}
+
+	return s.nestedStruct(v.Addr().Interface()).Fill(m)
+}
+
+// assign sets v to val, converting val as described in Fill.
+func (s *Struct) assign(v reflect.Value, val interface{}) error {
+	if ok, err := s.fillWrapper(v, val); ok {
+		return err
+	}
+
+	given := reflect.ValueOf(val)
+
+	if !given.IsValid() {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if given.Type().AssignableTo(v.Type()) {
+		v.Set(given)
+		return nil
+	}
+
+	if scanner, ok := v.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(val)
+	}
+
+	if ok, err := unmarshalText(v, val); ok {
+		return err
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return s.assign(v.Elem(), val)
+	}
+
+	if isNumberKind(v.Kind()) && isNumber(given) {
+		n, err := convertNumber(given, v.Type())
+		if err != nil {
+			return err
+		}
+
+		v.Set(n)
+		return nil
+	}
+
+	switch val := val.(type) {
+	case map[string]interface{}:
+		switch v.Kind() {
+		case reflect.Struct:
+			return s.nestedStruct(v.Addr().Interface()).Fill(val)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				break
+			}
+
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(v.Type(), len(val)))
+			}
+
+			for k, elem := range val {
+				e := reflect.New(v.Type().Elem()).Elem()
+				if err := s.assign(e, elem); err != nil {
+					return fmt.Errorf("key %q: %w", k, err)
+				}
+
+				v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), e)
+			}
+
+			return nil
+		}
+	case []interface{}:
+		switch v.Kind() {
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), len(val), len(val)))
+		case reflect.Array:
+			if v.Len() < len(val) {
+				return fmt.Errorf("%w: can't set %d elements into %s", ErrTypeMismatch, len(val), v.Type())
+			}
+
+			v.Set(reflect.Zero(v.Type()))
+		default:
+			return fmt.Errorf("%w: can't set %T into %s", ErrTypeMismatch, val, v.Type())
+		}
+
+		for i, elem := range val {
+			if err := s.assign(v.Index(i), elem); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+		return nil
+	}
+
+	// named types of the same kind, such as a string into a type Color string
+	if given.Kind() == v.Kind() && given.Type().ConvertibleTo(v.Type()) {
+		v.Set(given.Convert(v.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("%w: can't set %T into %s", ErrTypeMismatch, val, v.Type())
+}
+
+// Fill sets the fields of the struct pointed to by dst from the given map.
+// For more info refer to Struct types Fill() method. It returns an error if
+// dst is not a pointer to struct.
+func Fill(m map[string]interface{}, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Fill(m)
+}
@@ -0,0 +1,98 @@
+package structs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fillConfig struct {
+	Name     string `structs:"name"`
+	Port     int
+	Database *fillDatabase
+	Replicas []*fillDatabase
+	Labels   map[string]string
+	Limits   *fillLimits `structs:",flatten"`
+	Ignored  string      `structs:"-"`
+	Comment  sql.NullString
+}
+
+type fillDatabase struct {
+	Host string
+	Pool *fillPool
+}
+
+type fillPool struct {
+	Size uint8
+}
+
+type fillLimits struct {
+	MaxConns int
+}
+
+func TestFill(t *testing.T) {
+	data := `{
+		"name": "api",
+		"Port": 8080,
+		"Database": {"Host": "db", "Pool": {"Size": 10}},
+		"Replicas": [{"Host": "r1"}, null],
+		"Labels": {"env": "prod"},
+		"MaxConns": 100,
+		"Ignored": "ignored",
+		"Comment": "hello",
+		"Unknown": true
+	}`
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg fillConfig
+	if err := Fill(m, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	want := fillConfig{
+		Name:     "api",
+		Port:     8080,
+		Database: &fillDatabase{Host: "db", Pool: &fillPool{Size: 10}},
+		Replicas: []*fillDatabase{{Host: "r1"}, nil},
+		Labels:   map[string]string{"env": "prod"},
+		Limits:   &fillLimits{MaxConns: 100},
+		Comment:  sql.NullString{String: "hello", Valid: true},
+	}
+
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Fill should return\n%+v\ngot\n%+v", want, cfg)
+	}
+}
+
+func TestFill_Errors(t *testing.T) {
+	m := map[string]interface{}{
+		"Port":     "8080",
+		"Database": map[string]interface{}{"Pool": map[string]interface{}{"Size": 1000}},
+	}
+
+	err := Fill(m, &fillConfig{})
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Fill should report all failing fields, got: %v", err)
+	}
+
+	if errs[0].Path != "Port" || errs[0].Got != "string" {
+		t.Errorf("Fill should report the path and type of the value, got: %+v", errs[0])
+	}
+
+	if errs[1].Path != "Database.Pool.Size" || !strings.Contains(errs[1].Error(), "overflows") {
+		t.Errorf("Fill should report nested paths, got: %v", errs[1])
+	}
+
+	if err := Fill(m, fillConfig{}); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("Fill should require a pointer, got: %v", err)
+	}
+}
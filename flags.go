@@ -0,0 +1,182 @@
+package structs
+
+import (
+	"flag"
+	"reflect"
+)
+
+var (
+	// FlagTagName is the tag name which gives the name of the command-line
+	// flag of a field, such as `flag:"port"`, for RegisterFlags.
+	FlagTagName = "flag"
+
+	// UsageTagName is the tag name which gives the usage message of a
+	// field's command-line flag.
+	UsageTagName = "usage"
+)
+
+// RegisterFlags defines a flag in fs for each field of s, or in
+// flag.CommandLine if fs is nil. Parsing the flags sets the fields directly,
+// so s holds the configuration once fs.Parse returns. The name of a field's
+// flag is given in its flag tag, or derived from the field's name in
+// kebab-case, and is prefixed with the given prefix. Fields tagged with "-"
+// are ignored:
+//
+//   type Config struct {
+//       // -port, defaults to 8080
+//       Port int `usage:"port to listen on"`
+//
+//       // -timeout, such as -timeout 1m30s
+//       Timeout time.Duration
+//
+//       // -host a,b or -host a -host b
+//       Hosts []string `flag:"host"`
+//
+//       // -db-user, -db-password, ...
+//       DB Database `flag:"db"`
+//   }
+//
+//   cfg := Config{Port: 8080}
+//   structs.New(&cfg).RegisterFlags(nil, "")
+//   flag.Parse()
+//
+// The current values of the fields are the flags' defaults and the usage
+// message is given in the usage tag. Values are parsed and formatted as in
+// LoadEnv. A slice flag which is given more than once, such as -host a -host
+// b, appends to the values of its previous occurrences. Nested structs are
+// registered with their flag's name followed by a hyphen as a prefix, nil
+// pointers to them are allocated. It returns an error if s was not created
+// from a pointer. It panics like fs.Var if a flag is defined twice.
+func (s *Struct) RegisterFlags(fs *flag.FlagSet, prefix string) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	s.registerFlags(fs, prefix)
+	return nil
+}
+
+// registerFlags defines the flags of the fields of s in fs.
+func (s *Struct) registerFlags(fs *flag.FlagSet, prefix string) {
+	for _, field := range s.structFields() {
+		name, _, ok := flagName(field, prefix)
+		if !ok {
+			continue
+		}
+
+		v := s.value.FieldByName(field.Name)
+
+		if envNested(field.Type) {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+
+				v = v.Elem()
+			}
+
+			s.nestedStruct(v.Addr().Interface()).registerFlags(fs, name+"-")
+			continue
+		}
+
+		fs.Var(&fieldFlag{v: v}, name, field.Tag.Get(UsageTagName))
+	}
+}
+
+// flagName returns the name of the flag of the given field and its flag tag
+// options. The boolean is false if the field is tagged with "-".
+func flagName(field reflect.StructField, prefix string) (string, tagOptions, bool) {
+	tag := field.Tag.Get(FlagTagName)
+	if tag == "-" {
+		return "", nil, false
+	}
+
+	name, tagOpts := parseTag(tag)
+	if name == "" {
+		name = KeyKebabCase.Convert(field.Name)
+	}
+
+	return prefix + name, tagOpts, true
+}
+
+// fieldFlag is the flag.Value of a field.
+type fieldFlag struct {
+	v   reflect.Value
+	set bool
+
+	// parsed is the value of the field once the flag is set, which is
+	// kept as the field may be changed afterwards, such as by Load
+	parsed string
+}
+
+// String returns the flag's value formatted as in Env.
+func (f *fieldFlag) String() string {
+	// the flag package calls String on zero values
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+
+	if f.set {
+		return f.parsed
+	}
+
+	str, err := formatEnv(f.v)
+	if err != nil {
+		return ""
+	}
+
+	return str
+}
+
+// Set parses str into the field. Slices given more than once are appended
+// to.
+func (f *fieldFlag) Set(str string) error {
+	v := f.v
+	if f.set && v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		// start from the previous occurrences of the flag
+		v = reflect.New(f.v.Type()).Elem()
+		if err := parseEnv(v, f.parsed); err != nil {
+			return err
+		}
+
+		elems := reflect.New(v.Type()).Elem()
+		if err := parseEnv(elems, str); err != nil {
+			return err
+		}
+
+		v.Set(reflect.AppendSlice(v, elems))
+		f.v.Set(v)
+	} else if err := parseEnv(v, str); err != nil {
+		return err
+	}
+
+	parsed, err := formatEnv(f.v)
+	if err != nil {
+		return err
+	}
+
+	f.set, f.parsed = true, parsed
+	return nil
+}
+
+// IsBoolFlag reports whether the field is a bool, so the flag can be given
+// without a value.
+func (f *fieldFlag) IsBoolFlag() bool {
+	return f.v.IsValid() && indirectType(f.v.Type()).Kind() == reflect.Bool
+}
+
+// RegisterFlags defines a flag in fs for each field of the struct pointed to
+// by dst. For more info refer to Struct types RegisterFlags() method. It
+// returns an error if dst is not a pointer to struct.
+func RegisterFlags(fs *flag.FlagSet, prefix string, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.RegisterFlags(fs, prefix)
+}
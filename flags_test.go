@@ -0,0 +1,93 @@
+package structs
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type flagDatabase struct {
+	User string `usage:"database user"`
+}
+
+type flagConfig struct {
+	Port       int `usage:"port to listen on"`
+	Timeout    time.Duration
+	Hosts      []string `flag:"host"`
+	Verbose    bool
+	MaxRetries *int
+	Skip       string `flag:"-"`
+	DB         flagDatabase
+	Replica    *flagDatabase
+}
+
+func TestRegisterFlags(t *testing.T) {
+	c := flagConfig{Port: 8080, Hosts: []string{"default"}}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := RegisterFlags(fs, "", &c); err != nil {
+		t.Fatal(err)
+	}
+
+	err := fs.Parse([]string{
+		"-timeout", "1m", "-host", "a,b", "-host", "c", "-verbose",
+		"-max-retries", "3", "-db-user", "admin", "-replica-user", "ro",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retries := 3
+	want := flagConfig{
+		Port:       8080,
+		Timeout:    time.Minute,
+		Hosts:      []string{"a", "b", "c"},
+		Verbose:    true,
+		MaxRetries: &retries,
+		DB:         flagDatabase{User: "admin"},
+		Replica:    &flagDatabase{User: "ro"},
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+
+	if f := fs.Lookup("skip"); f != nil {
+		t.Error("skip: expected no flag")
+	}
+
+	if f := fs.Lookup("port"); f.DefValue != "8080" || f.Usage != "port to listen on" {
+		t.Errorf("port: got default %q and usage %q", f.DefValue, f.Usage)
+	}
+
+	var usage bytes.Buffer
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+
+	if !strings.Contains(usage.String(), "-db-user value\n    \tdatabase user") {
+		t.Errorf("unexpected usage:\n%s", usage.String())
+	}
+}
+
+func TestRegisterFlags_Invalid(t *testing.T) {
+	var c flagConfig
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+
+	if err := RegisterFlags(fs, "app-", &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Parse([]string{"-app-port", "http"}); err == nil {
+		t.Error("expected an error")
+	}
+
+	if err := RegisterFlags(fs, "", c); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
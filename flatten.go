@@ -0,0 +1,49 @@
+package structs
+
+import "strconv"
+
+// Flatten converts s to a single level map, such as for metric labels,
+// environment variables or flat key/value stores. It's the same as Map, but
+// the keys of nested structs are joined with their parent's key using the
+// given separator, i.e. "Server.Port" for a "." separator or "SERVER__PORT"
+// with the KeyScreamingSnakeCase and a "__" separator. Slices of structs are
+// flattened with the index as the key, i.e. "Users.0.Name". Other values,
+// including slices and maps without structs, are kept as is. Nested structs
+// without fields don't appear in the map.
+func (s *Struct) Flatten(sep string) map[string]interface{} {
+	out := make(map[string]interface{})
+	flatten(out, "", sep, s.Map())
+	return out
+}
+
+// flatten adds v to out with the given key. Nested maps and slices of the
+// output of Map are added with their own keys joined to key with sep.
+func flatten(out map[string]interface{}, key, sep string, v interface{}) {
+	join := func(k string) string {
+		if key == "" {
+			return k
+		}
+
+		return key + sep + k
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			flatten(out, join(k), sep, elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			flatten(out, join(strconv.Itoa(i)), sep, elem)
+		}
+	default:
+		out[key] = v
+	}
+}
+
+// Flatten converts the given struct to a single level map, joining the keys
+// of nested structs with sep. For more info refer to Struct types Flatten()
+// method. It panics if s's kind is not struct.
+func Flatten(s interface{}, sep string) map[string]interface{} {
+	return New(s).Flatten(sep)
+}
@@ -0,0 +1,56 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type flatServer struct {
+	Host string
+	Port int
+}
+
+type flatConfig struct {
+	Name    string
+	Server  flatServer
+	Backup  *flatServer
+	Peers   []flatServer
+	Tags    []string
+	Limits  map[string]int
+	Ignored string `structs:"-"`
+}
+
+func TestFlatten(t *testing.T) {
+	c := flatConfig{
+		Name:   "app",
+		Server: flatServer{"localhost", 80},
+		Peers:  []flatServer{{"a", 1}, {"b", 2}},
+		Tags:   []string{"x"},
+		Limits: map[string]int{"cpu": 2},
+	}
+
+	want := map[string]interface{}{
+		"Name":         "app",
+		"Server.Host":  "localhost",
+		"Server.Port":  80,
+		"Backup":       (*flatServer)(nil),
+		"Peers.0.Host": "a",
+		"Peers.0.Port": 1,
+		"Peers.1.Host": "b",
+		"Peers.1.Port": 2,
+		"Tags":         []string{"x"},
+		"Limits":       map[string]int{"cpu": 2},
+	}
+
+	if m := Flatten(c, "."); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v\nwant %#v", m, want)
+	}
+
+	s := New(c)
+	s.KeyCase = KeyScreamingSnakeCase
+	m := s.Flatten("__")
+
+	if m["SERVER__HOST"] != "localhost" || m["PEERS__1__PORT"] != 2 {
+		t.Errorf("got %#v", m)
+	}
+}
@@ -0,0 +1,114 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// FormatTagName is the tag name which controls how a field's value is
+	// stringified by MapString and RedisHash, such as `format:"%.2f"`.
+	FormatTagName = "format"
+)
+
+// timeLayouts are the names of the time layouts which can be used in the
+// format tag instead of the layout itself.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// MapString converts the given struct to a map[string]string, such as for
+// HTTP headers, query strings or metric labels. Keys and the "-", "omitempty"
+// and "sensitive" options are handled as in Map, nil pointers are left out.
+// Values are formatted as in RedisHash, unless the field has a format tag:
+//
+//   // Price is formatted with fmt.Sprintf, i.e. "9.50"
+//   Price float64 `format:"%.2f"`
+//
+//   // Mask is formatted as hex, i.e. "0xff"
+//   Mask int `format:"%#x"`
+//
+//   // Created is formatted with Format(), the layout is either one of the
+//   // names of the layouts in the time package or a layout itself.
+//   Created time.Time `format:"RFC3339"`
+//   Day     time.Time `format:"2006-01-02"`
+//
+// It panics if a value can't be formatted.
+func (s *Struct) MapString() map[string]string {
+	out := make(map[string]string)
+
+	for _, field := range s.structFields() {
+		if !s.allowed(field) {
+			continue
+		}
+
+		val := s.value.FieldByName(field.Name)
+
+		name, tagOpts := s.key(field)
+
+		if tagOpts.Has("omitempty") && isZeroValue(val) {
+			continue
+		}
+
+		if masked, ok := maskField(s.value.Type(), field, tagOpts, val); ok {
+			out[name] = masked
+			continue
+		}
+
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			continue
+		}
+
+		str, err := formatField(field, val)
+		if err != nil {
+			panic(err)
+		}
+
+		out[name] = str
+	}
+
+	return out
+}
+
+// formatField returns the string representation of the value v of the given
+// field, using the field's format tag if it has one.
+func formatField(field reflect.StructField, v reflect.Value) (string, error) {
+	format := field.Tag.Get(FormatTagName)
+	if format == "" {
+		return formatString(v)
+	}
+
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok && !strings.Contains(format, "%") {
+		if layout, ok := timeLayouts[format]; ok {
+			format = layout
+		}
+
+		return t.Format(format), nil
+	}
+
+	return fmt.Sprintf(format, v.Interface()), nil
+}
+
+// MapString converts the given struct to a map[string]string. For more info
+// refer to Struct types MapString() method. It panics if s's kind is not
+// struct.
+func MapString(s interface{}) map[string]string {
+	return New(s).MapString()
+}
@@ -0,0 +1,53 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type formatted struct {
+	Name    string
+	Price   float64    `format:"%.2f"`
+	Mask    int        `format:"%#x"`
+	Created time.Time  `format:"RFC3339"`
+	Day     *time.Time `format:"2006-01-02"`
+	Count   int
+	Missing *int
+	Note    string `structs:"note,omitempty"`
+}
+
+func TestMapString(t *testing.T) {
+	day := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	f := formatted{
+		Name:    "fatih",
+		Price:   9.5,
+		Mask:    255,
+		Created: day,
+		Day:     &day,
+		Count:   3,
+	}
+
+	want := map[string]string{
+		"Name":    "fatih",
+		"Price":   "9.50",
+		"Mask":    "0xff",
+		"Created": "2020-01-02T03:04:05Z",
+		"Day":     "2020-01-02",
+		"Count":   "3",
+	}
+
+	if m := MapString(f); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v want %#v", m, want)
+	}
+}
+
+func TestRedisHash_Format(t *testing.T) {
+	pairs := RedisHash(struct {
+		Price float64 `format:"%.1f"`
+	}{Price: 1})
+
+	if want := []string{"Price", "1.0"}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("got %q want %q", pairs, want)
+	}
+}
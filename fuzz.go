@@ -0,0 +1,249 @@
+package structs
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// FuzzT is the part of testing.F used by AddFuzzSeeds.
+type FuzzT interface {
+	Add(args ...interface{})
+}
+
+// maxSeedDepth is the number of pointers, slices and maps FuzzSeeds follows
+// into nested structs, so recursive types have a finite number of seeds.
+const maxSeedDepth = 2
+
+// FuzzSeeds returns seed inputs for fuzzing the decoding of s's type, such
+// as with Fill or BindRequest. Each seed is a JSON object shaped like the
+// output of Map, with the keys of s, that holds the zero value of each
+// field, except for one field which holds a boundary value of its type:
+//
+//   - the smallest and largest values of numbers, and values just outside
+//     of them, such as 128 and -129 for an int8
+//   - fractions and numbers as strings for integers
+//   - empty, blank, long, multi-byte and NUL strings
+//   - null, empty and single element slices and maps, and slices with the
+//     boundary values of their elements
+//   - the seeds of nested structs, with their paths
+//   - values of the wrong type, such as a string for a bool
+//
+// The first seed holds the zero values only. Seeds are deterministic and
+// free of duplicates. Fields whose kind can't be encoded as JSON, such as a
+// chan or a func, are left out. It panics if s's kind is not struct.
+func (s *Struct) FuzzSeeds() [][]byte {
+	var seeds [][]byte
+	seen := make(map[string]bool)
+
+	for _, doc := range s.seedDocs(0) {
+		b, err := json.Marshal(doc)
+		if err != nil || seen[string(b)] {
+			continue
+		}
+
+		seen[string(b)] = true
+		seeds = append(seeds, b)
+	}
+
+	return seeds
+}
+
+// seedDocs returns the seed documents of s, the first of which holds the
+// zero values of its fields.
+func (s *Struct) seedDocs(depth int) []map[string]interface{} {
+	type fieldSeeds struct {
+		key string
+
+		// seeds are the values of the field, or the documents of the
+		// struct for flattened fields.
+		seeds   []interface{}
+		flatten bool
+	}
+
+	zero := make(map[string]interface{})
+	var fields []fieldSeeds
+
+	for _, field := range s.structFields() {
+		if _, ok := unsupportedKind(field.Type); ok {
+			continue
+		}
+
+		name, tagOpts := s.key(field)
+
+		if t := indirectType(field.Type); tagOpts.Has("flatten") && t.Kind() == reflect.Struct {
+			docs := s.nestedStruct(reflect.New(t).Interface()).seedDocs(depth)
+
+			fs := fieldSeeds{flatten: true}
+			for k, v := range docs[0] {
+				zero[k] = v
+			}
+
+			for _, doc := range docs[1:] {
+				fs.seeds = append(fs.seeds, doc)
+			}
+
+			fields = append(fields, fs)
+			continue
+		}
+
+		seeds := s.seedValues(field.Type, depth)
+		if tagOpts.Has("string") {
+			seeds = stringSeeds()
+		}
+
+		zero[name] = seeds[0]
+		fields = append(fields, fieldSeeds{key: name, seeds: seeds[1:]})
+	}
+
+	docs := []map[string]interface{}{zero}
+
+	for _, f := range fields {
+		for _, seed := range f.seeds {
+			doc := make(map[string]interface{}, len(zero))
+			for k, v := range zero {
+				doc[k] = v
+			}
+
+			if f.flatten {
+				for k, v := range seed.(map[string]interface{}) {
+					doc[k] = v
+				}
+			} else {
+				doc[f.key] = seed
+			}
+
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs
+}
+
+// seedValues returns the seed values of the type t, the first of which is
+// its zero value.
+func (s *Struct) seedValues(t reflect.Type, depth int) []interface{} {
+	if inner, ok := nullableType(t); ok {
+		if t.Kind() == reflect.Ptr && depth >= maxSeedDepth && envNested(t) {
+			return []interface{}{nil}
+		}
+
+		return append([]interface{}{nil}, s.seedValues(inner, depth)...)
+	}
+
+	if t == timeType {
+		return []interface{}{"0001-01-01T00:00:00Z", "", "2006-01-02T15:04:05Z", "2006-01-02T15:04:05.999999999+23:59", "9999-12-31T23:59:59Z", "not a time", 0}
+	}
+
+	if t == durationType {
+		return []interface{}{0, -1, math.MaxInt64, "1s", 1.5}
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return []interface{}{"", " ", "0", "invalid", 0, nil}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return []interface{}{false, true, "true", 1, nil}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		min, max := int64(-1)<<(t.Bits()-1), int64(1)<<(t.Bits()-1)-1
+		return []interface{}{0, 1, -1, min, max, float64(min) - 1, float64(max) + 1, 1.5, "1", nil}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		max := uint64(1)<<(t.Bits()-1) - 1 + uint64(1)<<(t.Bits()-1)
+		return []interface{}{0, 1, -1, max, float64(max) + 1, 1.5, "1", nil}
+	case reflect.Float32, reflect.Float64:
+		max := math.MaxFloat64
+		if t.Kind() == reflect.Float32 {
+			max = math.MaxFloat32
+		}
+
+		return []interface{}{0, -1, 1.5, max, -max, math.SmallestNonzeroFloat64, "1", nil}
+	case reflect.String:
+		return stringSeeds()
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return []interface{}{nil, "", "AA==", "not base64", []interface{}{}}
+		}
+
+		seeds := []interface{}{nil, []interface{}{}}
+		if depth >= maxSeedDepth && envNested(t.Elem()) {
+			return seeds
+		}
+
+		elems := s.seedValues(t.Elem(), depth+1)
+		for _, e := range elems {
+			seeds = append(seeds, []interface{}{e})
+		}
+
+		// more elements than an array can hold
+		if t.Kind() == reflect.Array {
+			over := make([]interface{}, t.Len()+1)
+			for i := range over {
+				over[i] = elems[0]
+			}
+
+			seeds = append(seeds, over)
+		}
+
+		return append(seeds, elems[0])
+	case reflect.Map:
+		seeds := []interface{}{nil, map[string]interface{}{}}
+		if depth >= maxSeedDepth && envNested(t.Elem()) {
+			return seeds
+		}
+
+		for _, e := range s.seedValues(t.Elem(), depth+1) {
+			seeds = append(seeds, map[string]interface{}{"": e, "key": e})
+		}
+
+		return append(seeds, []interface{}{})
+	case reflect.Struct:
+		docs := s.nestedStruct(reflect.New(t).Interface()).seedDocs(depth + 1)
+
+		seeds := make([]interface{}, 0, len(docs)+2)
+		for _, doc := range docs {
+			seeds = append(seeds, doc)
+		}
+
+		return append(seeds, nil, "")
+	}
+
+	// interfaces allow any value
+	return []interface{}{nil, 0, "", false, []interface{}{}, map[string]interface{}{}}
+}
+
+// stringSeeds returns the seed values of strings.
+func stringSeeds() []interface{} {
+	return []interface{}{"", " ", "a", strings.Repeat("x", 1024), "ü😀", "\x00", "null", 1, nil}
+}
+
+// FuzzSeeds returns seed inputs for fuzzing the decoding of the given
+// struct's type. For more info refer to Struct types FuzzSeeds() method. It
+// panics if s's kind is not struct.
+func FuzzSeeds(s interface{}) [][]byte {
+	return New(s).FuzzSeeds()
+}
+
+// AddFuzzSeeds adds the seeds of FuzzSeeds for the given struct to the seed
+// corpus of f, which is usually a *testing.F:
+//
+//   func FuzzFillUser(f *testing.F) {
+//       structs.AddFuzzSeeds(f, User{})
+//
+//       f.Fuzz(func(t *testing.T, data []byte) {
+//           var m map[string]interface{}
+//           if json.Unmarshal(data, &m) != nil {
+//               return
+//           }
+//
+//           var u User
+//           structs.Fill(m, &u) // must not panic
+//       })
+//   }
+func AddFuzzSeeds(f FuzzT, s interface{}) {
+	for _, seed := range New(s).FuzzSeeds() {
+		f.Add(seed)
+	}
+}
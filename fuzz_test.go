@@ -0,0 +1,109 @@
+package structs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fuzzInner struct {
+	Code string
+}
+
+type fuzzNode struct {
+	Value int
+	Next  *fuzzNode
+}
+
+type fuzzTarget struct {
+	Small   int8
+	Count   uint16
+	Ratio   float32
+	Name    string `structs:"name"`
+	On      bool
+	Created time.Time
+	Timeout time.Duration
+	Tags    []string
+	Pair    [2]int
+	Labels  map[string]int
+	Data    []byte
+	Inner   fuzzInner
+	Meta    fuzzInner `structs:",flatten"`
+	List    *fuzzNode
+	Any     interface{}
+	Age     optional[int]
+	Ch      chan int
+}
+
+func TestFuzzSeeds(t *testing.T) {
+	seeds := FuzzSeeds(fuzzTarget{})
+
+	seen := make(map[string]bool)
+	for _, seed := range seeds {
+		if seen[string(seed)] {
+			t.Errorf("duplicate seed %s", seed)
+		}
+
+		seen[string(seed)] = true
+
+		var m map[string]interface{}
+		if err := json.Unmarshal(seed, &m); err != nil {
+			t.Fatalf("%s: %v", seed, err)
+		}
+
+		if _, ok := m["Ch"]; ok {
+			t.Errorf("unexpected chan in %s", seed)
+		}
+	}
+
+	var zero map[string]interface{}
+	if err := json.Unmarshal(seeds[0], &zero); err != nil {
+		t.Fatal(err)
+	}
+
+	if zero["Small"] != 0.0 || zero["name"] != "" || zero["Code"] != "" || zero["List"] != nil {
+		t.Errorf("zero seed: got %s", seeds[0])
+	}
+
+	for _, want := range []string{
+		`"Small":128`,
+		`"Small":-129`,
+		`"Count":65535`,
+		`"Count":-1`,
+		`"name":"ü😀"`,
+		`"Inner":{"Code":"null"}`,
+		`"Code":"\u0000"`,
+		`"Pair":[0,0,0]`,
+		`"List":{"Next":{"Next":null,"Value":-1}`,
+		`"Labels":{"":1.5,"key":1.5}`,
+		`"Age":"1"`,
+	} {
+		found := false
+		for _, seed := range seeds {
+			if strings.Contains(string(seed), want) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("expected a seed with %s", want)
+		}
+	}
+}
+
+func FuzzFill(f *testing.F) {
+	AddFuzzSeeds(f, fuzzTarget{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var m map[string]interface{}
+		if json.Unmarshal(data, &m) != nil {
+			return
+		}
+
+		// invalid values must be reported as errors, not panics
+		var dst fuzzTarget
+		Fill(m, &dst)
+	})
+}
@@ -0,0 +1,70 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// names holds the field names of the types passed to NamesOf.
+var names sync.Map // map[reflect.Type][]string
+
+// NamesOf returns the field names of the struct type T, such as for column
+// lists, without the need for a value of T. The names are computed once per
+// type. It panics if T's kind is not struct.
+func NamesOf[T any]() []string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	n, ok := names.Load(t)
+	if !ok {
+		n, _ = names.LoadOrStore(t, New(reflect.New(t).Interface()).Names())
+	}
+
+	// the cached slice must not be modified by the caller
+	return append([]string(nil), n.([]string)...)
+}
+
+// FillNew returns a new T filled from the given map. T is either a struct
+// type or a pointer to a struct type, in which case the struct is allocated.
+// For more info refer to Struct types Fill() method. If values can't be
+// assigned, the returned T holds the fields which could be assigned, along
+// with a FieldErrors. It returns an error if T is not a struct or a pointer
+// to struct.
+func FillNew[T any](m map[string]interface{}) (T, error) {
+	var t T
+
+	v := reflect.ValueOf(&t).Elem()
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		return t, Fill(m, v.Interface())
+	}
+
+	return t, Fill(m, &t)
+}
+
+// Get returns the value of the field with the given name or path, i.e.
+// "Server.Port", as a T. It returns the zero value of T if the field is
+// behind a nil pointer. It returns an error wrapping ErrTypeMismatch if the
+// field's value is not a T, ErrFieldNotFound or ErrNotExported for invalid
+// names and ErrNotStruct if s is not a struct or a pointer to struct.
+func Get[T any](s interface{}, name string) (T, error) {
+	var t T
+
+	v, err := structVal(s)
+	if err != nil {
+		return t, err
+	}
+
+	fv, ok, err := fieldByPath(v, name, false)
+	if err != nil || !ok {
+		return t, err
+	}
+
+	t, ok = fv.Interface().(T)
+	if !ok {
+		return t, fmt.Errorf("%w: field %s is %s, not %s", ErrTypeMismatch, name, fv.Type(),
+			reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	return t, nil
+}
@@ -0,0 +1,115 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type genericUser struct {
+	Name  string
+	Age   int
+	Token string `structs:"-"`
+}
+
+func TestNamesOf(t *testing.T) {
+	n := NamesOf[genericUser]()
+	if want := []string{"Name", "Age"}; !reflect.DeepEqual(n, want) {
+		t.Errorf("NamesOf: got %q want %q", n, want)
+	}
+
+	// modifying the result doesn't change the cached names
+	n[0] = "changed"
+	if n := NamesOf[genericUser](); n[0] != "Name" {
+		t.Errorf("NamesOf: got %q", n)
+	}
+}
+
+func TestFillNew(t *testing.T) {
+	m := map[string]interface{}{"Name": "gopher", "Age": float64(12)}
+
+	u, err := FillNew[genericUser](m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (genericUser{Name: "gopher", Age: 12}); u != want {
+		t.Errorf("got %+v want %+v", u, want)
+	}
+
+	p, err := FillNew[*genericUser](m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p == nil || p.Name != "gopher" {
+		t.Errorf("got %+v", p)
+	}
+
+	if _, err := FillNew[int](m); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+
+	u, err = FillNew[genericUser](map[string]interface{}{"Name": "partial", "Age": "old"})
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || u.Name != "partial" {
+		t.Errorf("got %+v, %v", u, err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	type server struct {
+		Port int
+	}
+
+	type config struct {
+		Name    string
+		Server  server
+		Backup  *server
+		Handler interface{}
+		secret  string
+	}
+
+	c := &config{Name: "app", Server: server{80}, Handler: "h"}
+
+	if name, err := Get[string](c, "Name"); err != nil || name != "app" {
+		t.Errorf("got %q, %v", name, err)
+	}
+
+	if port, err := Get[int](*c, "Server.Port"); err != nil || port != 80 {
+		t.Errorf("got %d, %v", port, err)
+	}
+
+	if port, err := Get[int](c, "Backup.Port"); err != nil || port != 0 {
+		t.Errorf("nil pointer: got %d, %v", port, err)
+	}
+
+	if h, err := Get[interface{}](c, "Handler"); err != nil || h != "h" {
+		t.Errorf("got %v, %v", h, err)
+	}
+
+	_, err := Get[int](c, "Name")
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("expected ErrTypeMismatch, got %v", err)
+	}
+
+	if want := "type mismatch: field Name is string, not int"; err.Error() != want {
+		t.Errorf("got %q want %q", err, want)
+	}
+
+	tests := map[string]error{
+		"Missing": ErrFieldNotFound,
+		"secret":  ErrNotExported,
+	}
+
+	for name, want := range tests {
+		if _, err := Get[string](c, name); !errors.Is(err, want) {
+			t.Errorf("%s: got %v want %v", name, err, want)
+		}
+	}
+
+	if _, err := Get[string]("not a struct", "Name"); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
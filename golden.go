@@ -0,0 +1,132 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateGolden makes AssertGolden write the golden files instead of
+// comparing them. It's true if the UPDATE_GOLDEN environment variable is
+// set, and can be bound to a test flag too:
+//
+//   func init() {
+//       flag.BoolVar(&structs.UpdateGolden, "update", false, "update golden files")
+//   }
+var UpdateGolden = os.Getenv("UPDATE_GOLDEN") != ""
+
+// Canonical returns the canonical form of s, which is the output of Map as
+// indented JSON with sorted keys and a trailing newline. It's deterministic
+// for equal structs, so it can be compared with a golden file or diffed in
+// reviews. It returns an error if a value can't be encoded as JSON, such as
+// a chan or a func.
+func (s *Struct) Canonical() ([]byte, error) {
+	b, err := json.MarshalIndent(s.Map(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// Canonical returns the canonical form of the given struct. For more info
+// refer to Struct types Canonical() method. It panics if s's kind is not
+// struct.
+func Canonical(s interface{}) ([]byte, error) {
+	return New(s).Canonical()
+}
+
+// AssertGolden compares the canonical form of s with the golden file at
+// path, such as "testdata/user.golden", and reports the differing lines to
+// t. If UpdateGolden is set, the golden file and its directory are written
+// instead. It returns true if the file matches or was written.
+func AssertGolden(t TestingT, path string, s interface{}) bool {
+	t.Helper()
+
+	got, err := New(s).Canonical()
+	if err != nil {
+		t.Errorf("structs: %s: %v", path, err)
+		return false
+	}
+
+	if UpdateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("structs: %v", err)
+			return false
+		}
+
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("structs: %v", err)
+			return false
+		}
+
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("structs: golden file %s doesn't exist, set UPDATE_GOLDEN=1 to create it", path)
+		return false
+	}
+
+	if err != nil {
+		t.Errorf("structs: %v", err)
+		return false
+	}
+
+	if bytes.Equal(got, want) {
+		return true
+	}
+
+	t.Errorf("structs: %s differs (-want +got):\n%s", path, lineDiff(string(want), string(got)))
+	return false
+}
+
+// lineDiff returns the lines of a and b prefixed by "-" if they're only in
+// a, "+" if they're only in b and " " if they're in both, based on their
+// longest common subsequence.
+func lineDiff(a, b string) string {
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			fmt.Fprintf(&out, "  %s\n", x[i])
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "- %s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", y[j])
+			j++
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,100 @@
+package structs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type goldenUser struct {
+	Name    string
+	Created time.Time
+	Labels  map[string]string
+	Address assertAddress
+}
+
+func TestCanonical(t *testing.T) {
+	u := goldenUser{
+		Name:    "Ann",
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Labels:  map[string]string{"team": "core", "env": "prod"},
+		Address: assertAddress{ID: 1, City: "Oslo"},
+	}
+
+	got, err := Canonical(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "Address": {
+    "City": "Oslo",
+    "ID": 1
+  },
+  "Created": "2024-01-02T03:04:05Z",
+  "Labels": {
+    "env": "prod",
+    "team": "core"
+  },
+  "Name": "Ann"
+}
+`
+
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	var bad struct{ C chan int }
+	if _, err := Canonical(bad); err == nil {
+		t.Error("expected an error for a chan")
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "user.golden")
+	u := goldenUser{Name: "Ann", Address: assertAddress{City: "Oslo"}}
+
+	r := &recorder{}
+	if AssertGolden(r, path, u) || len(r.errors) != 1 || !strings.Contains(r.errors[0], "doesn't exist") {
+		t.Fatalf("missing file: got %q", r.errors)
+	}
+
+	UpdateGolden = true
+	r = &recorder{}
+	ok := AssertGolden(r, path, u)
+	UpdateGolden = false
+
+	if !ok || len(r.errors) != 0 {
+		t.Fatalf("update: got %q", r.errors)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if !AssertGolden(r, path, &u) || len(r.errors) != 0 {
+		t.Fatalf("match: got %q", r.errors)
+	}
+
+	u.Address.City = "Bergen"
+	if AssertGolden(r, path, u) || len(r.errors) != 1 {
+		t.Fatalf("mismatch: got %q", r.errors)
+	}
+
+	for _, line := range []string{`-     "City": "Oslo",`, `+     "City": "Bergen",`, `      "ID": 0`} {
+		if !strings.Contains(r.errors[0], line+"\n") {
+			t.Errorf("expected %q in\n%s", line, r.errors[0])
+		}
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff("a\nb\nc\n", "a\nx\nc\nd\n")
+	want := "  a\n- b\n+ x\n  c\n+ d\n"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
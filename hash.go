@@ -0,0 +1,390 @@
+package structs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Hash returns a 64-bit FNV-1a hash of the output of Map, so equal structs
+// have equal hashes across processes and releases, such as for cache keys or
+// to detect changes. Since it's computed from Map, the tags are honored:
+// fields tagged with "-" or omitted with "omitempty" don't change the hash,
+// the keys given in the tags are hashed instead of the field names and
+// sensitive fields are hashed masked.
+//
+// The values are hashed in a canonical binary form, where each value is
+// prefixed with its kind, so "1" and 1 differ:
+//
+//   - map entries, including the fields of nested structs, are sorted by
+//     their encoded keys, so the order of the fields doesn't matter
+//   - slices and arrays are hashed in order with their length
+//   - numbers are hashed as 64-bit integers or floats, so an int8 and an
+//     int64 holding the same number are equal, and -0 equals 0
+//   - times are hashed as their instant in UTC, so the location doesn't
+//     matter, and other types implementing encoding.TextMarshaler as text
+//   - pointers are hashed as the value they point to, or nil
+//   - structs which are not converted by Map, i.e. with "omitnested", are
+//     hashed by their exported fields in the order they're declared
+//   - values of unsupported kinds, such as a chan or a func, are hashed by
+//     their type only
+//   - pointers, maps and slices which point back to a value being hashed,
+//     such as in omitnested structs, are hashed as a cycle
+//
+// Structs of different types with the same output of Map have the same
+// hash.
+func (s *Struct) Hash() uint64 {
+	h := fnv.New64a()
+	s.writeHash(h)
+	return h.Sum64()
+}
+
+// Hash256 is the same as Hash, but returns a SHA-256 hash, for hashes which
+// must not collide, such as content addresses.
+func (s *Struct) Hash256() [sha256.Size]byte {
+	var sum [sha256.Size]byte
+
+	h := sha256.New()
+	s.writeHash(h)
+	h.Sum(sum[:0])
+	return sum
+}
+
+// writeHash writes the canonical form of the output of Map to h.
+func (s *Struct) writeHash(h hash.Hash) {
+	var e hashEncoder
+	e.value(reflect.ValueOf(s.Map()))
+	h.Write(e.buf.Bytes())
+}
+
+// CanonicalBytes returns the canonical binary form of s, for signatures
+// which are verified by other services, such as with Sign. It's the same for
+// equal structs, regardless of the order of the fields or of map entries. Each value starts with a
+// byte giving its kind, and lengths and numbers are 8 bytes in big endian
+// order:
+//
+//   0  nil
+//   1  bool: 1 byte, 0 or 1
+//   2  int: the value as int64
+//   3  uint: the value as uint64
+//   4  float: the IEEE 754 bits of the value as float64, with -0 as 0
+//   5  complex: the real and imaginary parts as floats
+//   6  string: the length and the UTF-8 bytes
+//   7  bytes: the length and the bytes of a []byte
+//   8  time: the Unix time in seconds and the nanoseconds
+//   9  text: the length and the output of MarshalText
+//   10 list: the length and the values of a slice or array
+//   11 map: the number of entries, and the key and value of each entry,
+//      sorted by the encoded keys, so string keys are sorted by their
+//      length first
+//   12 struct: the name and value of each exported field, as a string and
+//      a value, and a nil after the last one
+//   13 unsupported: the length and the bytes of the name of the type
+//   14 cycle: a pointer, map or slice to a value being encoded
+//
+// Unlike Hash, it's computed from the fields of s rather than from the
+// output of Map, so it only depends on the tags: s and its nested structs
+// are maps whose keys are the names given in the tags or the fields' names.
+// Fields tagged with "-" or omitted with "omitempty" are left out, the
+// fields of flattened structs are merged and omitnested structs are
+// encoded as structs. Sensitive fields are encoded with their value, so
+// they're covered by a signature, and the options of s, such as KeyCase,
+// KeyPrefix or AllowList, as well as the registered maskers don't change
+// the form. For more info on the values refer to Struct types Hash()
+// method.
+func (s *Struct) CanonicalBytes() []byte {
+	e := hashEncoder{tagName: s.TagName}
+
+	if v := reflect.ValueOf(s.raw); v.Kind() == reflect.Ptr {
+		e.enter(v)
+	}
+
+	e.value(s.value)
+	return e.buf.Bytes()
+}
+
+// the kinds the values are prefixed with in the canonical form
+const (
+	hashNil byte = iota
+	hashBool
+	hashInt
+	hashUint
+	hashFloat
+	hashComplex
+	hashString
+	hashBytes
+	hashTime
+	hashText
+	hashList
+	hashMap
+	hashStruct
+	hashUnsupported
+	hashCycle
+)
+
+// hashEncoder encodes values in the canonical form described in Hash.
+type hashEncoder struct {
+	buf bytes.Buffer
+
+	// visiting holds the pointers, maps and slices being encoded, so
+	// cycles, which aren't converted by Map when they're reached through
+	// omitnested fields or interfaces, are encoded as hashCycle.
+	visiting map[visit]bool
+
+	// tagName, if set, encodes structs as maps of their fields as
+	// described in CanonicalBytes.
+	tagName string
+}
+
+// sub returns an encoder for a part of the value being encoded by e, which
+// shares the tracking of cycles.
+func (e *hashEncoder) sub() *hashEncoder {
+	if e.visiting == nil {
+		e.visiting = make(map[visit]bool)
+	}
+
+	return &hashEncoder{visiting: e.visiting, tagName: e.tagName}
+}
+
+// enter marks v, which is a pointer, map or slice, as being encoded. It
+// returns false if v is already being encoded.
+func (e *hashEncoder) enter(v reflect.Value) bool {
+	if e.visiting == nil {
+		e.visiting = make(map[visit]bool)
+	}
+
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if e.visiting[key] {
+		return false
+	}
+
+	e.visiting[key] = true
+	return true
+}
+
+// leave unmarks v after it was encoded.
+func (e *hashEncoder) leave(v reflect.Value) {
+	delete(e.visiting, visit{ptr: v.Pointer(), typ: v.Type()})
+}
+
+// uint writes n as 8 bytes.
+func (e *hashEncoder) uint(n uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	e.buf.Write(b[:])
+}
+
+// bytes writes b with its length.
+func (e *hashEncoder) bytes(kind byte, b []byte) {
+	e.buf.WriteByte(kind)
+	e.uint(uint64(len(b)))
+	e.buf.Write(b)
+}
+
+// float writes f with -0 as 0 and all NaNs alike.
+func (e *hashEncoder) float(f float64) {
+	switch {
+	case f == 0:
+		f = 0
+	case math.IsNaN(f):
+		f = math.NaN()
+	}
+
+	e.uint(math.Float64bits(f))
+}
+
+// value writes v in the canonical form.
+func (e *hashEncoder) value(v reflect.Value) {
+	if !v.IsValid() {
+		e.buf.WriteByte(hashNil)
+		return
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		e.buf.WriteByte(hashTime)
+		e.uint(uint64(t.Unix()))
+		e.uint(uint64(t.Nanosecond()))
+		return
+	}
+
+	if v.Type().Implements(textMarshalerType) && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		if text, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			e.bytes(hashText, text)
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+
+		if !e.enter(v) {
+			e.buf.WriteByte(hashCycle)
+			return
+		}
+		defer e.leave(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.buf.WriteByte(hashNil)
+			return
+		}
+
+		e.value(v.Elem())
+	case reflect.Bool:
+		e.buf.WriteByte(hashBool)
+		if v.Bool() {
+			e.buf.WriteByte(1)
+		} else {
+			e.buf.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.buf.WriteByte(hashInt)
+		e.uint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.buf.WriteByte(hashUint)
+		e.uint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.buf.WriteByte(hashFloat)
+		e.float(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		e.buf.WriteByte(hashComplex)
+		e.float(real(v.Complex()))
+		e.float(imag(v.Complex()))
+	case reflect.String:
+		e.bytes(hashString, []byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.bytes(hashBytes, b)
+			return
+		}
+
+		e.buf.WriteByte(hashList)
+		e.uint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			e.value(v.Index(i))
+		}
+	case reflect.Map:
+		entries := make([]hashEntry, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			ke, ve := e.sub(), e.sub()
+			ke.value(k)
+			ve.value(v.MapIndex(k))
+			entries = append(entries, hashEntry{ke.buf.Bytes(), ve.buf.Bytes()})
+		}
+
+		e.entries(entries)
+	case reflect.Struct:
+		t := v.Type()
+
+		if e.tagName != "" && hasFields(t, "") {
+			e.entries(e.fields(v, nil))
+			return
+		}
+
+		e.buf.WriteByte(hashStruct)
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				e.bytes(hashString, []byte(f.Name))
+				e.value(v.Field(i))
+			}
+		}
+		e.buf.WriteByte(hashNil)
+	default:
+		e.bytes(hashUnsupported, []byte(v.Type().String()))
+	}
+}
+
+// hashEntry is an encoded key and value of a map.
+type hashEntry struct{ key, value []byte }
+
+// entries writes a map with the given entries, sorted by their keys.
+func (e *hashEncoder) entries(entries []hashEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	e.buf.WriteByte(hashMap)
+	e.uint(uint64(len(entries)))
+	for _, entry := range entries {
+		e.buf.Write(entry.key)
+		e.buf.Write(entry.value)
+	}
+}
+
+// fields appends the entries of the fields of the struct v to entries, as
+// described in CanonicalBytes.
+func (e *hashEncoder) fields(v reflect.Value, entries []hashEntry) []hashEntry {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get(e.tagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, tagOpts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+
+		val := v.Field(i)
+		if tagOpts.Has("omitempty") && reflect.DeepEqual(val.Interface(), reflect.Zero(val.Type()).Interface()) {
+			continue
+		}
+
+		ve := e.sub()
+		if tagOpts.Has("omitnested") {
+			ve.tagName = ""
+		} else if nested := reflect.Indirect(val); tagOpts.Has("flatten") && nested.Kind() == reflect.Struct &&
+			hasFields(nested.Type(), "") && !nested.Type().Implements(textMarshalerType) {
+			entries = e.fields(nested, entries)
+			continue
+		}
+
+		ke := e.sub()
+		ke.value(reflect.ValueOf(name))
+		ve.value(val)
+		entries = append(entries, hashEntry{ke.buf.Bytes(), ve.buf.Bytes()})
+	}
+
+	return entries
+}
+
+// Hash returns a 64-bit hash of the given struct. For more info refer to
+// Struct types Hash() method. It panics if s's kind is not struct.
+func Hash(s interface{}) uint64 {
+	return New(s).Hash()
+}
+
+// Hash256 returns a SHA-256 hash of the given struct. For more info refer to
+// Struct types Hash() method. It panics if s's kind is not struct.
+func Hash256(s interface{}) [sha256.Size]byte {
+	return New(s).Hash256()
+}
+
+// CanonicalBytes returns the canonical binary form of the given struct. For
+// more info refer to Struct types CanonicalBytes() method. It panics if s's
+// kind is not struct.
+func CanonicalBytes(s interface{}) []byte {
+	return New(s).CanonicalBytes()
+}
@@ -0,0 +1,162 @@
+package structs
+
+import (
+	"encoding/hex"
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+type hashAddress struct {
+	City string
+	Zip  string `structs:"zip,omitempty"`
+}
+
+type hashUser struct {
+	Name     string
+	Age      int8
+	Password string `structs:"password,sensitive"`
+	Cache    string `structs:"-"`
+	Created  time.Time
+	IP       netip.Addr
+	Tags     []string
+	Labels   map[string]int
+	Address  *hashAddress
+	Score    float64
+	Raw      []byte
+	Meta     hashAddress `structs:",omitnested"`
+	Ch       chan int
+}
+
+type hashUser2 struct {
+	Score    float64
+	Raw      []byte
+	Meta     hashAddress `structs:",omitnested"`
+	Name     string
+	Age      int64
+	Password string `structs:"password,sensitive"`
+	Created  time.Time
+	IP       netip.Addr
+	Tags     []string
+	Labels   map[string]int
+	Address  *hashAddress
+	Ch       chan int
+}
+
+func newHashUser() hashUser {
+	return hashUser{
+		Name:     "Ann",
+		Age:      42,
+		Password: "secret",
+		Cache:    "cache",
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		IP:       netip.MustParseAddr("10.0.0.1"),
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]int{"x": 1, "y": 2, "z": 3},
+		Address:  &hashAddress{City: "Oslo"},
+		Raw:      []byte("raw"),
+		Meta:     hashAddress{City: "Bergen"},
+	}
+}
+
+func TestHash(t *testing.T) {
+	u := newHashUser()
+	h := Hash(u)
+
+	// the hash must not change across releases
+	if h != 0xc963b9389a7e4aa6 {
+		t.Errorf("got %#x", h)
+	}
+
+	equal := []func(u *hashUser){
+		func(u *hashUser) { u.Cache = "other" },
+		func(u *hashUser) { u.Password = "other" },
+		func(u *hashUser) { u.Created = u.Created.In(time.FixedZone("", 3600)) },
+		func(u *hashUser) { u.Score = math.Copysign(0, -1) },
+		func(u *hashUser) { u.Ch = make(chan int) },
+		func(u *hashUser) { u.Labels = map[string]int{"z": 3, "y": 2, "x": 1} },
+	}
+
+	for i, change := range equal {
+		v := newHashUser()
+		change(&v)
+
+		if got := Hash(&v); got != h {
+			t.Errorf("%d: got %#x, want %#x", i, got, h)
+		}
+	}
+
+	differ := []func(u *hashUser){
+		func(u *hashUser) { u.Name = "Bob" },
+		func(u *hashUser) { u.Age = 43 },
+		func(u *hashUser) { u.Created = u.Created.Add(1) },
+		func(u *hashUser) { u.IP = netip.MustParseAddr("10.0.0.2") },
+		func(u *hashUser) { u.Tags = []string{"b", "a"} },
+		func(u *hashUser) { u.Tags = []string{"ab"} },
+		func(u *hashUser) { u.Labels["x"] = 2 },
+		func(u *hashUser) { u.Address = nil },
+		func(u *hashUser) { u.Address.Zip = "0150" },
+		func(u *hashUser) { u.Raw = nil },
+		func(u *hashUser) { u.Meta.City = "Oslo" },
+	}
+
+	for i, change := range differ {
+		v := newHashUser()
+		change(&v)
+
+		if got := Hash(v); got == h {
+			t.Errorf("%d: got the same hash %#x", i, got)
+		}
+	}
+
+	// the order and size of the fields don't matter
+	u2 := hashUser2{
+		Name:    u.Name,
+		Age:     int64(u.Age),
+		Created: u.Created,
+		IP:      u.IP,
+		Tags:    u.Tags,
+		Labels:  u.Labels,
+		Address: u.Address,
+		Raw:     u.Raw,
+		Meta:    u.Meta,
+	}
+
+	if got := Hash(u2); got != h {
+		t.Errorf("got %#x, want %#x", got, h)
+	}
+
+	sum := Hash256(u)
+	if Hash256(u2) != sum || Hash256(hashAddress{}) == sum {
+		t.Errorf("got %s", hex.EncodeToString(sum[:]))
+	}
+}
+
+func TestHash_Cycle(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	type Doc struct {
+		Head *Node `structs:",omitnested"`
+		Any  interface{}
+	}
+
+	a := &Node{Name: "a"}
+	a.Next = &Node{Name: "b", Next: a}
+
+	m := map[string]interface{}{"x": 1}
+	m["self"] = m
+
+	d := Doc{Head: a, Any: m}
+	if Hash(d) != Hash(d) {
+		t.Error("got different hashes for the same struct")
+	}
+
+	a.Next.Name = "c"
+	if h := Hash(d); h == Hash(Doc{Head: &Node{Name: "a"}, Any: m}) {
+		t.Errorf("got the same hash for different cycles: %x", h)
+	}
+}
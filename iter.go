@@ -0,0 +1,57 @@
+package structs
+
+import "iter"
+
+// All returns an iterator over the key/value pairs of the output of Map. The
+// values are converted lazily as the iterator advances, so breaking out of
+// the loop early skips the remaining fields without allocating the map:
+//
+//   for key, value := range structs.New(s).All() {
+//       if key == "Name" {
+//           break
+//       }
+//   }
+//
+// Keys used by multiple fields are handled by the OnDuplicateKey policy,
+// except that with the default DuplicateLastWins all of them are yielded.
+func (s *Struct) All() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		s := s.track()
+
+		seen := make(map[string]bool)
+
+		s.entries(func(field, key string, val interface{}) bool {
+			return yield(s.dedupe(seen, field, key), val)
+		})
+	}
+}
+
+// All returns an iterator over the key/value pairs of the given struct. For
+// more info refer to Struct types All() method. It panics if s's kind is not
+// struct.
+func All(s interface{}) iter.Seq2[string, interface{}] {
+	return New(s).All()
+}
+
+// MapSeq returns an iterator over the given slice of structs (or pointers to
+// structs), yielding each element converted with Map. Unlike MapSlice, the
+// elements are converted one at a time as the iterator advances, so huge
+// slices can be streamed with constant memory. Nil pointer elements are
+// yielded as nil maps. Arrays are accepted too. It panics if s is not a slice
+// of structs.
+func MapSeq(s interface{}) iter.Seq[map[string]interface{}] {
+	v := sliceVal(s)
+
+	return func(yield func(map[string]interface{}) bool) {
+		for i := 0; i < v.Len(); i++ {
+			var m map[string]interface{}
+			if elem := v.Index(i); !isNilPtr(elem) {
+				m = Map(elem.Interface())
+			}
+
+			if !yield(m) {
+				return
+			}
+		}
+	}
+}
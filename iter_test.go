@@ -0,0 +1,90 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type iterServer struct {
+	Host string
+	Port int
+}
+
+type iterConfig struct {
+	Name    string
+	Server  iterServer
+	Address iterServer `structs:",flatten"`
+	Skipped string     `structs:"-"`
+	Empty   string     `structs:",omitempty"`
+}
+
+func TestAll(t *testing.T) {
+	c := iterConfig{
+		Name:    "app",
+		Server:  iterServer{"localhost", 80},
+		Address: iterServer{"example.com", 443},
+	}
+
+	got := make(map[string]interface{})
+	for k, v := range All(c) {
+		got[k] = v
+	}
+
+	if want := Map(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v want %#v", got, want)
+	}
+
+	var keys []string
+	for k := range All(c) {
+		keys = append(keys, k)
+		if k == "Server" {
+			break
+		}
+	}
+
+	if want := []string{"Name", "Server"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got %q want %q", keys, want)
+	}
+}
+
+func TestAll_Duplicate(t *testing.T) {
+	type dup struct {
+		A string `structs:"x"`
+		B string `structs:"x"`
+	}
+
+	s := New(dup{"a", "b"})
+	s.OnDuplicateKey = DuplicateSuffix
+
+	var keys []string
+	for k := range s.All() {
+		keys = append(keys, k)
+	}
+
+	if want := []string{"x", "x2"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got %q want %q", keys, want)
+	}
+}
+
+func TestMapSeq(t *testing.T) {
+	servers := []*iterServer{{"a", 1}, nil, {"c", 3}}
+
+	var got []map[string]interface{}
+	for m := range MapSeq(servers) {
+		got = append(got, m)
+	}
+
+	if want := MapSlice(servers); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v want %#v", got, want)
+	}
+
+	n := 0
+	for range MapSeq([2]iterServer{}) {
+		n++
+		break
+	}
+
+	if n != 1 {
+		t.Errorf("expected to stop after 1 element, got %d", n)
+	}
+}
@@ -0,0 +1,169 @@
+package structs
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ValidateTagName is the tag name of the validation rules which are
+	// added to the schemas generated by JSONSchema and OpenAPI, such as
+	// `validate:"required,min=1"`.
+	ValidateTagName = "validate"
+)
+
+// jsonSchemaDialect is the URI of the JSON Schema dialect of JSONSchema.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document, draft 2020-12, for s's type,
+// such as for client-side validation or contract tests. The schemas of the
+// named structs it refers to are defined in its "$defs" and referenced with
+// "#/$defs/Name". Types, property names and descriptions are derived as
+// described in OpenAPI, except that nullable values allow the "null" type
+// with "anyOf" and numbers have no format.
+//
+// The rules of the validate tag, as used by structsgen and common
+// validation packages, add constraints to the schema:
+//
+//   // listed as required
+//   Name string `validate:"required"`
+//
+//   // minimum and maximum of numbers, minLength and maxLength of strings,
+//   // minItems and maxItems of slices, minProperties and maxProperties of maps
+//   Age  int      `validate:"min=18,max=130"`
+//   Tags []string `validate:"max=10"`
+//
+//   // both bounds at once
+//   Code string `validate:"len=3"`
+//
+//   // enum
+//   Plan string `validate:"oneof=free pro"`
+//
+//   // format
+//   Email string `validate:"email"`
+//
+// The "url" and "uuid" rules set the "uri" and "uuid" formats. Other rules
+// and rules which don't apply to the field's type are ignored.
+func (s *Struct) JSONSchema() *Schema {
+	g := &schemaGen{
+		s:          s,
+		jsonSchema: true,
+		refPrefix:  "#/$defs/",
+		defs:       make(map[string]*Schema),
+	}
+
+	doc := g.object(s)
+	doc.SchemaURI = jsonSchemaDialect
+	doc.Title = s.Name()
+
+	if len(g.defs) > 0 {
+		doc.Defs = g.defs
+	}
+
+	return doc
+}
+
+// schemaRules adds the constraints of the validation rules in tag to the
+// schema p of type t. It returns true if the rules contain "required".
+func schemaRules(p *Schema, t reflect.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required := false
+	for _, r := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(r, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+
+			if name != "max" {
+				p.limit(t, "min", n)
+			}
+
+			if name != "min" {
+				p.limit(t, "max", n)
+			}
+		case "oneof":
+			for _, v := range strings.Fields(arg) {
+				p.Enum = append(p.Enum, enumValue(p, v))
+			}
+		case "email":
+			p.Format = "email"
+		case "url", "uri":
+			p.Format = "uri"
+		case "uuid":
+			p.Format = "uuid"
+		}
+	}
+
+	return required
+}
+
+// limit sets the lower or upper bound, as given by which, of the schema p of
+// type t to n. The bound is the value of numbers and the length of strings,
+// slices, arrays and maps.
+func (p *Schema) limit(t reflect.Type, which string, n float64) {
+	l := int(n)
+
+	var min, max **int
+	switch {
+	case p.Type == "integer" || p.Type == "number":
+		if which == "min" {
+			p.Minimum = &n
+		} else {
+			p.Maximum = &n
+		}
+		return
+	case p.Type == "string" && t.Kind() == reflect.String:
+		min, max = &p.MinLength, &p.MaxLength
+	case p.Type == "array":
+		min, max = &p.MinItems, &p.MaxItems
+	case p.Type == "object" && t.Kind() == reflect.Map:
+		min, max = &p.MinProperties, &p.MaxProperties
+	default:
+		return
+	}
+
+	if which == "min" {
+		*min = &l
+	} else {
+		*max = &l
+	}
+}
+
+// enumValue returns the value v of a oneof rule as a value of the type of
+// the schema p, i.e. a number for integer properties. Values which can't be
+// parsed are kept as strings.
+func enumValue(p *Schema, v string) interface{} {
+	switch p.Type {
+	case "integer":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+
+	return v
+}
+
+// JSONSchema returns a JSON Schema document for the given struct. For more
+// info refer to Struct types JSONSchema() method. It panics if s's kind is
+// not struct.
+func JSONSchema(s interface{}) *Schema {
+	return New(s).JSONSchema()
+}
@@ -0,0 +1,102 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type schemaSignup struct {
+	Email    string            `json:"email" validate:"required,email" desc:"Login email"`
+	Age      *int              `json:"age" validate:"min=18,max=130"`
+	Code     string            `json:"code" validate:"len=3"`
+	Plan     string            `json:"plan" validate:"oneof=free pro"`
+	Level    int               `json:"level" validate:"oneof=1 2 3"`
+	Tags     []string          `json:"tags" validate:"max=10,dive"`
+	Labels   map[string]string `json:"labels" validate:"min=1"`
+	Home     *apiAddress       `json:"home"`
+	Avatar   []byte            `json:"avatar" validate:"max=1024"`
+	Referrer string            `json:"referrer" required:"true" validate:"url"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	s := New(&schemaSignup{})
+	s.TagName = "json"
+
+	doc := s.JSONSchema()
+
+	n := func(f float64) *float64 { return &f }
+	l := func(i int) *int { return &i }
+
+	want := &Schema{
+		SchemaURI: "https://json-schema.org/draft/2020-12/schema",
+		Title:     "schemaSignup",
+		Type:      "object",
+		Properties: map[string]*Schema{
+			"email": {Type: "string", Format: "email", Description: "Login email"},
+			"age": {AnyOf: []*Schema{
+				{Type: "integer", Minimum: n(18), Maximum: n(130)},
+				{Type: "null"},
+			}},
+			"code":   {Type: "string", MinLength: l(3), MaxLength: l(3)},
+			"plan":   {Type: "string", Enum: []interface{}{"free", "pro"}},
+			"level":  {Type: "integer", Enum: []interface{}{int64(1), int64(2), int64(3)}},
+			"tags":   {Type: "array", Items: &Schema{Type: "string"}, MaxItems: l(10)},
+			"labels": {Type: "object", AdditionalProperties: &Schema{Type: "string"}, MinProperties: l(1)},
+			"home": {AnyOf: []*Schema{
+				{Ref: "#/$defs/apiAddress"},
+				{Type: "null"},
+			}},
+			"avatar":   {Type: "string", ContentEncoding: "base64"},
+			"referrer": {Type: "string", Format: "uri"},
+		},
+		Required: []string{"email", "referrer"},
+		Defs: map[string]*Schema{
+			"apiAddress": {
+				Type:       "object",
+				Properties: map[string]*Schema{"city": {Type: "string"}},
+				Required:   []string{"city"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(doc, want) {
+		got, _ := json.MarshalIndent(doc, "", "  ")
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestJSONSchema_JSON(t *testing.T) {
+	got, err := json.Marshal(JSONSchema(apiNode{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"apiNode","type":"object",` +
+		`"properties":{"Children":{"type":"array","items":{"anyOf":[{"$ref":"#/$defs/apiNode"},{"type":"null"}]}},"Name":{"type":"string"}},` +
+		`"$defs":{"apiNode":{"type":"object","properties":{"Children":{"type":"array","items":{"anyOf":[{"$ref":"#/$defs/apiNode"},{"type":"null"}]}},"Name":{"type":"string"}}}}}`
+
+	if string(got) != want {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestOpenAPI_Rules(t *testing.T) {
+	s := New(&schemaSignup{})
+	s.TagName = "json"
+
+	schema, _ := s.OpenAPI()
+
+	age := schema.Properties["age"]
+	if age.Type != "integer" || !age.Nullable || *age.Minimum != 18 || *age.Maximum != 130 {
+		t.Errorf("age: got %#v", age)
+	}
+
+	if want := []string{"email", "referrer"}; !reflect.DeepEqual(schema.Required, want) {
+		t.Errorf("required: got %q want %q", schema.Required, want)
+	}
+
+	if home := schema.Properties["home"]; home.Ref != "#/components/schemas/apiAddress" {
+		t.Errorf("home: got %#v", home)
+	}
+}
@@ -0,0 +1,253 @@
+package structs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	// MaskTagName is the tag name which gives the class of a sensitive
+	// field, such as `mask:"card"`. The value of the field is masked with
+	// the masker registered for the class with RegisterMasker. Fields with a
+	// mask tag are sensitive, even without the "sensitive" option.
+	MaskTagName = "mask"
+)
+
+var (
+	maskersMu sync.RWMutex
+	maskers   = map[string]func(v interface{}) string{
+		"card":  maskCard,
+		"email": maskEmail,
+	}
+)
+
+// RegisterMasker registers the function which masks the values of the
+// sensitive fields of the given class, so the masked output stays useful for
+// debugging. The function is called with the field's value, or nil for nil
+// pointers. The following classes are registered by default:
+//
+//   card  => keeps the last 4 digits, i.e. "************4242"
+//   email => keeps the domain, i.e. "***@example.com"
+//
+// Sensitive fields without a class use the masker registered for the
+// "sensitive" class, i.e. to hash all of them with HashMasker. Fields with a
+// class that's not registered are masked with Mask.
+// Registering the same class again replaces the previous function. It's safe
+// to call RegisterMasker concurrently.
+func RegisterMasker(class string, fn func(v interface{}) string) {
+	maskersMu.Lock()
+	defer maskersMu.Unlock()
+
+	maskers[class] = fn
+}
+
+// RedactionPolicy maps fields to the class of their masker, such as
+// "card", in addition to the tags of the fields. Fields are given by the
+// package name, the type name and the field name, i.e.
+// "billing.Payment.Card". An empty class or a class without a registered
+// masker masks the field with Mask.
+type RedactionPolicy map[string]string
+
+var (
+	policyMu sync.RWMutex
+	policy   RedactionPolicy
+)
+
+// SetRedactionPolicy replaces the redaction policy, so fields of third party
+// or legacy structs can be masked without changing their tags. Fields in the
+// policy are sensitive even without a tag, and their class in the policy
+// takes precedence over their mask tag. A nil policy removes the current
+// one. It's safe to call SetRedactionPolicy concurrently, i.e. when the
+// policy is reloaded at runtime.
+func SetRedactionPolicy(p RedactionPolicy) {
+	c := make(RedactionPolicy, len(p))
+	for k, v := range p {
+		c[k] = v
+	}
+
+	policyMu.Lock()
+	defer policyMu.Unlock()
+
+	policy = c
+}
+
+// LoadRedactionPolicy reads a redaction policy encoded as a JSON object from
+// r and sets it with SetRedactionPolicy:
+//
+//   {
+//     "billing.Payment.Card": "card",
+//     "legacy.User.Password": ""
+//   }
+func LoadRedactionPolicy(r io.Reader) error {
+	var p RedactionPolicy
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return err
+	}
+
+	SetRedactionPolicy(p)
+	return nil
+}
+
+// policyClass returns the class of the given field of the struct type t in
+// the redaction policy. The boolean is false if the policy has no such
+// field.
+func policyClass(t reflect.Type, field reflect.StructField) (string, bool) {
+	return policyClassOf(t.String() + "." + field.Name)
+}
+
+// policyClassOf returns the class of the field with the given name, i.e.
+// "billing.Payment.Card", in the redaction policy. The boolean is false if
+// the policy has no such field.
+func policyClassOf(name string) (string, bool) {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+
+	class, ok := policy[name]
+	return class, ok
+}
+
+// maskClass returns the class of the masker of the given field of the struct
+// type t. The boolean is false if the field is not sensitive.
+func maskClass(t reflect.Type, field reflect.StructField, tagOpts tagOptions) (string, bool) {
+	class, ok := policyClass(t, field)
+	if !ok {
+		class = field.Tag.Get(MaskTagName)
+		if class == "" && !tagOpts.Has("sensitive") {
+			return "", false
+		}
+	}
+
+	if class == "" {
+		class = "sensitive"
+	}
+
+	return class, true
+}
+
+// sensitiveField returns true if the given field of the struct type t is
+// masked in the output of Map.
+func sensitiveField(t reflect.Type, field reflect.StructField, tagOpts tagOptions) bool {
+	_, ok := maskClass(t, field, tagOpts)
+	return ok
+}
+
+// maskField returns the masked value of the given field of the struct type
+// t. The boolean is false if the field is not sensitive.
+func maskField(t reflect.Type, field reflect.StructField, tagOpts tagOptions, val reflect.Value) (string, bool) {
+	class, ok := maskClass(t, field, tagOpts)
+	if !ok {
+		return "", false
+	}
+
+	return maskValue(class, val), true
+}
+
+// MaskValue returns the value v of the sensitive field with the given name,
+// i.e. "billing.Payment.Card", masked as in the output of Map. The class is
+// the one of the field's mask tag, or empty for fields which only have the
+// "sensitive" option. The class in the redaction policy takes precedence,
+// as with Map. It's called by the code generated by structsgen, which
+// doesn't inspect the fields at runtime.
+func MaskValue(name, class string, v interface{}) string {
+	if c, ok := policyClassOf(name); ok {
+		class = c
+	}
+
+	if class == "" {
+		class = "sensitive"
+	}
+
+	return maskValue(class, reflect.ValueOf(v))
+}
+
+// maskValue returns val masked with the masker of the given class, or Mask
+// if the class has no masker.
+func maskValue(class string, val reflect.Value) string {
+	maskersMu.RLock()
+	fn, ok := maskers[class]
+	maskersMu.RUnlock()
+
+	if !ok {
+		return Mask
+	}
+
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return fn(nil)
+		}
+
+		val = val.Elem()
+	}
+
+	if !val.IsValid() {
+		return fn(nil)
+	}
+
+	return fn(val.Interface())
+}
+
+// HashMasker returns a masker which replaces values with a salted hash, so
+// masked values remain correlatable, i.e. log lines of the same user or
+// token can be found, without exposing the values:
+//
+//   structs.RegisterMasker("sensitive", structs.HashMasker(salt))
+//
+// The hash is the hex encoded prefix of the HMAC-SHA256 of the value's
+// default format, i.e. "hash:5f2b7c9e1a3d4b6f". The salt must be kept
+// secret, as values from a small set, such as PINs, can be found by hashing
+// all of them. Nil values are masked with Mask.
+func HashMasker(salt []byte) func(v interface{}) string {
+	return func(v interface{}) string {
+		if v == nil {
+			return Mask
+		}
+
+		h := hmac.New(sha256.New, salt)
+		fmt.Fprint(h, v)
+		return "hash:" + hex.EncodeToString(h.Sum(nil)[:8])
+	}
+}
+
+// maskCard masks all but the last 4 digits of a card number.
+func maskCard(v interface{}) string {
+	if v == nil {
+		return Mask
+	}
+
+	var digits []rune
+	for _, r := range fmt.Sprint(v) {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+
+	if len(digits) <= 4 {
+		return Mask
+	}
+
+	return strings.Repeat("*", len(digits)-4) + string(digits[len(digits)-4:])
+}
+
+// maskEmail masks the local part of an email address.
+func maskEmail(v interface{}) string {
+	if v == nil {
+		return Mask
+	}
+
+	s := fmt.Sprint(v)
+
+	i := strings.LastIndex(s, "@")
+	if i < 0 {
+		return Mask
+	}
+
+	return Mask + s[i:]
+}
@@ -0,0 +1,144 @@
+package structs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type maskPayment struct {
+	Card   string  `mask:"card"`
+	Email  *string `mask:"email"`
+	Phone  string  `mask:"phone"`
+	CVC    string  `mask:"unknown"`
+	Amount int
+}
+
+func TestMaskers(t *testing.T) {
+	RegisterMasker("phone", func(v interface{}) string {
+		s := v.(string)
+		if len(s) < 3 {
+			return Mask
+		}
+
+		return s[:3] + strings.Repeat("*", len(s)-3)
+	})
+	defer func() {
+		maskersMu.Lock()
+		delete(maskers, "phone")
+		maskersMu.Unlock()
+	}()
+
+	email := "gopher@example.com"
+	p := maskPayment{
+		Card:   "4242 4242 4242 4242",
+		Email:  &email,
+		Phone:  "5551234",
+		CVC:    "123",
+		Amount: 10,
+	}
+
+	want := map[string]interface{}{
+		"Card":   "************4242",
+		"Email":  "***@example.com",
+		"Phone":  "555****",
+		"CVC":    "***",
+		"Amount": 10,
+	}
+
+	if m := Map(p); !reflect.DeepEqual(m, want) {
+		t.Errorf("Map: got %#v want %#v", m, want)
+	}
+
+	if m := MapString(p); m["Card"] != "************4242" || m["Email"] != "***@example.com" {
+		t.Errorf("MapString: got %#v", m)
+	}
+
+	r := Redact(p).(maskPayment)
+	if r.Card != "************4242" || r.Email != nil || r.Phone != "555****" || r.Amount != 10 {
+		t.Errorf("Redact: got %+v", r)
+	}
+
+	if m := Map(maskPayment{Card: "42"}); m["Card"] != "***" || m["Email"] != "***" {
+		t.Errorf("short or nil values should be masked entirely: %#v", m)
+	}
+}
+
+type legacyUser struct {
+	Name     string
+	Password string
+	Card     string `mask:"email"`
+}
+
+func TestRedactionPolicy(t *testing.T) {
+	err := LoadRedactionPolicy(strings.NewReader(`{
+		"structs.legacyUser.Password": "",
+		"structs.legacyUser.Card": "card"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetRedactionPolicy(nil)
+
+	u := legacyUser{Name: "gopher", Password: "hunter2", Card: "4242424242424242"}
+
+	want := map[string]interface{}{
+		"Name":     "gopher",
+		"Password": "***",
+		"Card":     "************4242",
+	}
+
+	if m := Map(u); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v want %#v", m, want)
+	}
+
+	if r := Redact(u).(legacyUser); r.Password != "***" || r.Name != "gopher" {
+		t.Errorf("Redact: got %+v", r)
+	}
+
+	SetRedactionPolicy(nil)
+
+	if m := Map(u); m["Password"] != "hunter2" {
+		t.Errorf("removing the policy should unmask the field: %#v", m)
+	}
+
+	if err := LoadRedactionPolicy(strings.NewReader(`[]`)); err == nil {
+		t.Error("expected an error for an invalid policy")
+	}
+}
+
+func TestHashMasker(t *testing.T) {
+	RegisterMasker("sensitive", HashMasker([]byte("salt")))
+	defer func() {
+		maskersMu.Lock()
+		delete(maskers, "sensitive")
+		maskersMu.Unlock()
+	}()
+
+	type login struct {
+		User  string  `structs:",sensitive"`
+		Token *string `structs:",sensitive"`
+	}
+
+	a := Map(login{User: "gopher"})
+	b := Map(login{User: "gopher"})
+	c := Map(login{User: "other"})
+
+	user, _ := a["User"].(string)
+	if !strings.HasPrefix(user, "hash:") || len(user) != len("hash:")+16 {
+		t.Fatalf("unexpected hash %q", user)
+	}
+
+	if a["User"] != b["User"] || a["User"] == c["User"] {
+		t.Errorf("equal values should have equal hashes: %v %v %v", a, b, c)
+	}
+
+	if a["Token"] != "***" {
+		t.Errorf("nil values should be masked: %v", a["Token"])
+	}
+
+	other := HashMasker([]byte("pepper"))("gopher")
+	if other == user {
+		t.Error("hashes with different salts should differ")
+	}
+}
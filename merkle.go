@@ -0,0 +1,134 @@
+package structs
+
+import (
+	"hash/fnv"
+	"reflect"
+	"sort"
+)
+
+// HashNode is a node of the hash tree of a struct returned by HashTree.
+type HashNode struct {
+	// Hash is the hash of the value, which for a struct is computed from
+	// the keys and the hashes of its fields.
+	Hash uint64
+
+	// Fields holds the nodes of the fields of a struct by their key in the
+	// output of Map, or of the entries of a map with string keys. It's nil
+	// for other values.
+	Fields map[string]*HashNode
+}
+
+// HashTree returns the Merkle tree of the hashes of s, in which every
+// nested struct has its own hash computed from the hashes of its fields, so
+// two trees can be compared with Changed by descending only into the
+// branches whose hashes differ, such as for sync engines to skip the
+// unchanged branches of a large document. The tree is built from the output
+// of Map, so it has the same keys and honors the tags the same way as Hash.
+// Maps with string keys have a node for each entry too. Other values are
+// hashed as a whole, as described in Hash. The hash of the root differs
+// from Hash.
+func (s *Struct) HashTree() *HashNode {
+	return hashTree(s.Map())
+}
+
+// hashTree returns the node of v, which is a value of the output of Map.
+func hashTree(v interface{}) *HashNode {
+	m := reflect.ValueOf(v)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		var e hashEncoder
+		e.value(m)
+
+		h := fnv.New64a()
+		h.Write(e.buf.Bytes())
+		return &HashNode{Hash: h.Sum64()}
+	}
+
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	n := &HashNode{Fields: make(map[string]*HashNode, len(keys))}
+
+	var e hashEncoder
+	e.buf.WriteByte(hashMap)
+	e.uint(uint64(len(keys)))
+	for _, k := range keys {
+		child := hashTree(m.MapIndex(k).Interface())
+		n.Fields[k.String()] = child
+
+		e.bytes(hashString, []byte(k.String()))
+		e.uint(child.Hash)
+	}
+
+	h := fnv.New64a()
+	h.Write(e.buf.Bytes())
+	n.Hash = h.Sum64()
+	return n
+}
+
+// Changed returns the dotted paths of the keys whose values differ between
+// the trees n and other, such as "db.host", sorted. Branches with equal
+// hashes are skipped. A nested struct which is only in one of the trees, or
+// which is a struct in one tree and another value in the other, is reported
+// as a whole, and so are other values. It returns nil if the trees are
+// equal, and []string{""} if either of them isn't the tree of a struct.
+func (n *HashNode) Changed(other *HashNode) []string {
+	var paths []string
+	n.changed(other, "", &paths)
+	return paths
+}
+
+// changed appends the paths of the changes between n and o to paths, where
+// path is the path of n.
+func (n *HashNode) changed(o *HashNode, path string, paths *[]string) {
+	if n.Hash == o.Hash {
+		return
+	}
+
+	if n.Fields == nil || o.Fields == nil {
+		*paths = append(*paths, path)
+		return
+	}
+
+	keys := make([]string, 0, len(n.Fields))
+	for k := range n.Fields {
+		keys = append(keys, k)
+	}
+	for k := range o.Fields {
+		if _, ok := n.Fields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+
+		a, b := n.Fields[k], o.Fields[k]
+		if a == nil || b == nil {
+			*paths = append(*paths, p)
+			continue
+		}
+
+		a.changed(b, p, paths)
+	}
+}
+
+// HashTree returns the hash tree of the given struct. For more info refer
+// to Struct types HashTree() method. It panics if s's kind is not struct.
+func HashTree(s interface{}) *HashNode {
+	return New(s).HashTree()
+}
+
+// Changed returns the paths of the keys whose values differ between the
+// structs a and b, compared by their hash trees. For more info refer to
+// HashNode types Changed() method. It panics if a's or b's kind is not
+// struct.
+func Changed(a, b interface{}) []string {
+	return HashTree(a).Changed(HashTree(b))
+}
@@ -0,0 +1,78 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type merkleAuthor struct {
+	Name  string `structs:"name"`
+	Email string `structs:"email"`
+}
+
+type merkleDoc struct {
+	Title  string            `structs:"title"`
+	Author merkleAuthor      `structs:"author"`
+	Editor *merkleAuthor     `structs:"editor"`
+	Labels map[string]string `structs:"labels"`
+	Tags   []string          `structs:"tags"`
+}
+
+func newMerkleDoc() merkleDoc {
+	return merkleDoc{
+		Title:  "Intro",
+		Author: merkleAuthor{Name: "Ann", Email: "ann@example.com"},
+		Labels: map[string]string{"lang": "en"},
+		Tags:   []string{"a", "b"},
+	}
+}
+
+func TestHashTree(t *testing.T) {
+	tree := HashTree(newMerkleDoc())
+
+	if tree.Hash != HashTree(newMerkleDoc()).Hash {
+		t.Error("equal structs have different hashes")
+	}
+
+	author := tree.Fields["author"]
+	if author == nil || len(author.Fields) != 2 || author.Fields["name"] == nil {
+		t.Fatalf("got %+v", author)
+	}
+
+	if tree.Fields["title"].Fields != nil {
+		t.Error("title has fields")
+	}
+
+	// the hash of a struct only depends on its fields
+	other := newMerkleDoc()
+	other.Title = "Outro"
+	if HashTree(other).Fields["author"].Hash != author.Hash {
+		t.Error("author's hash changed")
+	}
+}
+
+func TestChanged(t *testing.T) {
+	a := newMerkleDoc()
+
+	if got := Changed(a, newMerkleDoc()); got != nil {
+		t.Errorf("got %q", got)
+	}
+
+	b := newMerkleDoc()
+	b.Author.Email = "ann@example.org"
+	b.Editor = &merkleAuthor{Name: "Bob"}
+	b.Labels["draft"] = "yes"
+	b.Tags = []string{"b", "a"}
+
+	want := []string{"author.email", "editor", "labels.draft", "tags"}
+	if got := Changed(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	// a struct in one tree and nil in the other is changed as a whole
+	c := b
+	c.Editor = &merkleAuthor{Name: "Cid"}
+	if got := Changed(b, c); !reflect.DeepEqual(got, []string{"editor.name"}) {
+		t.Errorf("got %q", got)
+	}
+}
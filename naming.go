@@ -0,0 +1,187 @@
+package structs
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyCase defines how keys are derived from field names for fields without
+// an explicit name in their tag.
+type KeyCase int
+
+const (
+	// KeyFieldName uses the field's name as is. This is the default.
+	KeyFieldName KeyCase = iota
+
+	// KeySnakeCase converts field names to snake_case, i.e. "UserName"
+	// becomes "user_name".
+	KeySnakeCase
+
+	// KeyCamelCase converts field names to lowerCamelCase, i.e. "UserName"
+	// becomes "userName".
+	KeyCamelCase
+
+	// KeyKebabCase converts field names to kebab-case, i.e. "UserName"
+	// becomes "user-name".
+	KeyKebabCase
+
+	// KeyScreamingSnakeCase converts field names to SCREAMING_SNAKE_CASE,
+	// i.e. "UserName" becomes "USER_NAME".
+	KeyScreamingSnakeCase
+)
+
+// Convert returns the key for the given field name. Runs of upper case
+// letters and initialisms are kept together as one word, so "HTTPTimeout" is
+// split into "HTTP" and "Timeout" and "UserID" into "User" and "ID".
+func (c KeyCase) Convert(name string) string {
+	switch c {
+	case KeySnakeCase:
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	case KeyKebabCase:
+		return strings.ToLower(strings.Join(splitWords(name), "-"))
+	case KeyScreamingSnakeCase:
+		return strings.ToUpper(strings.Join(splitWords(name), "_"))
+	case KeyCamelCase:
+		words := splitWords(name)
+		for i, w := range words {
+			w = strings.ToLower(w)
+			if i > 0 {
+				r, size := utf8.DecodeRuneInString(w)
+				w = string(unicode.ToUpper(r)) + w[size:]
+			}
+
+			words[i] = w
+		}
+
+		return strings.Join(words, "")
+	}
+
+	return name
+}
+
+// initialisms are the words which are kept together when field names are
+// split into words. See RegisterInitialism.
+var (
+	initialismsMu sync.RWMutex
+	initialisms   = map[string]bool{}
+)
+
+func init() {
+	RegisterInitialism(
+		"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML",
+		"HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC",
+		"SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID",
+		"UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+	)
+}
+
+// RegisterInitialism adds words to the list of initialisms used by KeyCase
+// to split field names, such as "HTTP" or "ID". Initialisms are matched case
+// sensitively and may contain lower case letters and digits, i.e. "OAuth" or
+// "IPv4", so that "OAuthToken" becomes "oauth_token" instead of
+// "o_auth_token". An initialism followed by a lower case "s", such as "IDs",
+// is kept as one word too. It's safe to call RegisterInitialism concurrently.
+func RegisterInitialism(words ...string) {
+	initialismsMu.Lock()
+	defer initialismsMu.Unlock()
+
+	for _, w := range words {
+		initialisms[w] = true
+	}
+}
+
+// initialismAt returns the length of the longest initialism, including a
+// plural "s", found at runes[i:]. It must not be directly followed by a lower
+// case letter. It returns 0 if there is no such initialism.
+func initialismAt(runes []rune, i int) int {
+	initialismsMu.RLock()
+	defer initialismsMu.RUnlock()
+
+	endsWord := func(j int) bool {
+		return j == len(runes) || !unicode.IsLower(runes[j])
+	}
+
+	for j := len(runes); j > i+1; j-- {
+		if !initialisms[string(runes[i:j])] {
+			continue
+		}
+
+		if endsWord(j) {
+			return j - i
+		}
+
+		if runes[j] == 's' && endsWord(j+1) {
+			return j + 1 - i
+		}
+	}
+
+	return 0
+}
+
+// splitWords splits a field name into its words. Words are separated by
+// underscores, hyphens, changes from lower to upper case and the
+// initialisms registered with RegisterInitialism. Digits belong to the word
+// they follow.
+func splitWords(name string) []string {
+	var words []string
+
+	runes := []rune(name)
+
+	for i := 0; i < len(runes); {
+		if runes[i] == '_' || runes[i] == '-' {
+			i++
+			continue
+		}
+
+		if n := initialismAt(runes, i); n > 0 {
+			words = append(words, string(runes[i:i+n]))
+			i += n
+			continue
+		}
+
+		j := i + 1
+		for ; j < len(runes); j++ {
+			r := runes[j]
+			if r == '_' || r == '-' {
+				break
+			}
+
+			if !unicode.IsUpper(r) {
+				continue
+			}
+
+			// "userName", the "T" of "HTTPTimeout" or the "ID" of "AWSID"
+			if !unicode.IsUpper(runes[j-1]) ||
+				(j+1 < len(runes) && unicode.IsLower(runes[j+1])) ||
+				initialismAt(runes, j) > 0 {
+				break
+			}
+		}
+
+		words = append(words, string(runes[i:j]))
+		i = j
+	}
+
+	return words
+}
+
+// key returns the key of the given field, which is the name given in the
+// field's tag or the field's name converted with the KeyCase, between the
+// prefix and suffix.
+func (l fieldLayout) key(field reflect.StructField, tagName string) (string, tagOptions) {
+	name, tagOpts := parseTag(field.Tag.Get(tagName))
+	if name == "" {
+		name = l.keyCase.Convert(field.Name)
+	}
+
+	return l.prefix + name + l.suffix, tagOpts
+}
+
+// key returns the key of the given field and its tag options. For more info
+// refer to Field types Key() method.
+func (s *Struct) key(field reflect.StructField) (string, tagOptions) {
+	return s.fieldLayout().key(field, s.TagName)
+}
@@ -0,0 +1,189 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyCase_Convert(t *testing.T) {
+	tests := []struct {
+		name      string
+		snake     string
+		camel     string
+		kebab     string
+		screaming string
+	}{
+		{"Name", "name", "name", "name", "NAME"},
+		{"UserName", "user_name", "userName", "user-name", "USER_NAME"},
+		{"UserID", "user_id", "userId", "user-id", "USER_ID"},
+		{"HTTPTimeout", "http_timeout", "httpTimeout", "http-timeout", "HTTP_TIMEOUT"},
+		{"Field2Name", "field2_name", "field2Name", "field2-name", "FIELD2_NAME"},
+		{"Already_Snake", "already_snake", "alreadySnake", "already-snake", "ALREADY_SNAKE"},
+		{"ID", "id", "id", "id", "ID"},
+		{"HTTPURL", "http_url", "httpUrl", "http-url", "HTTP_URL"},
+		{"AWSID", "aws_id", "awsId", "aws-id", "AWS_ID"},
+		{"UserIDs", "user_ids", "userIds", "user-ids", "USER_IDS"},
+		{"HTTPService", "http_service", "httpService", "http-service", "HTTP_SERVICE"},
+		{"PrixÉlan", "prix_élan", "prixÉlan", "prix-élan", "PRIX_ÉLAN"},
+	}
+
+	for _, tt := range tests {
+		got := []string{
+			KeySnakeCase.Convert(tt.name),
+			KeyCamelCase.Convert(tt.name),
+			KeyKebabCase.Convert(tt.name),
+			KeyScreamingSnakeCase.Convert(tt.name),
+		}
+
+		want := []string{tt.snake, tt.camel, tt.kebab, tt.screaming}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q want %q", tt.name, got, want)
+		}
+
+		if KeyFieldName.Convert(tt.name) != tt.name {
+			t.Errorf("%s: KeyFieldName changed the name", tt.name)
+		}
+	}
+}
+
+func TestRegisterInitialism(t *testing.T) {
+	if got := KeySnakeCase.Convert("OAuthToken"); got != "o_auth_token" {
+		t.Errorf("before registering: got %q", got)
+	}
+
+	RegisterInitialism("OAuth", "IPv4")
+	defer func() {
+		initialismsMu.Lock()
+		delete(initialisms, "OAuth")
+		delete(initialisms, "IPv4")
+		initialismsMu.Unlock()
+	}()
+
+	tests := map[string]string{
+		"OAuthToken":  "oauth_token",
+		"IPv4Address": "ipv4_address",
+		"ServerIPv4":  "server_ipv4",
+	}
+
+	for name, want := range tests {
+		if got := KeySnakeCase.Convert(name); got != want {
+			t.Errorf("%s: got %q want %q", name, got, want)
+		}
+	}
+}
+
+type namingAddress struct {
+	StreetName string
+}
+
+type naming struct {
+	FirstName string
+	LastName  string `structs:"surname"`
+	HomeAddr  namingAddress
+}
+
+func TestMap_KeyCase(t *testing.T) {
+	n := naming{FirstName: "Rob", LastName: "Pike", HomeAddr: namingAddress{"Main"}}
+
+	s := New(&n)
+	s.KeyCase = KeySnakeCase
+
+	want := map[string]interface{}{
+		"first_name": "Rob",
+		"surname":    "Pike",
+		"home_addr": map[string]interface{}{
+			"street_name": "Main",
+		},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v want %#v", m, want)
+	}
+
+	var keys []string
+	for _, f := range s.Fields() {
+		keys = append(keys, f.Key())
+	}
+
+	if want := []string{"first_name", "surname", "home_addr"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Field.Key: got %q want %q", keys, want)
+	}
+
+	if key := s.Field("HomeAddr").Field("StreetName").Key(); key != "street_name" {
+		t.Errorf("nested Field.Key: got %q", key)
+	}
+}
+
+func TestFill_KeyCase(t *testing.T) {
+	var n naming
+
+	s := New(&n)
+	s.KeyCase = KeyCamelCase
+
+	err := s.Fill(map[string]interface{}{
+		"firstName": "Rob",
+		"surname":   "Pike",
+		"homeAddr":  map[string]interface{}{"streetName": "Main"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := naming{FirstName: "Rob", LastName: "Pike", HomeAddr: namingAddress{"Main"}}
+	if n != want {
+		t.Errorf("got %#v want %#v", n, want)
+	}
+}
+
+type prefixed struct {
+	Name    string
+	Address namingAddress `structs:",flatten"`
+	Home    namingAddress `structs:"home"`
+	Labels  map[string]string
+}
+
+func TestMap_KeyPrefix(t *testing.T) {
+	p := prefixed{
+		Name:    "api",
+		Address: namingAddress{"Main"},
+		Home:    namingAddress{"Side"},
+		Labels:  map[string]string{"env": "prod"},
+	}
+
+	s := New(&p)
+	s.KeyCase = KeySnakeCase
+	s.KeyPrefix = "app_"
+	s.KeySuffix = "_total"
+
+	want := map[string]interface{}{
+		"app_name_total":        "api",
+		"app_street_name_total": "Main",
+		"app_home_total": map[string]interface{}{
+			"app_street_name_total": "Side",
+		},
+		"app_labels_total": map[string]string{"env": "prod"},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v want %#v", m, want)
+	}
+
+	if key := s.Field("Home").Key(); key != "app_home_total" {
+		t.Errorf("Field.Key: got %q", key)
+	}
+
+	var out prefixed
+	d := New(&out)
+	d.KeyCase = KeySnakeCase
+	d.KeyPrefix = "app_"
+	d.KeySuffix = "_total"
+
+	if err := d.Fill(s.Map()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, p) {
+		t.Errorf("Fill: got %#v want %#v", out, p)
+	}
+}
@@ -0,0 +1,285 @@
+package structs
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// RequiredTagName is the tag name which marks a field as required in
+	// the schema generated by OpenAPI, such as `required:"true"`.
+	RequiredTagName = "required"
+
+	// DescTagName is the tag name which gives the description of a field in
+	// the schema generated by OpenAPI, such as `desc:"The user's email"`.
+	DescTagName = "desc"
+)
+
+// Schema is an OpenAPI 3 or JSON Schema schema object. Only the keywords
+// used by OpenAPI and JSONSchema are defined, it's meant to be marshaled with
+// encoding/json into a spec.
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// OpenAPI returns the OpenAPI 3 schema of s's type, along with the schemas
+// of the named structs it refers to, keyed by their names, which belong into
+// the components/schemas section of the spec:
+//
+//   type CreateUser struct {
+//       Email   string  `json:"email" required:"true" desc:"Login email"`
+//       Address Address `json:"address"`
+//   }
+//
+//   s := structs.New(&CreateUser{})
+//   s.TagName = "json"
+//   schema, components := s.OpenAPI()
+//
+// Properties are named by the keys of Map, so the tag name and KeyCase of s
+// apply and fields tagged with "-" are left out. Fields tagged with
+// "flatten" add their properties to the parent and fields with the
+// "string" option are strings. Fields tagged with `required:"true"` are
+// listed as required and the desc tag gives a property's description. The
+// rules of the validate tag are added as described in JSONSchema.
+//
+// Named nested structs are referenced with "#/components/schemas/Name",
+// which allows recursive types, other structs are inlined. Types
+// implementing encoding.TextMarshaler are strings, with the "date-time"
+// format for time.Time. Pointers, wrappers registered with RegisterWrapper
+// and the database/sql Null types are nullable. Interface fields have an
+// empty schema, which allows any value. Only the types are used, so the
+// schema is the same for any value of s.
+func (s *Struct) OpenAPI() (*Schema, map[string]*Schema) {
+	g := &schemaGen{
+		s:         s,
+		refPrefix: "#/components/schemas/",
+		defs:      make(map[string]*Schema),
+	}
+
+	return g.object(s), g.defs
+}
+
+// schemaGen generates the schemas of the types of a struct, either for
+// OpenAPI or for JSON Schema.
+type schemaGen struct {
+	s *Struct
+
+	// jsonSchema selects JSON Schema instead of OpenAPI.
+	jsonSchema bool
+
+	// refPrefix is the prefix of the references to defs.
+	refPrefix string
+
+	// defs are the schemas of the named structs, keyed by their names.
+	defs map[string]*Schema
+}
+
+// object returns the object schema of the struct s.
+func (g *schemaGen) object(s *Struct) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, field := range s.structFields() {
+		name, tagOpts := s.key(field)
+
+		if tagOpts.Has("flatten") {
+			if t := indirectType(field.Type); t.Kind() == reflect.Struct {
+				flat := g.object(s.nestedStruct(reflect.New(t).Interface()))
+				for k, p := range flat.Properties {
+					schema.Properties[k] = p
+				}
+
+				schema.Required = append(schema.Required, flat.Required...)
+				continue
+			}
+		}
+
+		// the constraints apply to the value, not to null
+		t, nullable := field.Type, false
+		for inner, ok := nullableType(t); ok; inner, ok = nullableType(t) {
+			t, nullable = inner, true
+		}
+
+		var p *Schema
+		if tagOpts.Has("string") {
+			p = &Schema{Type: "string"}
+		} else {
+			p = g.schema(t)
+		}
+
+		required, _ := strconv.ParseBool(field.Tag.Get(RequiredTagName))
+		if schemaRules(p, t, field.Tag.Get(ValidateTagName)) {
+			required = true
+		}
+
+		if nullable {
+			p = g.nullable(p)
+		}
+
+		if desc := field.Tag.Get(DescTagName); desc != "" {
+			// $ref siblings are ignored by OpenAPI, so the reference is wrapped
+			if p.Ref != "" && !g.jsonSchema {
+				p = &Schema{AllOf: []*Schema{p}}
+			}
+
+			p.Description = desc
+		}
+
+		schema.Properties[name] = p
+
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// schema returns the schema of the type t.
+func (g *schemaGen) schema(t reflect.Type) *Schema {
+	if inner, ok := nullableType(t); ok {
+		return g.nullable(g.schema(inner))
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: g.format("int32")}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: g.format("int64")}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: g.format("float")}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: g.format("double")}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if g.jsonSchema {
+				return &Schema{Type: "string", ContentEncoding: "base64"}
+			}
+
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.ref(t)
+	}
+
+	// interfaces and kinds without a JSON representation allow any value
+	return &Schema{}
+}
+
+// format returns the OpenAPI number format f, which JSON Schema doesn't
+// define.
+func (g *schemaGen) format(f string) string {
+	if g.jsonSchema {
+		return ""
+	}
+
+	return f
+}
+
+// nullable returns the schema p which allows null too. OpenAPI doesn't
+// allow nullable references, so they're returned as is.
+func (g *schemaGen) nullable(p *Schema) *Schema {
+	switch {
+	case g.jsonSchema:
+		return &Schema{AnyOf: []*Schema{p, {Type: "null"}}}
+	case p.Ref == "":
+		p.Nullable = true
+	}
+
+	return p
+}
+
+// invalidComponentChars matches the characters which are not allowed in the
+// names of components.
+var invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// ref returns a reference to the schema of the struct type t, which is
+// added to the defs. Unnamed structs are inlined.
+func (g *schemaGen) ref(t reflect.Type) *Schema {
+	nested := g.s.nestedStruct(reflect.New(t).Interface())
+	if t.Name() == "" {
+		return g.object(nested)
+	}
+
+	name := invalidComponentChars.ReplaceAllString(t.Name(), "_")
+	name = strings.Trim(name, "_")
+
+	if _, ok := g.defs[name]; !ok {
+		// add the name first, so recursive types refer to it
+		g.defs[name] = nil
+		g.defs[name] = g.object(nested)
+	}
+
+	return &Schema{Ref: g.refPrefix + name}
+}
+
+// nullableType returns the type t points to or wraps, if t is a pointer, a
+// wrapper registered with RegisterWrapper or one of the database/sql Null
+// types.
+func nullableType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem(), true
+	}
+
+	if w, ok := registered(t); ok {
+		return w.inner, true
+	}
+
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" ||
+		!strings.HasPrefix(t.Name(), "Null") || t.NumField() != 2 {
+		return nil, false
+	}
+
+	if valid, ok := t.FieldByName("Valid"); !ok || valid.Type.Kind() != reflect.Bool {
+		return nil, false
+	}
+
+	return t.Field(0).Type, true
+}
+
+// OpenAPI returns the OpenAPI 3 schema of the given struct and the schemas
+// of the named structs it refers to. For more info refer to Struct types
+// OpenAPI() method. It panics if s's kind is not struct.
+func OpenAPI(s interface{}) (*Schema, map[string]*Schema) {
+	return New(s).OpenAPI()
+}
@@ -0,0 +1,115 @@
+package structs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type apiAddress struct {
+	City string `json:"city" required:"true"`
+}
+
+type apiNode struct {
+	Name     string     `json:"name"`
+	Children []*apiNode `json:"children"`
+}
+
+type apiMeta struct {
+	Version int `json:"version" required:"true"`
+}
+
+type apiUser struct {
+	ID       int64             `json:"id" required:"true"`
+	Email    string            `json:"email" required:"true" desc:"Login email"`
+	Age      *int32            `json:"age"`
+	Score    float64           `json:"score,string"`
+	Created  time.Time         `json:"created"`
+	Avatar   []byte            `json:"avatar"`
+	Labels   map[string]string `json:"labels"`
+	Nick     sql.NullString    `json:"nick"`
+	Address  apiAddress        `json:"address" desc:"Home address"`
+	Work     *apiAddress       `json:"work"`
+	Tree     apiNode           `json:"tree"`
+	Meta     apiMeta           `json:"meta,flatten"`
+	Inline   struct{ On bool } `json:"inline"`
+	Any      interface{}       `json:"any"`
+	Internal string            `json:"-"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	s := New(&apiUser{})
+	s.TagName = "json"
+
+	schema, components := s.OpenAPI()
+
+	ref := func(name string) *Schema {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	want := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":      {Type: "integer", Format: "int64"},
+			"email":   {Type: "string", Description: "Login email"},
+			"age":     {Type: "integer", Format: "int32", Nullable: true},
+			"score":   {Type: "string"},
+			"created": {Type: "string", Format: "date-time"},
+			"avatar":  {Type: "string", Format: "byte"},
+			"labels":  {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"nick":    {Type: "string", Nullable: true},
+			"address": {AllOf: []*Schema{ref("apiAddress")}, Description: "Home address"},
+			"work":    ref("apiAddress"),
+			"tree":    ref("apiNode"),
+			"version": {Type: "integer", Format: "int64"},
+			"inline": {
+				Type:       "object",
+				Properties: map[string]*Schema{"On": {Type: "boolean"}},
+			},
+			"any": {},
+		},
+		Required: []string{"id", "email", "version"},
+	}
+
+	if !reflect.DeepEqual(schema, want) {
+		got, _ := json.MarshalIndent(schema, "", "  ")
+		t.Errorf("got %s", got)
+	}
+
+	wantComponents := map[string]*Schema{
+		"apiAddress": {
+			Type:       "object",
+			Properties: map[string]*Schema{"city": {Type: "string"}},
+			Required:   []string{"city"},
+		},
+		"apiNode": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name":     {Type: "string"},
+				"children": {Type: "array", Items: ref("apiNode")},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(components, wantComponents) {
+		got, _ := json.MarshalIndent(components, "", "  ")
+		t.Errorf("components: got %s", got)
+	}
+}
+
+func TestOpenAPI_JSON(t *testing.T) {
+	schema, _ := OpenAPI(apiNode{})
+
+	got, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default tag name is used, so the keys are the field names
+	want := `{"type":"object","properties":{"Children":{"type":"array","items":{"$ref":"#/components/schemas/apiNode"}},"Name":{"type":"string"}}}`
+	if string(got) != want {
+		t.Errorf("got %s", got)
+	}
+}
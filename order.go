@@ -0,0 +1,93 @@
+package structs
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	// OrderTagName is the tag name which gives the position of a field for
+	// the OrderTag order, such as `order:"1"`.
+	OrderTagName = "order"
+)
+
+// FieldOrder defines the order of the fields returned by Fields, Names and
+// Values and of the fields converted by Map.
+type FieldOrder int
+
+const (
+	// OrderDeclared keeps the fields in the order they're declared in the
+	// struct. This is the default.
+	OrderDeclared FieldOrder = iota
+
+	// OrderName sorts the fields alphabetically by their name.
+	OrderName
+
+	// OrderTag sorts the fields by the number given in their order tag.
+	// Fields without a number follow in the order they're declared.
+	OrderTag
+)
+
+// fieldLayout holds the options of a Struct which define the keys and the
+// order of its fields, so that the fields returned by Fields() are laid out
+// the same way.
+type fieldLayout struct {
+	keyCase KeyCase
+	prefix  string
+	suffix  string
+	order   FieldOrder
+}
+
+// fieldLayout returns the options of s which define the keys and the order
+// of its fields.
+func (s *Struct) fieldLayout() fieldLayout {
+	return fieldLayout{
+		keyCase: s.KeyCase,
+		prefix:  s.KeyPrefix,
+		suffix:  s.KeySuffix,
+		order:   s.Order,
+	}
+}
+
+// less reports whether the field a must be placed before the field b.
+func (l fieldLayout) less(a, b reflect.StructField) bool {
+	switch l.order {
+	case OrderName:
+		return a.Name < b.Name
+	case OrderTag:
+		an, aok := orderNumber(a)
+		bn, bok := orderNumber(b)
+		if aok && bok {
+			return an < bn
+		}
+
+		return aok && !bok
+	}
+
+	return false
+}
+
+// sortFields sorts the given fields according to l. Fields which are equal
+// keep their order.
+func (l fieldLayout) sortFields(fields []reflect.StructField) {
+	if l.order == OrderDeclared {
+		return
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return l.less(fields[i], fields[j])
+	})
+}
+
+// orderNumber returns the number given in the order tag of the field. The
+// boolean is false if the field has no valid number.
+func orderNumber(field reflect.StructField) (int, bool) {
+	tag, ok := field.Tag.Lookup(OrderTagName)
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(tag)
+	return n, err == nil
+}
@@ -0,0 +1,76 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ordered struct {
+	Zeta  string `order:"2"`
+	Alpha int
+	Mid   bool `order:"1"`
+	Beta  string
+	Skip  string `structs:"-"`
+}
+
+func TestOrder(t *testing.T) {
+	o := ordered{Zeta: "z", Alpha: 1, Mid: true, Beta: "b"}
+
+	tests := []struct {
+		order  FieldOrder
+		names  []string
+		values []interface{}
+	}{
+		{OrderDeclared, []string{"Zeta", "Alpha", "Mid", "Beta"}, []interface{}{"z", 1, true, "b"}},
+		{OrderName, []string{"Alpha", "Beta", "Mid", "Zeta"}, []interface{}{1, "b", true, "z"}},
+		{OrderTag, []string{"Mid", "Zeta", "Alpha", "Beta"}, []interface{}{true, "z", 1, "b"}},
+	}
+
+	for _, tt := range tests {
+		s := New(o)
+		s.Order = tt.order
+
+		if names := s.Names(); !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("%d: Names: got %q want %q", tt.order, names, tt.names)
+		}
+
+		var fields []string
+		for _, f := range s.Fields() {
+			fields = append(fields, f.Name())
+		}
+
+		if !reflect.DeepEqual(fields, tt.names) {
+			t.Errorf("%d: Fields: got %q want %q", tt.order, fields, tt.names)
+		}
+
+		if values := s.Values(); !reflect.DeepEqual(values, tt.values) {
+			t.Errorf("%d: Values: got %v want %v", tt.order, values, tt.values)
+		}
+	}
+}
+
+func TestOrder_Nested(t *testing.T) {
+	type inner struct {
+		B, A int
+	}
+
+	type outer struct {
+		In inner
+	}
+
+	s := New(&outer{In: inner{B: 2, A: 1}})
+	s.Order = OrderName
+
+	var names []string
+	for _, f := range s.Field("In").Fields() {
+		names = append(names, f.Name())
+	}
+
+	if want := []string{"A", "B"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %q want %q", names, want)
+	}
+
+	if values := s.Values(); !reflect.DeepEqual(values, []interface{}{1, 2}) {
+		t.Errorf("got %v", values)
+	}
+}
@@ -0,0 +1,123 @@
+package structs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// PartitionTagName is the tag name of the fields which make up the
+	// partition key of a struct, with their position in the key, such as
+	// `partition:"1"`.
+	PartitionTagName = "partition"
+)
+
+// PartitionKey returns the partition key of s, such as for the key of a
+// Kafka message, so all messages about the same entity go to the same
+// partition. It consists of the values of the fields tagged with partition,
+// in the order of the positions given in their tags:
+//
+//   type Order struct {
+//       Tenant  string `partition:"1"`
+//       Account int    `partition:"2"`
+//       ID      string
+//   }
+//
+// The values are formatted as in MapString and joined with "|". Backslashes
+// and "|" in the values are escaped with a backslash, so different values
+// never have the same key. Nil pointers are empty. It returns
+// ErrNoPartitionKey if no field is tagged, and an error if a position is
+// not a number or a value can't be formatted.
+func (s *Struct) PartitionKey() (string, error) {
+	fields, err := s.partitionFields()
+	if err != nil {
+		return "", err
+	}
+
+	escape := strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		str, err := formatField(field, s.value.FieldByIndex(field.Index))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", field.Name, err)
+		}
+
+		parts[i] = escape.Replace(str)
+	}
+
+	return strings.Join(parts, "|"), nil
+}
+
+// PartitionHash returns the 64-bit FNV-1a hash of the partition key of s,
+// for consistent-hash routing, such as PartitionHash() % n for n shards.
+// Since it's the hash of the key returned by PartitionKey, other services
+// can compute it too. For more info refer to Struct types PartitionKey()
+// method.
+func (s *Struct) PartitionHash() (uint64, error) {
+	key, err := s.PartitionKey()
+	if err != nil {
+		return 0, err
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64(), nil
+}
+
+// partitionFields returns the fields tagged with partition, sorted by their
+// positions.
+func (s *Struct) partitionFields() ([]reflect.StructField, error) {
+	type partition struct {
+		field reflect.StructField
+		pos   int
+	}
+
+	var parts []partition
+	for _, field := range s.structFields() {
+		tag, ok := field.Tag.Lookup(PartitionTagName)
+		if !ok {
+			continue
+		}
+
+		pos, err := strconv.Atoi(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid partition position %q", field.Name, tag)
+		}
+
+		parts = append(parts, partition{field, pos})
+	}
+
+	if len(parts) == 0 {
+		return nil, ErrNoPartitionKey
+	}
+
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].pos < parts[j].pos
+	})
+
+	fields := make([]reflect.StructField, len(parts))
+	for i, p := range parts {
+		fields[i] = p.field
+	}
+
+	return fields, nil
+}
+
+// PartitionKey returns the partition key of the given struct. For more info
+// refer to Struct types PartitionKey() method. It panics if s's kind is not
+// struct.
+func PartitionKey(s interface{}) (string, error) {
+	return New(s).PartitionKey()
+}
+
+// PartitionHash returns the hash of the partition key of the given struct.
+// For more info refer to Struct types PartitionHash() method. It panics if
+// s's kind is not struct.
+func PartitionHash(s interface{}) (uint64, error) {
+	return New(s).PartitionHash()
+}
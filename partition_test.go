@@ -0,0 +1,73 @@
+package structs
+
+import (
+	"errors"
+	"hash/fnv"
+	"testing"
+	"time"
+)
+
+type partitionOrder struct {
+	ID      string
+	Account *int      `partition:"2"`
+	Tenant  string    `partition:"1"`
+	Day     time.Time `partition:"3" format:"2006-01-02"`
+}
+
+func TestPartitionKey(t *testing.T) {
+	account := 42
+	o := partitionOrder{
+		ID:      "o1",
+		Account: &account,
+		Tenant:  "acme",
+		Day:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	key, err := PartitionKey(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "acme|42|2024-05-01"; key != want {
+		t.Errorf("got %q want %q", key, want)
+	}
+
+	o.Tenant, o.Account = `a|b\`, nil
+	key, _ = PartitionKey(o)
+	if want := `a\|b\\||2024-05-01`; key != want {
+		t.Errorf("got %q want %q", key, want)
+	}
+}
+
+func TestPartitionKey_Errors(t *testing.T) {
+	if _, err := PartitionKey(struct{ ID string }{}); !errors.Is(err, ErrNoPartitionKey) {
+		t.Errorf("got %v", err)
+	}
+
+	invalid := struct {
+		ID string `partition:"first"`
+	}{}
+	if _, err := PartitionKey(invalid); err == nil {
+		t.Error("got no error for an invalid position")
+	}
+}
+
+func TestPartitionHash(t *testing.T) {
+	o := partitionOrder{Tenant: "acme"}
+
+	got, err := PartitionHash(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte("acme||0001-01-01"))
+	if want := h.Sum64(); got != want {
+		t.Errorf("got %x want %x", got, want)
+	}
+
+	o.ID = "other"
+	if again, _ := PartitionHash(o); again != got {
+		t.Error("untagged field changed the hash")
+	}
+}
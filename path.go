@@ -0,0 +1,347 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PathError is returned by GetPath and SetPath if a path expression can't be
+// resolved.
+type PathError struct {
+	// Path is the whole path expression.
+	Path string
+
+	// At is the part of Path up to the segment which can't be resolved,
+	// such as "Orders[2]" for an order which doesn't exist.
+	At string
+
+	// Err is the underlying error, such as ErrFieldNotFound,
+	// ErrIndexOutOfRange or ErrKeyNotFound.
+	Err error
+}
+
+// Error returns the message of the error including the path.
+func (e *PathError) Error() string {
+	if e.At == e.Path {
+		return fmt.Sprintf("%s: %s", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("%s: at %s: %s", e.Path, e.At, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// segment is a part of a path expression: a field name or an index.
+type segment struct {
+	// name is the field name, or the index or key in brackets.
+	name  string
+	index bool
+
+	// end is the end of the segment in the path expression.
+	end int
+}
+
+// parsePath splits the path expression path into its segments.
+func parsePath(path string) ([]segment, error) {
+	var segs []segment
+
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '[':
+			var name string
+			if rest := path[i+1:]; strings.HasPrefix(rest, `"`) {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil || !strings.HasPrefix(rest[len(quoted):], "]") {
+					return nil, &PathError{Path: path, At: path[:i], Err: ErrInvalidPath}
+				}
+
+				name, _ = strconv.Unquote(quoted)
+				i += len(quoted) + 2
+			} else {
+				n := strings.IndexByte(rest, ']')
+				if n <= 0 {
+					return nil, &PathError{Path: path, At: path[:i], Err: ErrInvalidPath}
+				}
+
+				name = rest[:n]
+				i += n + 2
+			}
+
+			segs = append(segs, segment{name: name, index: true, end: i})
+		case len(segs) > 0 && path[i] != '.':
+			return nil, &PathError{Path: path, At: path[:i], Err: ErrInvalidPath}
+		default:
+			if len(segs) > 0 {
+				i++
+			}
+
+			n := strings.IndexAny(path[i:], ".[")
+			if n < 0 {
+				n = len(path) - i
+			}
+
+			if n == 0 {
+				return nil, &PathError{Path: path, At: path[:i], Err: ErrInvalidPath}
+			}
+
+			segs = append(segs, segment{name: path[i : i+n], end: i + n})
+			i += n
+		}
+	}
+
+	if len(segs) == 0 {
+		return nil, &PathError{Path: path, Err: ErrInvalidPath}
+	}
+
+	return segs, nil
+}
+
+// GetPath returns the value at the given path expression, which consists of
+// field names separated by dots and of indexes of slices, arrays and maps in
+// brackets, such as:
+//
+//   Orders[2].Items["sku"].Price
+//
+// String keys are quoted as in Go, other keys are parsed like environment
+// variables in LoadEnv, i.e. Scores[42] for a map[int]int. Promoted fields
+// of embedded structs are found by their name. If a segment can't be
+// resolved, it returns a *PathError wrapping one of:
+//
+//   - ErrFieldNotFound if a struct has no such field, or the field is
+//     tagged with "-"
+//   - ErrNotExported if the field is not exported
+//   - ErrIndexOutOfRange if a slice or array has no such index
+//   - ErrKeyNotFound if a map has no such key
+//   - ErrNilPointer if a pointer or interface along the path is nil
+//   - ErrTypeMismatch if a value can't be indexed, or an index is invalid
+//   - ErrInvalidPath if the expression is malformed
+func (s *Struct) GetPath(path string) (interface{}, error) {
+	var val interface{}
+
+	err := s.resolvePath(path, false, func(v reflect.Value) error {
+		val = v.Interface()
+		return nil
+	})
+
+	return val, err
+}
+
+// SetPath sets the value at the given path expression to val, which is
+// converted as described in Fill. The path expression is the same as for
+// GetPath, except that nil pointers and maps along the path are allocated,
+// and missing map keys are added. Structs stored in maps are copied, changed
+// and stored back. Segments which can't be resolved are reported with a
+// *PathError as in GetPath. It returns an error if s was not created from a
+// pointer.
+func (s *Struct) SetPath(path string, val interface{}) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	return s.resolvePath(path, true, func(v reflect.Value) error {
+		if err := s.assign(v, val); err != nil {
+			return &PathError{Path: path, At: path, Err: err}
+		}
+
+		return nil
+	})
+}
+
+// resolvePath calls fn with the value at path. If set is true, the value is
+// addressable and the values along the path are allocated on demand.
+func (s *Struct) resolvePath(path string, set bool, fn func(reflect.Value) error) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	r := pathResolver{s: s, path: path, segs: segs, set: set, fn: fn}
+	return r.resolve(s.value, 0, 0)
+}
+
+// pathResolver resolves the segments of a path expression.
+type pathResolver struct {
+	s    *Struct
+	path string
+	segs []segment
+	set  bool
+	fn   func(reflect.Value) error
+}
+
+// fail returns a *PathError for the segment at i.
+func (r *pathResolver) fail(i int, err error) error {
+	return &PathError{Path: r.path, At: r.path[:r.segs[i].end], Err: err}
+}
+
+// resolve resolves the segments of the path starting at i in v. Segments
+// before i end at prev in the path expression.
+func (r *pathResolver) resolve(v reflect.Value, i, prev int) error {
+	// failPrev returns a *PathError for the value v itself
+	failPrev := func(err error) error {
+		return &PathError{Path: r.path, At: r.path[:prev], Err: err}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if i == len(r.segs) {
+			return r.fn(v)
+		}
+
+		if v.IsNil() {
+			if !r.set {
+				return failPrev(ErrNilPointer)
+			}
+
+			if !v.CanSet() {
+				return failPrev(ErrNotSettable)
+			}
+
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return r.resolve(v.Elem(), i, prev)
+	case reflect.Interface:
+		if i == len(r.segs) {
+			return r.fn(v)
+		}
+
+		if v.IsNil() {
+			return failPrev(ErrNilPointer)
+		}
+
+		if !r.set {
+			return r.resolve(v.Elem(), i, prev)
+		}
+
+		if !v.CanSet() {
+			return failPrev(ErrNotSettable)
+		}
+
+		c := reflect.New(v.Elem().Type()).Elem()
+		c.Set(v.Elem())
+
+		if err := r.resolve(c, i, prev); err != nil {
+			return err
+		}
+
+		v.Set(c)
+		return nil
+	}
+
+	if i == len(r.segs) {
+		return r.fn(v)
+	}
+
+	seg := r.segs[i]
+	if !seg.index {
+		if v.Kind() != reflect.Struct {
+			return r.fail(i, ErrFieldNotFound)
+		}
+
+		field, ok := v.Type().FieldByName(seg.name)
+		if !ok || field.Tag.Get(r.s.TagName) == "-" {
+			return r.fail(i, ErrFieldNotFound)
+		}
+
+		if field.PkgPath != "" {
+			return r.fail(i, ErrNotExported)
+		}
+
+		// promoted fields of embedded pointers
+		for j, x := range field.Index {
+			if j > 0 && v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					if !r.set {
+						return r.fail(i, ErrNilPointer)
+					}
+
+					if !v.CanSet() {
+						return r.fail(i, ErrNotSettable)
+					}
+
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+
+				v = v.Elem()
+			}
+
+			v = v.Field(x)
+		}
+
+		return r.resolve(v, i+1, seg.end)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		n, err := strconv.Atoi(seg.name)
+		if err != nil {
+			return r.fail(i, fmt.Errorf("%w: invalid index %q", ErrTypeMismatch, seg.name))
+		}
+
+		if n < 0 || n >= v.Len() {
+			return r.fail(i, fmt.Errorf("%w: %d with length %d", ErrIndexOutOfRange, n, v.Len()))
+		}
+
+		return r.resolve(v.Index(n), i+1, seg.end)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		if err := parseEnv(key, seg.name); err != nil {
+			return r.fail(i, fmt.Errorf("%w: invalid key %q: %v", ErrTypeMismatch, seg.name, err))
+		}
+
+		elem := v.MapIndex(key)
+		if !elem.IsValid() && !r.set {
+			return r.fail(i, ErrKeyNotFound)
+		}
+
+		if !r.set {
+			return r.resolve(elem, i+1, seg.end)
+		}
+
+		if v.IsNil() {
+			if !v.CanSet() {
+				return failPrev(ErrNotSettable)
+			}
+
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		c := reflect.New(v.Type().Elem()).Elem()
+		if elem.IsValid() {
+			c.Set(elem)
+		}
+
+		if err := r.resolve(c, i+1, seg.end); err != nil {
+			return err
+		}
+
+		v.SetMapIndex(key, c)
+		return nil
+	}
+
+	return r.fail(i, fmt.Errorf("%w: can't index %s", ErrTypeMismatch, v.Type()))
+}
+
+// GetPath returns the value at the given path expression in the given
+// struct. For more info refer to Struct types GetPath() method. It panics if
+// s's kind is not struct.
+func GetPath(s interface{}, path string) (interface{}, error) {
+	return New(s).GetPath(path)
+}
+
+// SetPath sets the value at the given path expression in the struct pointed
+// to by dst. For more info refer to Struct types SetPath() method. It
+// returns an error if dst is not a pointer to struct.
+func SetPath(dst interface{}, path string, val interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.SetPath(path, val)
+}
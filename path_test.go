@@ -0,0 +1,155 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type pathExprItem struct {
+	Price float64
+}
+
+type pathExprOrder struct {
+	Items map[string]pathExprItem
+	Notes []string
+}
+
+type PathExprBase struct {
+	Version int
+}
+
+type pathExprTarget struct {
+	*PathExprBase
+	Orders  []*pathExprOrder
+	Scores  map[int]int
+	Any     interface{}
+	Pair    [2]string
+	Ignored string `structs:"-"`
+	secret  string
+}
+
+func TestGetPath(t *testing.T) {
+	s := pathExprTarget{
+		Orders: []*pathExprOrder{nil, {Items: map[string]pathExprItem{"s.k\"u": {2.5}}, Notes: []string{"a"}}},
+		Scores: map[int]int{42: 1},
+		Any:    pathExprItem{1.5},
+		Pair:   [2]string{"x", "y"},
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{`Orders[1].Items["s.k\"u"].Price`, 2.5},
+		{`Orders[1].Items["s.k\"u"]`, pathExprItem{2.5}},
+		{"Orders[1].Notes[0]", "a"},
+		{"Orders[0]", (*pathExprOrder)(nil)},
+		{"Scores[42]", 1},
+		{"Any.Price", 1.5},
+		{"Pair[1]", "y"},
+	}
+
+	for _, test := range tests {
+		got, err := GetPath(s, test.path)
+		if err != nil {
+			t.Errorf("%s: %v", test.path, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %#v want %#v", test.path, got, test.want)
+		}
+	}
+}
+
+func TestGetPath_Errors(t *testing.T) {
+	s := pathExprTarget{Orders: []*pathExprOrder{nil, {}}}
+
+	tests := []struct {
+		path string
+		err  error
+		at   string
+	}{
+		{"Missing", ErrFieldNotFound, "Missing"},
+		{"Ignored", ErrFieldNotFound, "Ignored"},
+		{"secret", ErrNotExported, "secret"},
+		{"Version", ErrNilPointer, "Version"},
+		{"Orders[2].Notes", ErrIndexOutOfRange, "Orders[2]"},
+		{"Orders[-1]", ErrIndexOutOfRange, "Orders[-1]"},
+		{"Orders[x]", ErrTypeMismatch, "Orders[x]"},
+		{"Orders[0].Notes", ErrNilPointer, "Orders[0]"},
+		{`Orders[1].Items["a"]`, ErrKeyNotFound, `Orders[1].Items["a"]`},
+		{"Scores[x]", ErrTypeMismatch, "Scores[x]"},
+		{"Any.Price", ErrNilPointer, "Any"},
+		{"Pair[0].Len", ErrFieldNotFound, "Pair[0].Len"},
+		{"Pair.Len", ErrFieldNotFound, "Pair.Len"},
+		{"Scores.X", ErrFieldNotFound, "Scores.X"},
+		{"Orders[1][0]", ErrTypeMismatch, "Orders[1][0]"},
+		{"", ErrInvalidPath, ""},
+		{"Orders.", ErrInvalidPath, "Orders."},
+		{"Orders[1", ErrInvalidPath, "Orders"},
+		{`Orders["1]`, ErrInvalidPath, "Orders"},
+		{"Orders[]", ErrInvalidPath, "Orders"},
+		{"Orders[1]Notes", ErrInvalidPath, "Orders[1]"},
+	}
+
+	for _, test := range tests {
+		_, err := GetPath(s, test.path)
+
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) || !errors.Is(err, test.err) || pathErr.At != test.at {
+			t.Errorf("%s: got %v, want %v at %q", test.path, err, test.err, test.at)
+		}
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	var s pathExprTarget
+
+	sets := []struct {
+		path string
+		val  interface{}
+	}{
+		{"Version", 2.0},
+		{"Orders", []*pathExprOrder{nil}},
+		{`Orders[0].Items["sku"].Price`, 1.5},
+		{"Scores[7]", 3},
+		{"Pair[1]", "b"},
+		{"Any", pathExprItem{}},
+		{"Any.Price", 4},
+	}
+
+	for _, set := range sets {
+		if err := SetPath(&s, set.path, set.val); err != nil {
+			t.Fatalf("%s: %v", set.path, err)
+		}
+	}
+
+	want := pathExprTarget{
+		PathExprBase: &PathExprBase{Version: 2},
+		Orders:       []*pathExprOrder{{Items: map[string]pathExprItem{"sku": {1.5}}}},
+		Scores:       map[int]int{7: 3},
+		Pair:         [2]string{"", "b"},
+		Any:          pathExprItem{4},
+	}
+
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v\nwant %+v", s, want)
+	}
+
+	err := SetPath(&s, "Orders[1].Notes", nil)
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("got %v", err)
+	}
+
+	var pathErr *PathError
+	err = SetPath(&s, "Scores[1]", "x")
+	if !errors.As(err, &pathErr) || !errors.Is(err, ErrTypeMismatch) || pathErr.At != "Scores[1]" {
+		t.Errorf("got %v", err)
+	}
+
+	if err := SetPath(s, "Version", 1); err == nil {
+		t.Error("expected an error for a struct which is not a pointer")
+	}
+}
@@ -0,0 +1,139 @@
+package structs
+
+import (
+	"math/rand"
+	"reflect"
+	"testing/quick"
+	"time"
+)
+
+// maxQuickDepth is the number of levels of nested structs QuickValue
+// generates through pointers, slices and maps, so recursive types end.
+const maxQuickDepth = 3
+
+var generatorType = reflect.TypeOf((*quick.Generator)(nil)).Elem()
+
+// Quick wraps a value of type T so it's generated by testing/quick with
+// QuickValue, which allows property-based tests over types that
+// testing/quick can't generate itself, such as structs with a time.Time,
+// unexported or interface fields, without writing a Generate method:
+//
+//   f := func(q structs.Quick[User]) bool {
+//       u := q.Value
+//       return roundTrip(u) == u
+//   }
+//
+//   if err := quick.Check(f, nil); err != nil {
+//       t.Error(err)
+//   }
+type Quick[T any] struct {
+	Value T
+}
+
+// Generate implements quick.Generator.
+func (Quick[T]) Generate(r *rand.Rand, size int) reflect.Value {
+	var q Quick[T]
+	quickValue(reflect.ValueOf(&q.Value).Elem(), r, size, 0)
+	return reflect.ValueOf(q)
+}
+
+// QuickValue returns a random value of type t, like quick.Value does, but
+// for any type:
+//
+//   - the exported fields of structs are generated recursively, unexported
+//     fields and fields tagged with "-" are left as is
+//   - slices and maps have up to size elements, halved at every level of
+//     nesting, and pointers are nil once in a while. Pointers, slices and
+//     maps of structs more than three levels deep are left empty, so
+//     recursive types end.
+//   - times are random times between 1970 and 2242 in UTC
+//   - wrappers registered with RegisterWrapper are valid or not at random
+//   - types implementing quick.Generator are generated with it
+//   - interfaces, chans, funcs and other types implementing
+//     encoding.TextUnmarshaler are zero
+//
+// Other types, such as numbers and strings, are generated with quick.Value.
+func QuickValue(t reflect.Type, r *rand.Rand, size int) reflect.Value {
+	v := reflect.New(t).Elem()
+	quickValue(v, r, size, 0)
+	return v
+}
+
+// quickValue sets v to a random value as described in QuickValue, where v is
+// nested depth levels below the generated value.
+func quickValue(v reflect.Value, r *rand.Rand, size, depth int) {
+	t := v.Type()
+
+	if t.Implements(generatorType) {
+		v.Set(reflect.Zero(t).Interface().(quick.Generator).Generate(r, size))
+		return
+	}
+
+	if w, ok := registered(t); ok && w.wrap != nil {
+		inner := reflect.New(w.inner).Elem()
+		quickValue(inner, r, size, depth)
+		v.Set(w.wrap(inner, r.Intn(2) == 0))
+		return
+	}
+
+	switch {
+	case t == timeType:
+		v.Set(reflect.ValueOf(time.Unix(r.Int63n(1<<33), r.Int63n(int64(time.Second))).UTC()))
+		return
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return
+	}
+
+	// the number of elements of slices and maps
+	n := func() int {
+		if depth+1 >= maxQuickDepth && envNested(t.Elem()) {
+			return 0
+		}
+
+		return r.Intn(size>>depth + 1)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if size == 0 || r.Intn(size) == 0 || (depth >= maxQuickDepth && envNested(t)) {
+			return
+		}
+
+		elem := reflect.New(t.Elem())
+		quickValue(elem.Elem(), r, size, depth)
+		v.Set(elem)
+	case reflect.Struct:
+		s := New(v.Addr().Interface())
+		for _, field := range s.structFields() {
+			quickValue(v.FieldByName(field.Name), r, size, depth+1)
+		}
+	case reflect.Slice:
+		l := n()
+		v.Set(reflect.MakeSlice(t, l, l))
+
+		for i := 0; i < l; i++ {
+			quickValue(v.Index(i), r, size, depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			quickValue(v.Index(i), r, size, depth+1)
+		}
+	case reflect.Map:
+		l := n()
+		v.Set(reflect.MakeMapWithSize(t, l))
+
+		for i := 0; i < l; i++ {
+			key := reflect.New(t.Key()).Elem()
+			elem := reflect.New(t.Elem()).Elem()
+
+			quickValue(key, r, size, depth+1)
+			quickValue(elem, r, size, depth+1)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Interface, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+	default:
+		if val, ok := quick.Value(t, r); ok {
+			v.Set(val)
+		}
+	}
+}
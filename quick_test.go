@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+type quickItem struct {
+	SKU   string
+	Price float64
+}
+
+type quickOrder struct {
+	ID      int64
+	Created time.Time
+	Items   []quickItem
+	Tags    map[string]bool
+	Parent  *quickOrder
+	Note    optional[string]
+	Any     interface{}
+	secret  int
+}
+
+func TestQuick(t *testing.T) {
+	sawItems, sawParent := false, false
+
+	f := func(q Quick[quickOrder]) bool {
+		o := q.Value
+		sawItems = sawItems || len(o.Items) > 0
+		sawParent = sawParent || o.Parent != nil
+
+		// a clone of a generated value is equal to it
+		return reflect.DeepEqual(Clone(o), o) && o.Created.Location() == time.UTC && o.Any == nil && o.secret == 0
+	}
+
+	if err := quick.Check(f, &quick.Config{Rand: rand.New(rand.NewSource(1))}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !sawItems || !sawParent {
+		t.Errorf("got items %t and parent %t, expected both", sawItems, sawParent)
+	}
+}
+
+func TestQuickValue_Recursive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20; i++ {
+		v := QuickValue(reflect.TypeOf(quickOrder{}), r, 50).Interface().(quickOrder)
+
+		depth := 0
+		for p := v.Parent; p != nil; p = p.Parent {
+			depth++
+		}
+
+		if depth > maxQuickDepth {
+			t.Fatalf("got a depth of %d", depth)
+		}
+
+		// Items is one level deep, so it has up to half the size
+		if len(v.Items) > 25 {
+			t.Fatalf("got %d items", len(v.Items))
+		}
+	}
+
+	if v := QuickValue(reflect.TypeOf(0), r, 10); v.Kind() != reflect.Int {
+		t.Errorf("got %v", v)
+	}
+}
@@ -0,0 +1,94 @@
+package structs
+
+import "reflect"
+
+// Redact returns a deep copy of s like Clone, with all fields tagged with the
+// "sensitive" option or a mask tag masked, so it can be passed to any logger
+// or encoder safely. String fields are set to their masked value as in Map,
+// all other fields to their zero value. Nested structs, including the ones
+// behind pointers and in slices, arrays and maps, are redacted recursively.
+//
+// If s was created from a pointer, a pointer to the copy is returned,
+// otherwise the copy itself.
+func (s *Struct) Redact() interface{} {
+	d := s.clonePtr()
+	redact(d.value, s.TagName, make(map[visit]bool))
+	return s.cloneResult(d)
+}
+
+// redact masks the sensitive fields of all structs found in v. Pointers
+// already redacted are recorded in seen to stop at pointer cycles.
+func redact(v reflect.Value, tagName string, seen map[visit]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+
+		key := visit{ptr: v.Pointer(), typ: v.Type()}
+		if seen[key] {
+			return
+		}
+
+		seen[key] = true
+		redact(v.Elem(), tagName, seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		// the value of an interface is not addressable
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		redact(elem, tagName, seen)
+		v.Set(elem)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redact(v.Index(i), tagName, seen)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// map elements are not addressable
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			redact(elem, tagName, seen)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			tag := field.Tag.Get(tagName)
+			if tag == "-" {
+				continue
+			}
+
+			_, tagOpts := parseTag(tag)
+
+			masked, ok := maskField(t, field, tagOpts, v.Field(i))
+			if !ok {
+				redact(v.Field(i), tagName, seen)
+				continue
+			}
+
+			if field.Type.Kind() == reflect.String {
+				v.Field(i).SetString(masked)
+			} else {
+				v.Field(i).Set(reflect.Zero(field.Type))
+			}
+		}
+	}
+}
+
+// Redact returns a deep copy of the given struct or pointer to struct with
+// all sensitive fields masked. For more info refer to Struct types Redact()
+// method. It panics if s's kind is not struct.
+func Redact(s interface{}) interface{} {
+	return New(s).Redact()
+}
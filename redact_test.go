@@ -0,0 +1,115 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type redactCredentials struct {
+	User     string
+	Password string `structs:"password,sensitive"`
+}
+
+type redactLogin struct {
+	Credentials redactCredentials
+	Token       *string `structs:",sensitive"`
+	Note        string  `structs:",sensitive,omitempty"`
+}
+
+func TestMap_Sensitive(t *testing.T) {
+	l := redactLogin{Credentials: redactCredentials{"gopher", "hunter2"}}
+
+	want := map[string]interface{}{
+		"Credentials": map[string]interface{}{
+			"User":     "gopher",
+			"password": "***",
+		},
+		"Token": "***",
+	}
+
+	if m := Map(l); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v want %#v", m, want)
+	}
+
+	if m := MapString(l.Credentials); m["password"] != "***" || m["User"] != "gopher" {
+		t.Errorf("MapString: got %#v", m)
+	}
+}
+
+type redactAddress struct {
+	City string
+}
+
+func TestMap_SensitivePassThrough(t *testing.T) {
+	type Session struct {
+		Login redactLogin
+		Raw   redactCredentials `structs:",omitnested"`
+		Any   []interface{}
+		Plain []redactAddress `structs:",omitnested"`
+	}
+
+	creds := redactCredentials{"gopher", "hunter2"}
+	sess := Session{
+		Login: redactLogin{Credentials: creds},
+		Raw:   creds,
+		Any:   []interface{}{creds},
+		Plain: []redactAddress{{City: "Berlin"}},
+	}
+
+	s := New(sess)
+	s.MaxDepth = 1
+
+	m := s.Map()
+	if login := m["Login"].(map[string]interface{}); login["Credentials"] != Mask {
+		t.Errorf("Login: got %#v", login)
+	}
+
+	for _, key := range []string{"Raw", "Any"} {
+		if m[key] != Mask {
+			t.Errorf("%s: got %#v want %q", key, m[key], Mask)
+		}
+	}
+
+	// values without a sensitive field are passed through as is
+	if _, ok := m["Plain"].([]redactAddress); !ok {
+		t.Errorf("Plain: got %#v", m["Plain"])
+	}
+
+	if v := Values(sess); v[len(v)-3] != Mask {
+		t.Errorf("Values: got %#v", v)
+	}
+}
+
+type redactAccount struct {
+	Logins  []redactLogin
+	ByName  map[string]redactCredentials
+	Any     interface{}
+	PIN     int    `structs:",sensitive"`
+	Ignored string `structs:"-"`
+}
+
+func TestRedact(t *testing.T) {
+	token := "t"
+	a := &redactAccount{
+		Logins: []redactLogin{{Credentials: redactCredentials{"a", "pw1"}, Token: &token}},
+		ByName: map[string]redactCredentials{"b": {"b", "pw2"}},
+		Any:    redactCredentials{"c", "pw3"},
+		PIN:    1234,
+	}
+
+	r := Redact(a).(*redactAccount)
+
+	want := &redactAccount{
+		Logins: []redactLogin{{Credentials: redactCredentials{"a", "***"}, Note: "***"}},
+		ByName: map[string]redactCredentials{"b": {"b", "***"}},
+		Any:    redactCredentials{"c", "***"},
+	}
+
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("got %+v want %+v", r, want)
+	}
+
+	if a.Logins[0].Credentials.Password != "pw1" || a.ByName["b"].Password != "pw2" || a.PIN != 1234 || *a.Logins[0].Token != "t" {
+		t.Errorf("the original was modified: %+v", a)
+	}
+}
@@ -0,0 +1,187 @@
+package structs
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// RedisHash returns the field/value pairs of s as a flat slice, ready to be
+// passed to HSET, i.e. ["Name", "gopher", "ID", "42"]. Field names and the "-"
+// and "omitempty" options are handled as in Map. Values are formatted as
+// follows:
+//
+//   string, []byte and [N]byte      => as is
+//   bool, int, uint and float kinds => strconv formatting
+//   encoding.TextMarshaler          => MarshalText()
+//   nil pointers                    => field is skipped
+//   anything else                   => JSON encoding
+//
+// Fields with a format tag are formatted as described in MapString. It panics
+// if a value can't be formatted.
+func (s *Struct) RedisHash() []string {
+	var pairs []string
+
+	for _, field := range s.structFields() {
+		val := s.value.FieldByName(field.Name)
+
+		name, tagOpts := s.key(field)
+
+		if tagOpts.Has("omitempty") && isZeroValue(val) {
+			continue
+		}
+
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			continue
+		}
+
+		str, err := formatField(field, val)
+		if err != nil {
+			panic(err)
+		}
+
+		pairs = append(pairs, name, str)
+	}
+
+	return pairs
+}
+
+// FromRedisHash sets the fields of s from the given HGETALL result. Values
+// are parsed according to the field's kind, which is the inverse of
+// RedisHash. Hash fields without a matching struct field are ignored. It
+// returns an error if s was not created from a pointer. If values can't be
+// parsed, all of them are reported with a FieldErrors.
+func (s *Struct) FromRedisHash(hash map[string]string) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	var errs FieldErrors
+
+	for _, field := range s.structFields() {
+		name, _ := s.key(field)
+
+		str, ok := hash[name]
+		if !ok {
+			continue
+		}
+
+		if err := parseString(s.value.FieldByName(field.Name), str); err != nil {
+			errs.add(field.Name, field.Type, "string", err)
+		}
+	}
+
+	return errs.err()
+}
+
+// formatString returns the string representation of v.
+func formatString(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return "", nil
+		}
+
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return string(b), nil
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "", nil
+		}
+
+		return formatString(v.Elem())
+	}
+
+	b, err := json.Marshal(v.Interface())
+	return string(b), err
+}
+
+// parseString parses str according to v's kind and sets it to v. Nil
+// pointers are allocated as needed.
+func parseString(v reflect.Value, str string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return parseString(v.Elem(), str)
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(str))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return setNumber(v, str)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return json.Unmarshal([]byte(str), v.Addr().Interface())
+		}
+
+		if v.Kind() == reflect.Slice {
+			v.SetBytes([]byte(str))
+			return nil
+		}
+
+		if v.Len() < len(str) {
+			return fmt.Errorf("%w: can't set %d bytes into %s", ErrTypeMismatch, len(str), v.Type())
+		}
+
+		v.Set(reflect.Zero(v.Type()))
+		reflect.Copy(v, reflect.ValueOf([]byte(str)))
+	default:
+		return json.Unmarshal([]byte(str), v.Addr().Interface())
+	}
+
+	return nil
+}
+
+// RedisHash returns the field/value pairs of s for HSET. For more info refer
+// to Struct types RedisHash() method. It panics if s's kind is not struct.
+func RedisHash(s interface{}) []string {
+	return New(s).RedisHash()
+}
+
+// FromRedisHash sets the fields of the struct pointed to by dst from the
+// given HGETALL result. For more info refer to Struct types FromRedisHash()
+// method. It returns an error if dst is not a pointer to struct.
+func FromRedisHash(hash map[string]string, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.FromRedisHash(hash)
+}
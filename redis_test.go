@@ -0,0 +1,71 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type redisUser struct {
+	Name    string `redis:"name"`
+	Age     int    `redis:"age"`
+	Admin   bool
+	Score   float32
+	Avatar  []byte
+	Tags    []string
+	Created time.Time
+	Manager *redisUser
+	Note    string `redis:",omitempty"`
+}
+
+func TestRedisHash(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	u := redisUser{
+		Name:    "fatih",
+		Age:     30,
+		Admin:   true,
+		Score:   0.5,
+		Avatar:  []byte("png"),
+		Tags:    []string{"a", "b"},
+		Created: created,
+	}
+
+	s := New(u)
+	s.TagName = "redis"
+	pairs := s.RedisHash()
+
+	want := []string{
+		"name", "fatih",
+		"age", "30",
+		"Admin", "true",
+		"Score", "0.5",
+		"Avatar", "png",
+		"Tags", `["a","b"]`,
+		"Created", "2020-01-02T03:04:05Z",
+	}
+
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("RedisHash should return\n%q\ngot\n%q", want, pairs)
+	}
+
+	hash := make(map[string]string)
+	for i := 0; i < len(pairs); i += 2 {
+		hash[pairs[i]] = pairs[i+1]
+	}
+
+	var out redisUser
+	d := New(&out)
+	d.TagName = "redis"
+
+	if err := d.FromRedisHash(hash); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, u) {
+		t.Errorf("FromRedisHash should return\n%+v\ngot\n%+v", u, out)
+	}
+
+	if err := FromRedisHash(map[string]string{"Age": "old"}, &out); err == nil {
+		t.Error("FromRedisHash should return an error for unparsable values")
+	}
+}
@@ -0,0 +1,140 @@
+package structs
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reloader keeps a live config struct of type T up to date with the values
+// of a load function, such as one calling Load, and notifies callbacks of the
+// fields which changed:
+//
+//   r := structs.NewReloader(&cfg, func() (Config, error) {
+//       var c Config
+//       _, err := structs.Load(&c, structs.DefaultsSource(), structs.EnvSource("APP_"))
+//       return c, err
+//   })
+//
+//   r.OnChange(func(changes []structs.Change) {
+//       pool.Resize(r.Get().DB.MaxConns)
+//   }, "DB.MaxConns")
+//
+//   go r.Watch(ctx, time.Minute, func(err error) { log.Print(err) })
+//
+// The live struct is replaced while holding a lock, so readers running
+// concurrently with reloads should use Get instead of reading it directly.
+// It's safe to use a Reloader concurrently.
+type Reloader[T any] struct {
+	mu   sync.RWMutex
+	live *T
+	load func() (T, error)
+
+	// reloadMu serializes reloads, so callbacks see the changes in order
+	reloadMu  sync.Mutex
+	callbacks []reloadCallback
+}
+
+// reloadCallback is a callback registered with OnChange.
+type reloadCallback struct {
+	fn    func([]Change)
+	paths []string
+}
+
+// NewReloader returns a Reloader which updates the struct pointed to by
+// live with the values returned by load.
+func NewReloader[T any](live *T, load func() (T, error)) *Reloader[T] {
+	return &Reloader[T]{live: live, load: load}
+}
+
+// OnChange registers fn to be called with the changes of a reload, in the
+// order of the fields. If paths are given, fn is only called with the
+// changes of the fields at these paths or below them, such as "DB" for
+// "DB.Host", and only if there are any. Callbacks are called in the order
+// they were registered, after the live struct was updated.
+func (r *Reloader[T]) OnChange(fn func(changes []Change), paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.callbacks = append(r.callbacks, reloadCallback{fn: fn, paths: paths})
+}
+
+// Get returns a copy of the live struct.
+func (r *Reloader[T]) Get() T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return *r.live
+}
+
+// Reload calls the load function and, if it succeeds, replaces the live
+// struct with its result and calls the callbacks with the changes, which are
+// computed as with Diff. It returns the changes, which are empty if nothing
+// changed. The live struct is left untouched if load returns an error.
+func (r *Reloader[T]) Reload() ([]Change, error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	fresh, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	changes := New(r.live).Diff(&fresh)
+	*r.live = fresh
+	callbacks := r.callbacks
+	r.mu.Unlock()
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	for _, cb := range callbacks {
+		if matched := matchChanges(changes, cb.paths); len(matched) > 0 {
+			cb.fn(matched)
+		}
+	}
+
+	return changes, nil
+}
+
+// Watch reloads every interval until ctx is done. Errors of the load
+// function are passed to onError, if it's not nil, and the live struct is
+// kept as is until a later reload succeeds.
+func (r *Reloader[T]) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// matchChanges returns the changes of the fields at the given paths or
+// below them, or all changes if there are no paths.
+func matchChanges(changes []Change, paths []string) []Change {
+	if len(paths) == 0 {
+		return changes
+	}
+
+	var matched []Change
+	for _, c := range changes {
+		for _, path := range paths {
+			if c.Path == path || strings.HasPrefix(c.Path, path+".") {
+				matched = append(matched, c)
+				break
+			}
+		}
+	}
+
+	return matched
+}
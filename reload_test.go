@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type reloadConfig struct {
+	Port int
+	DB   configDatabase
+}
+
+func TestReloader(t *testing.T) {
+	live := reloadConfig{Port: 80}
+
+	next := live
+	var loadErr error
+
+	r := NewReloader(&live, func() (reloadConfig, error) {
+		return next, loadErr
+	})
+
+	var all, db [][]Change
+	r.OnChange(func(c []Change) { all = append(all, c) })
+	r.OnChange(func(c []Change) { db = append(db, c) }, "DB")
+
+	next.Port = 81
+	changes, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Change{{Path: "Port", Old: 80, New: 81}}
+	if !reflect.DeepEqual(changes, want) || !reflect.DeepEqual(all, [][]Change{want}) || len(db) != 0 {
+		t.Errorf("got changes %v, all %v and db %v", changes, all, db)
+	}
+
+	if live.Port != 81 || r.Get().Port != 81 {
+		t.Errorf("live struct not updated: %+v", live)
+	}
+
+	next.DB.Host = "db"
+	next.Port = 82
+	if _, err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantDB := [][]Change{{{Path: "DB.Host", Old: "", New: "db"}}}
+	if !reflect.DeepEqual(db, wantDB) || len(all) != 2 || len(all[1]) != 2 {
+		t.Errorf("got all %v and db %v", all, db)
+	}
+
+	// nothing changed
+	if changes, err := r.Reload(); err != nil || changes != nil || len(all) != 2 {
+		t.Errorf("got %v, %v and %d calls", changes, err, len(all))
+	}
+
+	loadErr = errors.New("boom")
+	next.Port = 1
+	if _, err := r.Reload(); err != loadErr || live.Port != 82 {
+		t.Errorf("got %v and port %d", err, live.Port)
+	}
+}
+
+func TestReloader_Watch(t *testing.T) {
+	var live reloadConfig
+
+	n := 0
+	r := NewReloader(&live, func() (reloadConfig, error) {
+		n++
+		if n == 1 {
+			return reloadConfig{}, errors.New("boom")
+		}
+
+		return reloadConfig{Port: n}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	changed := make(chan int, 10)
+	r.OnChange(func(c []Change) { changed <- c[0].New.(int) })
+
+	errs := make(chan error, 10)
+	done := make(chan struct{})
+	go func() {
+		r.Watch(ctx, time.Millisecond, func(err error) { errs <- err })
+		close(done)
+	}()
+
+	if err := <-errs; err.Error() != "boom" {
+		t.Errorf("got %v", err)
+	}
+
+	if port := <-changed; port != 2 {
+		t.Errorf("got port %d", port)
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,265 @@
+package structs
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAcceptable is returned by WriteResponse if none of the media types
+// in the Accept header can be written.
+var ErrNotAcceptable = errors.New("not acceptable")
+
+// encoder writes a struct in a media type supported by WriteResponse.
+type encoder struct {
+	mediaType   string
+	contentType string
+	encode      func(s *Struct, w io.Writer) error
+}
+
+// encoders are the encoders of WriteResponse, in the order they're preferred
+// if the Accept header allows several of them with the same quality.
+var encoders = []encoder{
+	{"application/json", "application/json", encodeJSON},
+	{"application/xml", "application/xml; charset=utf-8", encodeXML},
+	{"text/xml", "text/xml; charset=utf-8", encodeXML},
+	{"text/csv", "text/csv; charset=utf-8", encodeCSV},
+}
+
+// WriteResponse writes s to w in the media type preferred by the given
+// Accept header, along with its Content-Type, so handlers can serve JSON,
+// XML and CSV clients with a single call:
+//
+//   func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+//       ...
+//       if err := structs.WriteResponse(w, r.Header.Get("Accept"), user); err != nil {
+//           http.Error(w, err.Error(), http.StatusNotAcceptable)
+//       }
+//   }
+//
+// All formats are written from the output of Map, so keys, omitted fields
+// and masked values are the same for each of them:
+//
+//   - application/json is the JSON encoding of Map
+//   - application/xml and text/xml have an element named after the struct's
+//     type, or "struct" for unnamed types, with an element per key in
+//     sorted order. Nested structs and maps are nested elements, slices
+//     repeat the element of their key.
+//   - text/csv has a header row with the keys of Flatten, joined by ".",
+//     in sorted order and a row with their values
+//
+// Values are formatted as in RedisHash. Media types are chosen by their
+// quality in the Accept header, preferring JSON, XML and CSV in this order.
+// JSON is written if the header is empty. The body is encoded before
+// anything is written, so w is left untouched if encoding fails. It returns
+// ErrNotAcceptable if none of the media types is accepted.
+func (s *Struct) WriteResponse(w http.ResponseWriter, accept string) error {
+	enc, ok := negotiate(accept)
+	if !ok {
+		return ErrNotAcceptable
+	}
+
+	var buf bytes.Buffer
+	if err := enc.encode(s, &buf); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", enc.contentType)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// negotiate returns the encoder of the media type with the highest quality
+// in the Accept header accept.
+func negotiate(accept string) (encoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return encoders[0], true
+	}
+
+	best, bestQ := -1, 0.0
+	for i, enc := range encoders {
+		if q := quality(accept, enc.mediaType); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	if best < 0 {
+		return encoder{}, false
+	}
+
+	return encoders[best], true
+}
+
+// quality returns the quality of mediaType in the Accept header accept,
+// which is given by its most specific media range. It's 0 if the media type
+// isn't accepted.
+func quality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, r := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil {
+			continue
+		}
+
+		var spec int
+		switch rangeType {
+		case mediaType:
+			spec = 2
+		case typ + "/*":
+			spec = 1
+		case "*/*":
+			spec = 0
+		default:
+			continue
+		}
+
+		if spec < specificity {
+			continue
+		}
+
+		rq := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				rq = f
+			}
+		}
+
+		q, specificity = rq, spec
+	}
+
+	return q
+}
+
+// encodeJSON writes s as JSON.
+func encodeJSON(s *Struct, w io.Writer) error {
+	return json.NewEncoder(w).Encode(s.Map())
+}
+
+// encodeXML writes s as XML.
+func encodeXML(s *Struct, w io.Writer) error {
+	name := s.Name()
+	if name == "" {
+		name = "struct"
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	if err := encodeXMLValue(enc, name, s.Map()); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// encodeXMLValue writes v as an element with the given name. Slices are
+// written as one element per item.
+func encodeXMLValue(enc *xml.Encoder, name string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return enc.EncodeElement("", xml.StartElement{Name: xml.Name{Local: name}})
+	}
+
+	if _, ok := v.(encoding.TextMarshaler); !ok {
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				break
+			}
+
+			for i := 0; i < rv.Len(); i++ {
+				if err := encodeXMLValue(enc, name, rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		case reflect.Map:
+			start := xml.StartElement{Name: xml.Name{Local: name}}
+			if err := enc.EncodeToken(start); err != nil {
+				return err
+			}
+
+			keys := make(map[string]reflect.Value, rv.Len())
+			names := make([]string, 0, rv.Len())
+			for _, k := range rv.MapKeys() {
+				key := fmt.Sprint(k.Interface())
+				keys[key] = k
+				names = append(names, key)
+			}
+
+			sort.Strings(names)
+
+			for _, key := range names {
+				if err := encodeXMLValue(enc, key, rv.MapIndex(keys[key]).Interface()); err != nil {
+					return err
+				}
+			}
+
+			return enc.EncodeToken(start.End())
+		}
+	}
+
+	str, err := formatString(rv)
+	if err != nil {
+		return err
+	}
+
+	return enc.EncodeElement(str, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// encodeCSV writes s as CSV.
+func encodeCSV(s *Struct, w io.Writer) error {
+	flat := s.Flatten(".")
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	row := make([]string, len(keys))
+	for i, k := range keys {
+		v := reflect.ValueOf(flat[k])
+		if !v.IsValid() {
+			continue
+		}
+
+		str, err := formatString(v)
+		if err != nil {
+			return err
+		}
+
+		row[i] = str
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(keys)
+	cw.Write(row)
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteResponse writes the given struct to w in the media type preferred by
+// the Accept header. For more info refer to Struct types WriteResponse()
+// method. It panics if s's kind is not struct.
+func WriteResponse(w http.ResponseWriter, accept string, s interface{}) error {
+	return New(s).WriteResponse(w, accept)
+}
@@ -0,0 +1,96 @@
+package structs
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type respondAddress struct {
+	City string `structs:"city"`
+}
+
+type respondUser struct {
+	Name     string         `structs:"name"`
+	Age      int            `structs:"age"`
+	Tags     []string       `structs:"tag"`
+	Address  respondAddress `structs:"address"`
+	Password string         `structs:"password,sensitive"`
+	Note     string         `structs:"note,omitempty"`
+}
+
+func TestWriteResponse(t *testing.T) {
+	u := respondUser{
+		Name:     "Ann",
+		Age:      30,
+		Tags:     []string{"a", "b"},
+		Address:  respondAddress{City: "Oslo"},
+		Password: "secret",
+	}
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			"",
+			"application/json",
+			`{"address":{"city":"Oslo"},"age":30,"name":"Ann","password":"***","tag":["a","b"]}` + "\n",
+		},
+		{
+			"text/html, application/xml;q=0.9, */*;q=0.1",
+			"application/xml; charset=utf-8",
+			`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<respondUser><address><city>Oslo</city></address><age>30</age><name>Ann</name>` +
+				`<password>***</password><tag>a</tag><tag>b</tag></respondUser>`,
+		},
+		{
+			"text/csv",
+			"text/csv; charset=utf-8",
+			"address.city,age,name,password,tag\nOslo,30,Ann,***,\"[\"\"a\"\",\"\"b\"\"]\"\n",
+		},
+		{
+			"text/*;q=0.5, application/json;q=0.4",
+			"text/xml; charset=utf-8",
+			"",
+		},
+		{
+			"application/*, text/csv;q=0.1",
+			"application/json",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+
+		if err := WriteResponse(w, tt.accept, u); err != nil {
+			t.Errorf("%q: %v", tt.accept, err)
+			continue
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%q: got Content-Type %q want %q", tt.accept, ct, tt.contentType)
+		}
+
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%q: got body\n%s\nwant\n%s", tt.accept, w.Body, tt.body)
+		}
+	}
+}
+
+func TestWriteResponse_NotAcceptable(t *testing.T) {
+	for _, accept := range []string{"text/html", "application/json;q=0, */*;q=0"} {
+		w := httptest.NewRecorder()
+
+		err := New(respondUser{}).WriteResponse(w, accept)
+		if !errors.Is(err, ErrNotAcceptable) {
+			t.Errorf("%q: got %v", accept, err)
+		}
+
+		if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+			t.Errorf("%q: expected nothing to be written", accept)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// TransformTagName is the tag name which lists the transforms applied
+	// to a field by Sanitize, such as `transform:"trim,lower,truncate=64"`.
+	TransformTagName = "transform"
+)
+
+var (
+	transformsMu sync.RWMutex
+	transforms   = map[string]func(v interface{}, arg string) (interface{}, error){
+		"trim":     stringTransform(func(s, _ string) (string, error) { return strings.TrimSpace(s), nil }),
+		"lower":    stringTransform(func(s, _ string) (string, error) { return strings.ToLower(s), nil }),
+		"upper":    stringTransform(func(s, _ string) (string, error) { return strings.ToUpper(s), nil }),
+		"squash":   stringTransform(squash),
+		"truncate": stringTransform(truncate),
+		"round":    round,
+	}
+)
+
+// RegisterTransform registers the function applied by Sanitize to the
+// fields whose transform tag lists the given name. The function is called
+// with the field's value and the argument given after "=" in the tag, if
+// any, and returns the new value, which is assigned as described in Fill.
+// Values of basic kinds are passed as string, int64, uint64, float64 or
+// bool, so a transform of strings handles a type Email string too. The
+// following transforms are registered by default:
+//
+//   trim       => removes leading and trailing white space
+//   lower      => converts to lower case
+//   upper      => converts to upper case
+//   squash     => replaces runs of white space with a single space
+//   truncate=N => keeps the first N characters
+//   round=N    => rounds floats to N decimals, 0 if N is missing
+//
+// Registering the same name again replaces the previous function. It's safe
+// to call RegisterTransform concurrently.
+func RegisterTransform(name string, fn func(v interface{}, arg string) (interface{}, error)) {
+	transformsMu.Lock()
+	defer transformsMu.Unlock()
+
+	transforms[name] = fn
+}
+
+// Sanitize applies the transforms listed in the transform tags of the fields
+// of s and of the structs nested in it, in the order they're listed, so
+// input cleaning lives next to the field declarations:
+//
+//   type Signup struct {
+//       Email string   `transform:"trim,lower"`
+//       Bio   string   `transform:"squash,truncate=280"`
+//       Tags  []string `transform:"trim,lower"`
+//   }
+//
+// The fields are the ones visited by Edit. The transforms of a slice, array
+// or map apply to its elements, the transforms of a pointer to the value it
+// points to if it's not nil. Fields whose transforms fail, such as with an
+// unknown transform, are left as is and reported with a FieldErrors. It
+// returns an error if s was not created from a pointer.
+func (s *Struct) Sanitize() error {
+	var errs FieldErrors
+
+	err := s.Edit(func(path string, f *Field) error {
+		tag, ok := f.TagOk(TransformTagName)
+		if !ok || tag == "" {
+			return nil
+		}
+
+		if err := s.sanitize(f.value, strings.Split(tag, ",")); err != nil {
+			errs.add(path, f.value.Type(), fmt.Sprintf("%T", f.Value()), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errs.err()
+}
+
+// sanitize applies the transforms given by names to v, or to the elements
+// of v.
+func (s *Struct) sanitize(v reflect.Value, names []string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return s.sanitize(v.Elem(), names)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := s.sanitize(v.Index(i), names); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		for _, k := range sortedKeys(v) {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(k))
+
+			if err := s.sanitize(elem, names); err != nil {
+				return fmt.Errorf("key %v: %w", k, err)
+			}
+
+			v.SetMapIndex(k, elem)
+		}
+
+		return nil
+	}
+
+	val := basicValue(v)
+	for _, name := range names {
+		name, arg, _ := strings.Cut(strings.TrimSpace(name), "=")
+
+		transformsMu.RLock()
+		fn, ok := transforms[name]
+		transformsMu.RUnlock()
+
+		if !ok {
+			return fmt.Errorf("unknown transform %q", name)
+		}
+
+		var err error
+		if val, err = fn(val, arg); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return s.assign(v, val)
+}
+
+// basicValue returns the value of v, as the basic type of its kind if it
+// has one, as described in RegisterTransform.
+func basicValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	}
+
+	return v.Interface()
+}
+
+// stringTransform returns a transform which applies fn to strings and fails
+// for other values.
+func stringTransform(fn func(s, arg string) (string, error)) func(interface{}, string) (interface{}, error) {
+	return func(v interface{}, arg string) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T is not a string", ErrTypeMismatch, v)
+		}
+
+		return fn(s, arg)
+	}
+}
+
+// squash replaces runs of white space in s with a single space.
+func squash(s, _ string) (string, error) {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " "), nil
+}
+
+// truncate returns the first arg characters of s.
+func truncate(s, arg string) (string, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("invalid length %q", arg)
+	}
+
+	if utf8.RuneCountInString(s) <= n {
+		return s, nil
+	}
+
+	return string([]rune(s)[:n]), nil
+}
+
+// round rounds the float v to arg decimals.
+func round(v interface{}, arg string) (interface{}, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not a float", ErrTypeMismatch, v)
+	}
+
+	n := 0
+	if arg != "" {
+		var err error
+		if n, err = strconv.Atoi(arg); err != nil {
+			return nil, fmt.Errorf("invalid decimals %q", arg)
+		}
+	}
+
+	p := math.Pow10(n)
+	return math.Round(f*p) / p, nil
+}
+
+// Sanitize applies the transforms listed in the transform tags of the fields
+// of the struct pointed to by dst. For more info refer to Struct types
+// Sanitize() method. It returns an error if dst is not a pointer to struct.
+func Sanitize(dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Sanitize()
+}
@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type sanitizeEmail string
+
+type sanitizeProfile struct {
+	Bio string `transform:"squash,truncate=5"`
+}
+
+type sanitizeSignup struct {
+	Email    sanitizeEmail     `transform:"trim,lower"`
+	Name     *string           `transform:"trim, upper"`
+	Tags     []string          `transform:"trim"`
+	Labels   map[string]string `transform:"lower"`
+	Score    float32           `transform:"round=1"`
+	Profiles []sanitizeProfile
+	Nested   map[string]sanitizeProfile
+	Raw      string
+}
+
+func TestSanitize(t *testing.T) {
+	name := " ann "
+	s := sanitizeSignup{
+		Email:    " Ann@Example.COM ",
+		Name:     &name,
+		Tags:     []string{" a ", "b "},
+		Labels:   map[string]string{"K": "V"},
+		Score:    1.26,
+		Profiles: []sanitizeProfile{{Bio: "  hello \n  wörld "}},
+		Nested:   map[string]sanitizeProfile{"x": {Bio: "abcdefg"}},
+		Raw:      " raw ",
+	}
+
+	if err := Sanitize(&s); err != nil {
+		t.Fatal(err)
+	}
+
+	want := sanitizeSignup{
+		Email:    "ann@example.com",
+		Name:     &name,
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"K": "v"},
+		Score:    1.3,
+		Profiles: []sanitizeProfile{{Bio: "hello"}},
+		Nested:   map[string]sanitizeProfile{"x": {Bio: "abcde"}},
+		Raw:      " raw ",
+	}
+
+	if name != "ANN" || !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v\nwant %+v", s, want)
+	}
+}
+
+func TestSanitize_Errors(t *testing.T) {
+	var s struct {
+		A string  `transform:"trim,missing"`
+		B int     `transform:"lower"`
+		C string  `transform:"truncate=x"`
+		D float64 `transform:"round"`
+	}
+	s.A, s.D = " a ", 1.5
+
+	err := Sanitize(&s)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("got %v", err)
+	}
+
+	for i, want := range []string{`A (string, got string): unknown transform "missing"`, "B (int, got int): lower: type mismatch", `C (string, got string): truncate: invalid length "x"`} {
+		if !strings.HasPrefix(errs[i].Error(), want) {
+			t.Errorf("got %q, want %q", errs[i], want)
+		}
+	}
+
+	if s.A != " a " || s.D != 2 {
+		t.Errorf("got %+v", s)
+	}
+
+	if err := Sanitize(s); err == nil {
+		t.Error("expected an error for a struct which is not a pointer")
+	}
+}
+
+func TestRegisterTransform(t *testing.T) {
+	RegisterTransform("reverse", stringTransform(func(s, _ string) (string, error) {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+
+		return string(r), nil
+	}))
+	defer func() {
+		transformsMu.Lock()
+		delete(transforms, "reverse")
+		transformsMu.Unlock()
+	}()
+
+	s := struct {
+		A string `transform:"reverse"`
+	}{"abc"}
+
+	if err := Sanitize(&s); err != nil || s.A != "cba" {
+		t.Errorf("got %q, %v", s.A, err)
+	}
+}
@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+var (
+	// SecretTagName is the tag name which gives the path of a field's
+	// secret, such as `secret:"db/password"`, for ResolveSecrets.
+	SecretTagName = "secret"
+)
+
+// SecretResolver retrieves secrets, such as from Vault, AWS SSM or files,
+// for ResolveSecrets.
+type SecretResolver interface {
+	// ResolveSecret returns the secret at the given path. The boolean is
+	// false if there is no such secret.
+	ResolveSecret(ctx context.Context, path string) (string, bool, error)
+}
+
+// SecretResolverFunc is an adapter to use a function as a SecretResolver.
+type SecretResolverFunc func(ctx context.Context, path string) (string, bool, error)
+
+// ResolveSecret calls f(ctx, path).
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, path string) (string, bool, error) {
+	return f(ctx, path)
+}
+
+// FileSecrets returns a SecretResolver which reads secrets from the files in
+// dir, such as the /run/secrets directory of Docker and Kubernetes. The path
+// of a secret is the name of its file relative to dir, a trailing newline is
+// removed. Paths must not leave dir.
+func FileSecrets(dir string) SecretResolver {
+	return SecretResolverFunc(func(_ context.Context, path string) (string, bool, error) {
+		if !filepath.IsLocal(path) {
+			return "", false, os.ErrInvalid
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, path))
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+
+		if err != nil {
+			return "", false, err
+		}
+
+		return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), true, nil
+	})
+}
+
+// ResolveSecrets sets the fields of s which have a secret tag to the secrets
+// at the paths given in their tags, retrieved from r:
+//
+//   type Config struct {
+//       DatabaseURL string `secret:"db/url"`
+//       APIKey      []byte `secret:"api-key"`
+//   }
+//
+// Secrets are parsed into the fields as in LoadEnv and fields of nested
+// structs are resolved too, while nil pointers to them are only allocated if
+// at least one of their secrets is found. Fields whose secret doesn't exist
+// are left as is. It returns an error if s was not created from a pointer.
+// Secrets which can't be retrieved or parsed are reported with a FieldErrors.
+func (s *Struct) ResolveSecrets(ctx context.Context, r SecretResolver) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	_, err := secretSource(ctx, r).loadPrefix(s, "")
+	return err
+}
+
+// secretSource returns the source of the secrets of r.
+func secretSource(ctx context.Context, r SecretResolver) stringSource {
+	return stringSource{
+		name: func(field reflect.StructField, _ string) (string, tagOptions, bool) {
+			return field.Tag.Get(SecretTagName), nil, true
+		},
+		lookup: func(_ reflect.StructField, path string) (string, bool, error) {
+			if path == "" {
+				return "", false, nil
+			}
+
+			return r.ResolveSecret(ctx, path)
+		},
+		sigil: "secret ",
+	}
+}
+
+// SecretSource returns the source named "secrets" for Load which resolves
+// the secrets of the fields with r, as ResolveSecrets does.
+func SecretSource(ctx context.Context, r SecretResolver) Source {
+	src := secretSource(ctx, r)
+
+	return Source{
+		Name: "secrets",
+		Load: func(s *Struct) ([]string, error) {
+			return src.loadPrefix(s, "")
+		},
+	}
+}
+
+// ResolveSecrets sets the fields of the struct pointed to by dst which have
+// a secret tag from r. For more info refer to Struct types ResolveSecrets()
+// method. It returns an error if dst is not a pointer to struct.
+func ResolveSecrets(ctx context.Context, r SecretResolver, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.ResolveSecrets(ctx, r)
+}
@@ -0,0 +1,116 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type secretDatabase struct {
+	Password string `secret:"db/password"`
+}
+
+type secretConfig struct {
+	Name    string
+	APIKey  []byte `secret:"api-key"`
+	Port    int    `secret:"port"`
+	Missing string `secret:"missing"`
+	DB      secretDatabase
+	Replica *secretDatabase
+	Cache   *struct {
+		Token string `secret:"cache/token"`
+	}
+}
+
+func testSecrets(t *testing.T) SecretResolver {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"api-key":        "key\n",
+		"port":           "5432",
+		"db/password":    "pw\r\n",
+		"cache/password": "unused",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return FileSecrets(dir)
+}
+
+func TestResolveSecrets(t *testing.T) {
+	c := secretConfig{Name: "app", Missing: "kept"}
+
+	if err := ResolveSecrets(context.Background(), testSecrets(t), &c); err != nil {
+		t.Fatal(err)
+	}
+
+	want := secretConfig{
+		Name:    "app",
+		APIKey:  []byte("key"),
+		Port:    5432,
+		Missing: "kept",
+		DB:      secretDatabase{Password: "pw"},
+		Replica: &secretDatabase{Password: "pw"},
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v want %#v", c, want)
+	}
+}
+
+func TestResolveSecrets_Errors(t *testing.T) {
+	boom := errors.New("boom")
+	r := SecretResolverFunc(func(_ context.Context, path string) (string, bool, error) {
+		switch path {
+		case "port":
+			return "x", true, nil
+		case "db/password":
+			return "", false, boom
+		}
+
+		return "", false, nil
+	})
+
+	var c secretConfig
+	err := New(&c).ResolveSecrets(context.Background(), r)
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) != 3 {
+		t.Fatalf("expected 3 field errors, got %v", err)
+	}
+
+	if !errors.Is(err, boom) {
+		t.Errorf("expected the resolver's error, got %v", err)
+	}
+
+	if _, _, err := FileSecrets(t.TempDir()).ResolveSecret(context.Background(), "../etc/passwd"); err == nil {
+		t.Error("expected an error for a path outside the directory")
+	}
+}
+
+func TestFill_Secrets(t *testing.T) {
+	var c secretConfig
+
+	s := New(&c)
+	s.Secrets = testSecrets(t)
+
+	if err := s.Fill(map[string]interface{}{"Name": "app", "Port": 80}); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Name != "app" || c.Port != 5432 || c.DB.Password != "pw" {
+		t.Errorf("got %#v", c)
+	}
+}
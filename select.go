@@ -0,0 +1,33 @@
+package structs
+
+// Select returns the fields of s and of the structs nested in it for which
+// match returns true, in the order Walk visits them, such as all string
+// fields tagged with pii:
+//
+//   fields := structs.Select(s, func(f *structs.Field) bool {
+//       _, ok := f.TagOk("pii")
+//       return ok && f.Kind() == reflect.String
+//   })
+//
+// Fields of structs in maps and interfaces are copies, which can't be set.
+// Use Walk to get the paths of the fields as well.
+func (s *Struct) Select(match func(f *Field) bool) []*Field {
+	var fields []*Field
+
+	s.Walk(func(_ string, f *Field) error {
+		if match(f) {
+			fields = append(fields, f)
+		}
+
+		return nil
+	})
+
+	return fields
+}
+
+// Select returns the fields of the given struct and of the structs nested in
+// it for which match returns true. For more info refer to Struct types
+// Select() method. It panics if s's kind is not struct.
+func Select(s interface{}, match func(f *Field) bool) []*Field {
+	return New(s).Select(match)
+}
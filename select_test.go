@@ -0,0 +1,53 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type selectContact struct {
+	Email string `pii:""`
+	Opt   bool   `pii:""`
+}
+
+type selectUser struct {
+	Name     string `pii:"name"`
+	Role     string
+	Contacts []selectContact
+	Primary  *selectContact
+}
+
+func TestSelect(t *testing.T) {
+	u := &selectUser{
+		Contacts: []selectContact{{Email: "a@example.com"}, {Email: "b@example.com"}},
+	}
+
+	fields := Select(u, func(f *Field) bool {
+		_, ok := f.TagOk("pii")
+		return ok && f.Kind() == reflect.String
+	})
+
+	var values []interface{}
+	for _, f := range fields {
+		values = append(values, f.Value())
+	}
+
+	want := []interface{}{"", "a@example.com", "b@example.com"}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("got %v want %v", values, want)
+	}
+
+	for _, f := range fields {
+		if err := f.Set("redacted"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if u.Name != "redacted" || u.Contacts[1].Email != "redacted" || u.Role != "" {
+		t.Errorf("got %+v", u)
+	}
+
+	if fields := Select(u, func(*Field) bool { return false }); fields != nil {
+		t.Errorf("got %v", fields)
+	}
+}
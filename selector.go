@@ -0,0 +1,161 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Paths returns the paths of the fields of T selected by the given
+// functions, such as for ZeroFields or ClonePick. Unlike field names in
+// strings, selectors are checked by the compiler, so renaming a field can't
+// silently break them. A selector returns a pointer to the selected field:
+//
+//   paths, err := structs.Paths(
+//       func(u *User) interface{} { return &u.Password },
+//       func(u *User) interface{} { return &u.Session.Token },
+//   )
+//   // paths => ["Password", "Session.Token"]
+//
+// Selectors are called with a zero T whose nested struct pointers are
+// allocated, so they may select fields behind pointers too. It returns an
+// error wrapping ErrFieldNotFound if a selector doesn't return a pointer to
+// an exported field of T.
+func Paths[T any](selectors ...func(*T) interface{}) ([]string, error) {
+	t := reflect.New(reflect.TypeOf((*T)(nil)).Elem())
+	if t.Elem().Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+
+	allocStructs(t.Elem(), map[reflect.Type]bool{t.Type().Elem(): true})
+
+	paths := make([]string, len(selectors))
+
+	for i, sel := range selectors {
+		p := reflect.ValueOf(sel(t.Interface().(*T)))
+		if p.Kind() != reflect.Ptr || p.IsNil() {
+			return nil, fmt.Errorf("%w: selector %d doesn't return a pointer", ErrFieldNotFound, i)
+		}
+
+		path, ok := pathOf(t.Elem(), p)
+		if !ok {
+			return nil, fmt.Errorf("%w: selector %d doesn't return a pointer to a field of %s",
+				ErrFieldNotFound, i, t.Type().Elem())
+		}
+
+		paths[i] = path
+	}
+
+	return paths, nil
+}
+
+// allocStructs allocates the nil pointers to structs found in the exported
+// fields of the struct v. Types in allocating are already being allocated
+// and are skipped to stop at recursive types.
+func allocStructs(v reflect.Value, allocating map[reflect.Type]bool) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			allocStructs(fv, allocating)
+		case field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct:
+			elem := field.Type.Elem()
+			if allocating[elem] {
+				continue
+			}
+
+			fv.Set(reflect.New(elem))
+
+			allocating[elem] = true
+			allocStructs(fv.Elem(), allocating)
+			delete(allocating, elem)
+		}
+	}
+}
+
+// pathOf returns the path of the field of the struct v that p points to.
+// The type is compared too, as a struct and its first field have the same
+// address.
+func pathOf(v reflect.Value, p reflect.Value) (string, bool) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Addr().Pointer() == p.Pointer() && fv.Type() == p.Type().Elem() {
+			return field.Name, true
+		}
+
+		if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+
+		if fv.Kind() != reflect.Struct {
+			continue
+		}
+
+		if path, ok := pathOf(fv, p); ok {
+			return field.Name + "." + path, true
+		}
+	}
+
+	return "", false
+}
+
+// ZeroFieldsOf is the same as ZeroFields, but selects the fields with the
+// given selectors. For more info refer to Paths.
+func ZeroFieldsOf[T any](dst *T, selectors ...func(*T) interface{}) error {
+	paths, err := Paths(selectors...)
+	if err != nil {
+		return err
+	}
+
+	return ZeroFields(dst, paths...)
+}
+
+// PickOf is the same as ClonePick, but selects the fields with the given
+// selectors. For more info refer to Paths.
+func PickOf[T any](s T, selectors ...func(*T) interface{}) (T, error) {
+	paths, err := Paths(selectors...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	c, err := ClonePick(s, paths...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return c.(T), nil
+}
+
+// OmitOf is the same as CloneOmit, but selects the fields with the given
+// selectors. For more info refer to Paths.
+func OmitOf[T any](s T, selectors ...func(*T) interface{}) (T, error) {
+	paths, err := Paths(selectors...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	c, err := CloneOmit(s, paths...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return c.(T), nil
+}
@@ -0,0 +1,81 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type selectorSession struct {
+	Token string
+}
+
+type selectorUser struct {
+	Name     string
+	Password string
+	Session  selectorSession
+	Previous *selectorSession
+	Friend   *selectorUser
+}
+
+func TestPaths(t *testing.T) {
+	paths, err := Paths(
+		func(u *selectorUser) interface{} { return &u.Password },
+		func(u *selectorUser) interface{} { return &u.Session.Token },
+		func(u *selectorUser) interface{} { return &u.Previous.Token },
+		func(u *selectorUser) interface{} { return &u.Session },
+		func(u *selectorUser) interface{} { return &u.Friend },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Password", "Session.Token", "Previous.Token", "Session", "Friend"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %q want %q", paths, want)
+	}
+
+	var other string
+	_, err = Paths(func(u *selectorUser) interface{} { return &other })
+	if !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound, got %v", err)
+	}
+
+	_, err = Paths(func(u *selectorUser) interface{} { return u.Name })
+	if !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound for a non pointer, got %v", err)
+	}
+}
+
+func TestSelectorFunctions(t *testing.T) {
+	password := func(u *selectorUser) interface{} { return &u.Password }
+	token := func(u *selectorUser) interface{} { return &u.Session.Token }
+
+	u := selectorUser{Name: "gopher", Password: "hunter2", Session: selectorSession{"t"}}
+
+	omitted, err := OmitOf(u, password, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (selectorUser{Name: "gopher"}); !reflect.DeepEqual(omitted, want) {
+		t.Errorf("OmitOf: got %+v want %+v", omitted, want)
+	}
+
+	picked, err := PickOf(u, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (selectorUser{Session: selectorSession{"t"}}); !reflect.DeepEqual(picked, want) {
+		t.Errorf("PickOf: got %+v want %+v", picked, want)
+	}
+
+	if err := ZeroFieldsOf(&u, password); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Password != "" || u.Name != "gopher" {
+		t.Errorf("ZeroFieldsOf: got %+v", u)
+	}
+}
@@ -0,0 +1,36 @@
+package structs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// Sign returns the HMAC-SHA256 of the canonical binary form of s with the
+// given key, so a struct sent to another service can be verified with Verify
+// or by computing the HMAC of the same form. For more info refer to Struct
+// types CanonicalBytes() method.
+func (s *Struct) Sign(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(s.CanonicalBytes())
+	return mac.Sum(nil)
+}
+
+// Verify returns true if sig is the signature of s returned by Sign with the
+// given key. The signatures are compared in constant time.
+func (s *Struct) Verify(key, sig []byte) bool {
+	return hmac.Equal(s.Sign(key), sig)
+}
+
+// Sign returns the HMAC-SHA256 of the canonical binary form of the given
+// struct. For more info refer to Struct types Sign() method. It panics if s's
+// kind is not struct.
+func Sign(s interface{}, key []byte) []byte {
+	return New(s).Sign(key)
+}
+
+// Verify returns true if sig is the signature of the given struct. For more
+// info refer to Struct types Verify() method. It panics if s's kind is not
+// struct.
+func Verify(s interface{}, key, sig []byte) bool {
+	return New(s).Verify(key, sig)
+}
@@ -0,0 +1,89 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+type signPayment struct {
+	ID     string `structs:"id"`
+	Amount int64  `structs:"amount"`
+	Paid   bool   `structs:"paid"`
+}
+
+func TestCanonicalBytes(t *testing.T) {
+	got := hex.EncodeToString(CanonicalBytes(signPayment{ID: "p1", Amount: 5, Paid: true}))
+
+	// {"id": "p1", "paid": true, "amount": 5}
+	want := "0b" + "0000000000000003" +
+		"06" + "0000000000000002" + hex.EncodeToString([]byte("id")) + "06" + "0000000000000002" + hex.EncodeToString([]byte("p1")) +
+		"06" + "0000000000000004" + hex.EncodeToString([]byte("paid")) + "01" + "01" +
+		"06" + "0000000000000006" + hex.EncodeToString([]byte("amount")) + "02" + "0000000000000005"
+
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestSign(t *testing.T) {
+	key := []byte("key")
+	p := signPayment{ID: "p1", Amount: 5}
+
+	sig := Sign(p, key)
+	if len(sig) != 32 || !Verify(&p, key, sig) {
+		t.Fatalf("got %x", sig)
+	}
+
+	p.Amount = 6
+	if Verify(p, key, sig) {
+		t.Error("verified a changed struct")
+	}
+
+	if Verify(signPayment{ID: "p1", Amount: 5}, []byte("other"), sig) {
+		t.Error("verified with another key")
+	}
+
+	if bytes.Equal(Sign(p, key), sig) {
+		t.Error("got the same signature")
+	}
+}
+
+func TestCanonicalBytes_Raw(t *testing.T) {
+	type Account struct {
+		ID       string `structs:"id"`
+		Password string `structs:"password,sensitive"`
+		Card     string `mask:"card"`
+		Internal string `structs:"-"`
+	}
+
+	a := Account{ID: "a1", Password: "hunter2", Card: "4242424242424242"}
+	want := CanonicalBytes(a)
+
+	b := a
+	b.Password = "hunter3"
+	if bytes.Equal(CanonicalBytes(b), want) {
+		t.Error("changing a sensitive field didn't change the canonical form")
+	}
+
+	b = a
+	b.Internal = "x"
+	if !bytes.Equal(CanonicalBytes(b), want) {
+		t.Error("a field tagged with \"-\" changed the canonical form")
+	}
+
+	s := New(a)
+	s.KeyCase = KeySnakeCase
+	s.KeyPrefix = "app_"
+	s.AllowList = true
+	if !bytes.Equal(s.CanonicalBytes(), want) {
+		t.Error("the options of the Struct changed the canonical form")
+	}
+
+	RegisterMasker("card", func(interface{}) string { return "****" })
+	defer RegisterMasker("card", maskCard)
+
+	if !bytes.Equal(CanonicalBytes(a), want) {
+		t.Error("a masker changed the canonical form")
+	}
+}
@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapSlice converts the given slice of structs (or pointers to structs) to a
+// []map[string]interface{}, where each element is converted with Map. Nil
+// pointer elements are converted to nil maps. Arrays are accepted too. For
+// more info refer to Struct types Map() method. It panics if s is not a slice
+// of structs.
+func MapSlice(s interface{}) []map[string]interface{} {
+	v := sliceVal(s)
+
+	out := make([]map[string]interface{}, v.Len())
+	for i := range out {
+		if elem := v.Index(i); !isNilPtr(elem) {
+			out[i] = Map(elem.Interface())
+		}
+	}
+
+	return out
+}
+
+// ValuesSlice converts the given slice of structs (or pointers to structs) to
+// a [][]interface{}, where each element is converted with Values. Nil pointer
+// elements are converted to nil slices. It panics if s is not a slice of
+// structs.
+func ValuesSlice(s interface{}) [][]interface{} {
+	v := sliceVal(s)
+
+	out := make([][]interface{}, v.Len())
+	for i := range out {
+		if elem := v.Index(i); !isNilPtr(elem) {
+			out[i] = Values(elem.Interface())
+		}
+	}
+
+	return out
+}
+
+// FieldsSlice returns the fields of each element of the given slice of
+// structs (or pointers to structs). Nil pointer elements have no fields. It
+// panics if s is not a slice of structs.
+func FieldsSlice(s interface{}) [][]*Field {
+	v := sliceVal(s)
+
+	out := make([][]*Field, v.Len())
+	for i := range out {
+		if elem := v.Index(i); !isNilPtr(elem) {
+			out[i] = Fields(elem.Interface())
+		}
+	}
+
+	return out
+}
+
+// sliceVal returns the value of s. It panics if s is not a slice or an array
+// of structs or pointers to structs.
+func sliceVal(s interface{}) reflect.Value {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		panic(fmt.Errorf("%w: wrong kind. got: %s want: %s", ErrTypeMismatch, v.Kind(), reflect.Slice))
+	}
+
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		panic(ErrNotStruct)
+	}
+
+	return v
+}
+
+// isNilPtr returns true if v is a nil pointer.
+func isNilPtr(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// MapStructs converts the given map of structs (or pointers to structs) to a
+// map[K]map[string]interface{}, where each value is converted with Map. The
+// keys are kept as is. Nil pointer values are converted to nil maps. For more
+// info refer to Struct types Map() method. It panics if T is not a struct or
+// a pointer to struct.
+func MapStructs[K comparable, T any](m map[K]T) map[K]map[string]interface{} {
+	elem := reflect.TypeOf((*T)(nil)).Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		panic(ErrNotStruct)
+	}
+
+	out := make(map[K]map[string]interface{}, len(m))
+	for k, val := range m {
+		var mv map[string]interface{}
+		if !isNilPtr(reflect.ValueOf(val)) {
+			mv = Map(val)
+		}
+
+		out[k] = mv
+	}
+
+	return out
+}
@@ -0,0 +1,71 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapSlice(t *testing.T) {
+	type A struct {
+		Name string `structs:"name"`
+	}
+
+	m := MapSlice([]*A{{Name: "fatih"}, nil, {Name: "arslan"}})
+
+	want := []map[string]interface{}{
+		{"name": "fatih"},
+		nil,
+		{"name": "arslan"},
+	}
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("MapSlice should return %v, got: %v", want, m)
+	}
+
+	v := ValuesSlice([]A{{Name: "fatih"}})
+	if !reflect.DeepEqual(v, [][]interface{}{{"fatih"}}) {
+		t.Errorf("ValuesSlice should return the values of each element, got: %v", v)
+	}
+
+	f := FieldsSlice([]A{{Name: "fatih"}, {}})
+	if len(f) != 2 || f[0][0].Value() != "fatih" {
+		t.Errorf("FieldsSlice should return the fields of each element, got: %v", f)
+	}
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("MapSlice should panic for a slice of non structs")
+		}
+	}()
+
+	MapSlice([]int{1})
+}
+
+func TestMapStructs(t *testing.T) {
+	type A struct {
+		Name string `structs:"name"`
+	}
+
+	m := MapStructs(map[int]*A{1: {Name: "fatih"}, 2: nil})
+
+	want := map[int]map[string]interface{}{
+		1: {"name": "fatih"},
+		2: nil,
+	}
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("MapStructs should return %v, got: %v", want, m)
+	}
+
+	if v := MapStructs(map[string]A{"a": {Name: "arslan"}}); v["a"]["name"] != "arslan" {
+		t.Errorf("MapStructs should convert struct values, got: %v", v)
+	}
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("MapStructs should panic for a map of non structs")
+		}
+	}()
+
+	MapStructs(map[string]int{})
+}
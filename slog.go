@@ -0,0 +1,91 @@
+package structs
+
+import (
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	// LogTagName is the tag name which marks the fields allowed in the output
+	// with the AllowList option, such as `log:"true"`.
+	LogTagName = "log"
+)
+
+// LogValue implements slog.LogValuer, so s can be passed to a slog.Logger
+// directly. The struct is rendered as a group of the key/value pairs of Map,
+// with nested structs as nested groups. Sensitive fields are masked as in
+// Map, so logging s is safe by construction.
+func (s *Struct) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for key, val := range s.All() {
+		attrs = append(attrs, logAttr(key, val))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// allowed returns true if the given field may appear in the output of Map,
+// which is always the case unless the AllowList option is set or the field
+// is left out of an ETag.
+func (s *Struct) allowed(field reflect.StructField) bool {
+	if s.state != nil && s.state.skipVolatile && field.Tag.Get(ETagTagName) == "-" {
+		s.trace(field.Name, TraceSkip, "volatile")
+		return false
+	}
+
+	if !s.AllowList {
+		return true
+	}
+
+	ok, _ := strconv.ParseBool(field.Tag.Get(LogTagName))
+	if !ok {
+		s.trace(field.Name, TraceSkip, "not allowed")
+	}
+
+	return ok
+}
+
+// logAttr returns the attribute for the given key and value of the output of
+// Map. Nested maps are converted to groups with sorted keys.
+func logAttr(key string, val interface{}) slog.Attr {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return slog.Any(key, val)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = logAttr(k, m[k])
+	}
+
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// LogValue returns a slog.LogValuer for the given struct, which renders it
+// with its sensitive fields masked:
+//
+//   logger.Info("login", "user", structs.LogValue(u))
+//
+// For more info refer to Struct types LogValue() method. It panics if s's
+// kind is not struct.
+func LogValue(s interface{}) slog.LogValuer {
+	return New(s)
+}
+
+// LogValueAllowList is the same as LogValue, but only includes the fields
+// tagged with `log:"true"`. For more info refer to the AllowList option of
+// Struct. It panics if s's kind is not struct.
+func LogValueAllowList(s interface{}) slog.LogValuer {
+	st := New(s)
+	st.AllowList = true
+	return st
+}
@@ -0,0 +1,115 @@
+package structs
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type slogAddress struct {
+	City string
+	Zip  string
+}
+
+type slogUser struct {
+	Name     string
+	Password string `structs:"password,sensitive"`
+	Address  slogAddress
+}
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	u := slogUser{
+		Name:     "gopher",
+		Password: "hunter2",
+		Address:  slogAddress{"Berlin", "10115"},
+	}
+
+	logger.Info("login", "user", LogValue(u))
+
+	want := `level=INFO msg=login user.Name=gopher user.password=*** user.Address.City=Berlin user.Address.Zip=10115`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+type slogRequest struct {
+	ID      string `log:"true"`
+	Body    string
+	Client  slogClient `log:"true"`
+	Ignored string     `log:"false"`
+}
+
+type slogClient struct {
+	IP    string `log:"true"`
+	Token string
+}
+
+func TestLogValueAllowList(t *testing.T) {
+	r := slogRequest{
+		ID:      "42",
+		Body:    "secret",
+		Client:  slogClient{"127.0.0.1", "token"},
+		Ignored: "x",
+	}
+
+	v := LogValueAllowList(r).LogValue()
+
+	var got []string
+	for _, a := range v.Group() {
+		got = append(got, a.String())
+	}
+
+	want := []string{"ID=42", "Client=[IP=127.0.0.1]"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	s := New(r)
+	s.AllowList = true
+
+	if m := s.MapString(); len(m) != 2 || m["ID"] != "42" {
+		t.Errorf("MapString: got %#v", m)
+	}
+}
+
+func TestLogValueAllowList_NestedWithoutAllowed(t *testing.T) {
+	type Credentials struct {
+		User     string
+		Password string
+	}
+
+	type Request struct {
+		ID    string      `log:"true"`
+		Creds Credentials `log:"true"`
+	}
+
+	r := Request{ID: "42", Creds: Credentials{"gopher", "hunter2"}}
+
+	s := New(r)
+	s.AllowList = true
+
+	m := s.Map()
+	if creds, ok := m["Creds"].(map[string]interface{}); !ok || len(creds) != 0 {
+		t.Errorf("Map: got %#v, want an empty map for Creds", m["Creds"])
+	}
+
+	var got []string
+	for _, a := range LogValueAllowList(r).LogValue().Group() {
+		got = append(got, a.String())
+	}
+
+	if strings.Contains(strings.Join(got, " "), "hunter2") {
+		t.Errorf("LogValue leaked the password: %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// State is the opaque state of a struct captured by Snapshot.
+type State struct {
+	value reflect.Value
+}
+
+// Snapshot captures the values of all fields of s, including the ones tagged
+// with "-", so s can be rolled back with Restore, i.e. for transactional
+// edits or in test setups. Values are deep copied as described in Clone,
+// unexported fields are captured as is.
+func (s *Struct) Snapshot() *State {
+	return &State{value: snapshotValue(s.value)}
+}
+
+// Restore sets all fields of s back to the values captured by Snapshot. The
+// same state can be restored multiple times. It returns an error wrapping
+// ErrTypeMismatch if the state was captured from a struct of another type
+// and an error if s was not created from a pointer.
+func (s *Struct) Restore(snap *State) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	if snap.value.Type() != s.value.Type() {
+		return fmt.Errorf("%w: can't restore %s into %s", ErrTypeMismatch, snap.value.Type(), s.value.Type())
+	}
+
+	s.value.Set(snapshotValue(snap.value))
+	return nil
+}
+
+// snapshotValue returns a deep copy of v including all of its fields.
+func snapshotValue(v reflect.Value) reflect.Value {
+	c := &cloner{seen: make(map[visit]reflect.Value)}
+	return c.clone(v)
+}
+
+// Snapshot captures the values of all fields of the given struct. For more
+// info refer to Struct types Snapshot() method. It panics if s's kind is not
+// struct.
+func Snapshot(s interface{}) *State {
+	return New(s).Snapshot()
+}
+
+// Restore sets all fields of the struct pointed to by dst back to the values
+// captured by Snapshot. For more info refer to Struct types Restore()
+// method. It returns an error if dst is not a pointer to struct.
+func Restore(dst interface{}, snap *State) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Restore(snap)
+}
@@ -0,0 +1,56 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type snapshotConfig struct {
+	Name   string
+	Hosts  []string
+	Limits map[string]int
+	Token  string `structs:"-"`
+}
+
+func TestSnapshot(t *testing.T) {
+	c := snapshotConfig{
+		Name:   "a",
+		Hosts:  []string{"x"},
+		Limits: map[string]int{"cpu": 1},
+		Token:  "secret",
+	}
+
+	snap := Snapshot(c)
+
+	for i := 0; i < 2; i++ {
+		c.Name = "b"
+		c.Hosts[0] = "y"
+		c.Limits["cpu"] = 2
+		c.Token = ""
+
+		if err := Restore(&c, snap); err != nil {
+			t.Fatal(err)
+		}
+
+		want := snapshotConfig{
+			Name:   "a",
+			Hosts:  []string{"x"},
+			Limits: map[string]int{"cpu": 1},
+			Token:  "secret",
+		}
+
+		if !reflect.DeepEqual(c, want) {
+			t.Fatalf("%d: got %+v want %+v", i, c, want)
+		}
+	}
+
+	var other struct{ Name string }
+	if err := Restore(&other, snap); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch, got %v", err)
+	}
+
+	if err := Restore(c, snap); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
@@ -0,0 +1,530 @@
+package structs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// SQLTagName is the tag name used by the SQL helpers to map struct fields
+	// to table columns. If a field has no such tag, the field name is used.
+	SQLTagName = "db"
+
+	errNotStructPtr = fmt.Errorf("%w: destination must be a non-nil pointer to struct", ErrNotStruct)
+)
+
+// Placeholder is the style of the bind parameters generated by the SQL
+// helpers. The zero value is Question.
+type Placeholder int
+
+const (
+	// Question uses "?" for all parameters, as used by MySQL and SQLite.
+	Question Placeholder = iota
+
+	// Dollar uses numbered parameters such as "$1", as used by PostgreSQL.
+	Dollar
+
+	// Colon uses named parameters such as ":name". The arguments are
+	// returned as sql.NamedArg values.
+	Colon
+
+	// AtP uses numbered parameters such as "@p1", as used by SQL Server.
+	AtP
+)
+
+// Format returns the placeholder for the n'th (1-based) parameter, which is
+// bound to the given column.
+func (p Placeholder) Format(n int, column string) string {
+	switch p {
+	case Dollar:
+		return "$" + strconv.Itoa(n)
+	case Colon:
+		return ":" + column
+	case AtP:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// arg returns the argument value for the given column as expected by the
+// placeholder style.
+func (p Placeholder) arg(column string, v interface{}) interface{} {
+	if p == Colon {
+		return sql.Named(column, v)
+	}
+
+	return v
+}
+
+// Rows is the interface implemented by *sql.Rows. It's used by Scan to map
+// the result columns to struct fields.
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+}
+
+// Row is the interface implemented by *sql.Row and *sql.Rows.
+type Row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scan copies the columns of the current row of rows into the struct pointed
+// to by s. Columns are matched with the field's "db" tag name or, if not
+// tagged, with the field name. If no exact match is found, the column is
+// matched case-insensitively. Columns without a matching field are discarded.
+// A tag value with the content of "-" ignores that particular field. Example:
+//
+//   // Field is filled from the "user_name" column.
+//   Name string `db:"user_name"`
+//
+//   // Field is never filled.
+//   Field string `db:"-"`
+//
+// Exported embedded structs without a tag are flattened, so their fields are
+// matched as if they were declared in the outer struct.
+func (s *Struct) Scan(rows Rows) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := sqlFields(s.value, true)
+
+	dest := make([]interface{}, len(columns))
+	for i, column := range columns {
+		f, ok := matchColumn(fields, column)
+		if !ok {
+			// column has no destination, scan it into a throw away value
+			dest[i] = new(interface{})
+			continue
+		}
+
+		dest[i] = f.value.Addr().Interface()
+	}
+
+	return rows.Scan(dest...)
+}
+
+// ScanRow copies the columns of row into the struct pointed to by s. As
+// *sql.Row doesn't expose the column names, the columns are assigned to the
+// fields in the order they are declared, therefore the query must select the
+// columns in the same order. Fields are selected as described in Scan.
+func (s *Struct) ScanRow(row Row) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	fields := sqlFields(s.value, true)
+
+	dest := make([]interface{}, len(fields))
+	for i, f := range fields {
+		dest[i] = f.value.Addr().Interface()
+	}
+
+	return row.Scan(dest...)
+}
+
+// UpdateSet returns a "SET col = ?, ..." fragment for the non-zero fields
+// of s and the arguments in the same order as the placeholders. The
+// placeholders are generated in the given style, i.e. Dollar produces
+// "SET col = $1, ...". Columns are named as described in Scan. It returns an
+// empty string and nil arguments if all fields are zero.
+func (s *Struct) UpdateSet(p Placeholder) (string, []interface{}) {
+	var fields []sqlField
+	for _, f := range sqlFields(s.value, false) {
+		if isZeroValue(f.value) {
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	return updateSet(fields, p)
+}
+
+// UpdateSetChanged returns a "SET col = ?, ..." fragment for the fields of s
+// whose values differ from the ones in prev, and the arguments in the same
+// order as the placeholders. prev must be of the same type as s. Unlike
+// UpdateSet, fields that were reset to their zero value are included, which
+// makes it the preferred way to build partial updates. The placeholders are
+// generated in the given style.
+func (s *Struct) UpdateSetChanged(prev interface{}, p Placeholder) (string, []interface{}) {
+	pv := strctVal(prev)
+	if pv.Type() != s.value.Type() {
+		panic(fmt.Errorf("%w: got: %s want: %s", ErrTypeMismatch, pv.Type(), s.value.Type()))
+	}
+
+	old := sqlFields(pv, false)
+
+	var fields []sqlField
+	for i, f := range sqlFields(s.value, false) {
+		if reflect.DeepEqual(f.value.Interface(), old[i].value.Interface()) {
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	return updateSet(fields, p)
+}
+
+// updateSet builds the SET fragment and its arguments for the given fields.
+func updateSet(fields []sqlField, p Placeholder) (string, []interface{}) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	sets := make([]string, len(fields))
+	args := make([]interface{}, len(fields))
+
+	for i, f := range fields {
+		sets[i] = f.column + " = " + p.Format(i+1, f.column)
+		args[i] = p.arg(f.column, f.value.Interface())
+	}
+
+	return "SET " + strings.Join(sets, ", "), args
+}
+
+// isZeroValue returns true if v is the zero value of its type.
+func isZeroValue(v reflect.Value) bool {
+	zero := reflect.Zero(v.Type()).Interface()
+	return reflect.DeepEqual(v.Interface(), zero)
+}
+
+// InsertBatch is a multi-row insert statement fragment created by
+// InsertValues.
+type InsertBatch struct {
+	// Columns contains the column names in the same order as the values of
+	// each row.
+	Columns []string
+
+	// Values is the "VALUES (?, ?), (?, ?)" fragment of the batch.
+	Values string
+
+	// Args contains the flattened arguments for the placeholders in Values.
+	Args []interface{}
+}
+
+// InsertValues returns the "VALUES (...), (...)" fragments and the flattened
+// arguments for the given slice of structs (or pointers to structs). Columns
+// are named as described in Scan. The rows are split into several batches so
+// no batch has more than maxParams arguments, which is needed as databases
+// limit the number of parameters of a single statement. A maxParams of zero
+// or less puts all rows into a single batch. Numbered placeholders start at
+// 1 for each batch. As Colon parameters need to be unique, they're suffixed
+// with the row number within the batch, i.e. ":name_1", ":name_2". It returns
+// an error if slice is not a slice of structs, if a row is a nil pointer or
+// if a single row doesn't fit into maxParams.
+func InsertValues(slice interface{}, p Placeholder, maxParams int) ([]InsertBatch, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w: wrong kind. got: %s want: %s", ErrTypeMismatch, v.Kind(), reflect.Slice)
+	}
+
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: wrong slice element kind. got: %s want: %s", ErrNotStruct, elem.Kind(), reflect.Struct)
+	}
+
+	if v.Len() == 0 {
+		return nil, nil
+	}
+
+	var columns []string
+	for _, f := range sqlFields(reflect.New(elem).Elem(), false) {
+		columns = append(columns, f.column)
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("struct has no columns")
+	}
+
+	perBatch := v.Len()
+	if maxParams > 0 {
+		perBatch = maxParams / len(columns)
+		if perBatch == 0 {
+			return nil, fmt.Errorf("row with %d columns exceeds the limit of %d parameters", len(columns), maxParams)
+		}
+	}
+
+	var batches []InsertBatch
+
+	for start := 0; start < v.Len(); start += perBatch {
+		end := start + perBatch
+		if end > v.Len() {
+			end = v.Len()
+		}
+
+		rows := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(columns))
+
+		for i := start; i < end; i++ {
+			row := v.Index(i)
+			if row.Kind() == reflect.Ptr && row.IsNil() {
+				return nil, fmt.Errorf("%w: row %d", ErrNilPointer, i)
+			}
+
+			fields := sqlFields(strctVal(row.Interface()), false)
+
+			binds := make([]string, len(fields))
+			for j, f := range fields {
+				name := f.column
+				if p == Colon {
+					name += "_" + strconv.Itoa(i-start+1)
+				}
+
+				binds[j] = p.Format(len(args)+1, name)
+				args = append(args, p.arg(name, f.value.Interface()))
+			}
+
+			rows = append(rows, "("+strings.Join(binds, ", ")+")")
+		}
+
+		batches = append(batches, InsertBatch{
+			Columns: columns,
+			Values:  "VALUES " + strings.Join(rows, ", "),
+			Args:    args,
+		})
+	}
+
+	return batches, nil
+}
+
+// sqlField is a struct field that maps to a table column.
+type sqlField struct {
+	column string
+	value  reflect.Value
+	field  reflect.StructField
+}
+
+// sqlFields returns the fields of v mapped to their column names. Embedded
+// structs and pointers to structs without a tag are flattened into the
+// result. Nil embedded pointers are allocated if alloc is true, otherwise
+// they're handled like a zero value struct, so the columns are the same for
+// all values of a type.
+func sqlFields(v reflect.Value, alloc bool) []sqlField {
+	t := v.Type()
+
+	var fields []sqlField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get(SQLTagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, _ := parseTag(tag)
+
+		if field.Anonymous && name == "" {
+			switch {
+			case field.Type.Kind() == reflect.Struct:
+				fields = append(fields, sqlFields(v.Field(i), alloc)...)
+				continue
+			case field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct:
+				embedded := v.Field(i)
+				if embedded.IsNil() {
+					if !alloc || !embedded.CanSet() {
+						fields = append(fields, sqlFields(reflect.New(field.Type.Elem()).Elem(), false)...)
+						continue
+					}
+
+					embedded.Set(reflect.New(field.Type.Elem()))
+				}
+
+				fields = append(fields, sqlFields(embedded.Elem(), alloc)...)
+				continue
+			}
+		}
+
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, sqlField{
+			column: name,
+			value:  v.Field(i),
+			field:  field,
+		})
+	}
+
+	return fields
+}
+
+// matchColumn returns the field for the given column. An exact match has
+// precedence over a case-insensitive match.
+func matchColumn(fields []sqlField, column string) (sqlField, bool) {
+	for _, f := range fields {
+		if f.column == column {
+			return f, true
+		}
+	}
+
+	for _, f := range fields {
+		if strings.EqualFold(f.column, column) {
+			return f, true
+		}
+	}
+
+	return sqlField{}, false
+}
+
+// sqlNull returns the inner value of v if v is one of the database/sql Null
+// types, such as sql.NullString or sql.Null[T]. The returned value is nil if
+// v is not valid. The boolean is false if v is not a Null type.
+func sqlNull(v reflect.Value) (interface{}, bool) {
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	t := v.Type()
+	if t.PkgPath() != "database/sql" || !strings.HasPrefix(t.Name(), "Null") {
+		return nil, false
+	}
+
+	valid := v.FieldByName("Valid")
+	if !valid.IsValid() || valid.Kind() != reflect.Bool || t.NumField() != 2 {
+		return nil, false
+	}
+
+	if !valid.Bool() {
+		return nil, true
+	}
+
+	return v.Field(0).Interface(), true
+}
+
+// sqlValue returns the database value of v. Null types and registered
+// wrappers are unwrapped as described in unwrap, other types implementing
+// driver.Valuer are converted with their Value() method. If Value() fails, v
+// is returned as is. The boolean is false if v is neither of them.
+func sqlValue(v reflect.Value) (interface{}, bool) {
+	if null, ok := unwrap(v); ok {
+		return null, true
+	}
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, false
+	}
+
+	valuer, ok := v.Interface().(driver.Valuer)
+	if !ok {
+		return nil, false
+	}
+
+	dv, err := valuer.Value()
+	if err != nil {
+		return v.Interface(), true
+	}
+
+	return dv, true
+}
+
+// structPtr returns a *Struct for the given destination. It returns an error
+// if dst is not a non-nil pointer to struct.
+func structPtr(dst interface{}) (*Struct, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errNotStructPtr
+	}
+
+	return New(dst), nil
+}
+
+// Scan copies the columns of the current row of rows into the struct pointed
+// to by dst. For more info refer to Struct types Scan() method. It returns an
+// error if dst is not a pointer to struct.
+func Scan(rows Rows, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Scan(rows)
+}
+
+// ScanRow copies the columns of row into the struct pointed to by dst. For
+// more info refer to Struct types ScanRow() method. It returns an error if dst
+// is not a pointer to struct.
+func ScanRow(row Row, dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.ScanRow(row)
+}
+
+// ScanAll iterates over rows and appends a new element to the slice pointed
+// to by dst for each row. The slice element must be a struct or a pointer to
+// struct. The caller is still responsible for checking rows.Err() and closing
+// rows.
+func ScanAll(rows Rows, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: destination must be a non-nil pointer to slice", ErrTypeMismatch)
+	}
+
+	slice := v.Elem()
+	elem := slice.Type().Elem()
+
+	isPtr := elem.Kind() == reflect.Ptr
+	if isPtr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: wrong slice element kind. got: %s want: %s", ErrNotStruct, elem.Kind(), reflect.Struct)
+	}
+
+	for rows.Next() {
+		item := reflect.New(elem)
+		if err := New(item.Interface()).Scan(rows); err != nil {
+			return err
+		}
+
+		if !isPtr {
+			item = item.Elem()
+		}
+
+		slice.Set(reflect.Append(slice, item))
+	}
+
+	return nil
+}
+
+// UpdateSet returns a "SET col = ?, ..." fragment and its arguments for the
+// non-zero fields of s, using the placeholder style p. For more info refer to
+// Struct types UpdateSet() method. It panics if s's kind is not struct.
+func UpdateSet(s interface{}, p Placeholder) (string, []interface{}) {
+	return New(s).UpdateSet(p)
+}
+
+// UpdateSetChanged returns a "SET col = ?, ..." fragment and its arguments for
+// the fields of s that differ from prev, using the placeholder style p. For
+// more info refer to Struct types UpdateSetChanged() method. It panics if s's
+// or prev's kind is not struct.
+func UpdateSetChanged(prev, s interface{}, p Placeholder) (string, []interface{}) {
+	return New(s).UpdateSetChanged(prev, p)
+}
@@ -0,0 +1,401 @@
+package structs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeRows implements the Rows interface on top of static data.
+type fakeRows struct {
+	columns []string
+	data    [][]interface{}
+	pos     int
+}
+
+func (r *fakeRows) Columns() ([]string, error) { return r.columns, nil }
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.data)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.data[r.pos-1]
+	if len(dest) != len(row) {
+		return errors.New("wrong number of destinations")
+	}
+
+	for i, d := range dest {
+		v := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			v.Set(reflect.Zero(v.Type()))
+			continue
+		}
+
+		v.Set(reflect.ValueOf(row[i]).Convert(v.Type()))
+	}
+
+	return nil
+}
+
+type sqlUser struct {
+	ID      int64
+	Name    string `db:"user_name"`
+	Ignored string `db:"-"`
+	secret  string
+	sqlAudit
+}
+
+type sqlAudit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+func TestScan(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"id", "user_name", "created_by", "unknown"},
+		data:    [][]interface{}{{int64(1), "fatih", "admin", "discarded"}},
+	}
+
+	var u sqlUser
+	u.Ignored = "keep"
+
+	if !rows.Next() {
+		t.Fatal("rows should have a row")
+	}
+
+	if err := Scan(rows, &u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.ID != 1 {
+		t.Errorf("ID should be matched case-insensitively, got: %d", u.ID)
+	}
+
+	if u.Name != "fatih" {
+		t.Errorf("Name should be filled by tag, got: %q", u.Name)
+	}
+
+	if u.CreatedBy != "admin" {
+		t.Errorf("CreatedBy of embedded struct should be filled, got: %q", u.CreatedBy)
+	}
+
+	if u.Ignored != "keep" {
+		t.Errorf("Ignored field should not be changed, got: %q", u.Ignored)
+	}
+}
+
+func TestScan_NonPointer(t *testing.T) {
+	rows := &fakeRows{columns: []string{"id"}}
+
+	if err := Scan(rows, sqlUser{}); err == nil {
+		t.Error("Scan should return an error for a non pointer destination")
+	}
+}
+
+func TestScanRow(t *testing.T) {
+	row := &fakeRows{
+		data: [][]interface{}{{int64(2), "arslan", "root"}},
+		pos:  1,
+	}
+
+	var u sqlUser
+	if err := ScanRow(row, &u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.ID != 2 || u.Name != "arslan" || u.CreatedBy != "root" {
+		t.Errorf("ScanRow should fill the fields in order, got: %+v", u)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"ID", "user_name"},
+		data: [][]interface{}{
+			{int64(1), "fatih"},
+			{int64(2), "arslan"},
+		},
+	}
+
+	var users []*sqlUser
+	if err := ScanAll(rows, &users); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("ScanAll should return 2 elements, got: %d", len(users))
+	}
+
+	if users[1].ID != 2 || users[1].Name != "arslan" {
+		t.Errorf("ScanAll should fill the second element, got: %+v", users[1])
+	}
+
+	var ints []int
+	if err := ScanAll(&fakeRows{}, &ints); err == nil {
+		t.Error("ScanAll should return an error for a non struct element")
+	}
+}
+
+func TestUpdateSet(t *testing.T) {
+	u := sqlUser{
+		Name:    "fatih",
+		Ignored: "ignored",
+	}
+
+	set, args := UpdateSet(u, Question)
+	if set != "SET user_name = ?" {
+		t.Errorf("UpdateSet should only contain non-zero fields, got: %q", set)
+	}
+
+	if !reflect.DeepEqual(args, []interface{}{"fatih"}) {
+		t.Errorf("UpdateSet args are wrong, got: %v", args)
+	}
+
+	set, args = UpdateSet(sqlUser{}, Question)
+	if set != "" || args != nil {
+		t.Errorf("UpdateSet of a zero struct should be empty, got: %q %v", set, args)
+	}
+}
+
+func TestUpdateSetChanged(t *testing.T) {
+	prev := sqlUser{ID: 1, Name: "fatih"}
+	prev.CreatedBy = "admin"
+
+	next := prev
+	next.Name = ""
+	next.CreatedBy = "root"
+
+	set, args := UpdateSetChanged(prev, &next, Question)
+	if set != "SET user_name = ?, created_by = ?" {
+		t.Errorf("UpdateSetChanged should contain changed fields, got: %q", set)
+	}
+
+	if !reflect.DeepEqual(args, []interface{}{"", "root"}) {
+		t.Errorf("UpdateSetChanged args are wrong, got: %v", args)
+	}
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("UpdateSetChanged with different types should panic")
+		}
+	}()
+
+	UpdateSetChanged(sqlAudit{}, next, Question)
+}
+
+func TestPlaceholder(t *testing.T) {
+	u := sqlUser{ID: 1, Name: "fatih"}
+
+	tests := []struct {
+		p    Placeholder
+		set  string
+		args []interface{}
+	}{
+		{Question, "SET ID = ?, user_name = ?", []interface{}{int64(1), "fatih"}},
+		{Dollar, "SET ID = $1, user_name = $2", []interface{}{int64(1), "fatih"}},
+		{AtP, "SET ID = @p1, user_name = @p2", []interface{}{int64(1), "fatih"}},
+		{Colon, "SET ID = :ID, user_name = :user_name", []interface{}{
+			sql.Named("ID", int64(1)),
+			sql.Named("user_name", "fatih"),
+		}},
+	}
+
+	for _, test := range tests {
+		set, args := UpdateSet(u, test.p)
+		if set != test.set {
+			t.Errorf("UpdateSet(%d) should be %q, got: %q", test.p, test.set, set)
+		}
+
+		if !reflect.DeepEqual(args, test.args) {
+			t.Errorf("UpdateSet(%d) args should be %v, got: %v", test.p, test.args, args)
+		}
+	}
+}
+
+func TestInsertValues(t *testing.T) {
+	users := []*sqlUser{
+		{ID: 1, Name: "fatih"},
+		{ID: 2, Name: "arslan"},
+		{ID: 3, Name: "gopher"},
+	}
+
+	batches, err := InsertValues(users, Dollar, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 2 {
+		t.Fatalf("InsertValues should return 2 batches, got: %d", len(batches))
+	}
+
+	if !reflect.DeepEqual(batches[0].Columns, []string{"ID", "user_name", "created_by"}) {
+		t.Errorf("InsertValues columns are wrong, got: %v", batches[0].Columns)
+	}
+
+	if batches[0].Values != "VALUES ($1, $2, $3), ($4, $5, $6)" {
+		t.Errorf("InsertValues first batch is wrong, got: %q", batches[0].Values)
+	}
+
+	if batches[1].Values != "VALUES ($1, $2, $3)" {
+		t.Errorf("InsertValues second batch is wrong, got: %q", batches[1].Values)
+	}
+
+	want := []interface{}{int64(3), "gopher", ""}
+	if !reflect.DeepEqual(batches[1].Args, want) {
+		t.Errorf("InsertValues args should be %v, got: %v", want, batches[1].Args)
+	}
+
+	batches, err = InsertValues(users[:2], Colon, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 1 {
+		t.Fatalf("InsertValues without a limit should return 1 batch, got: %d", len(batches))
+	}
+
+	if batches[0].Values != "VALUES (:ID_1, :user_name_1, :created_by_1), (:ID_2, :user_name_2, :created_by_2)" {
+		t.Errorf("InsertValues named batch is wrong, got: %q", batches[0].Values)
+	}
+
+	if _, err := InsertValues(users, Question, 2); err == nil {
+		t.Error("InsertValues should return an error if a row exceeds the limit")
+	}
+
+	if _, err := InsertValues([]int{1}, Question, 0); err == nil {
+		t.Error("InsertValues should return an error for non struct elements")
+	}
+
+	_, err = InsertValues([]*sqlUser{users[0], nil}, Question, 0)
+	if !errors.Is(err, ErrNilPointer) || err.Error() != "nil pointer: row 1" {
+		t.Errorf("InsertValues should return an error for nil rows, got: %v", err)
+	}
+}
+
+func TestSQLNull(t *testing.T) {
+	type Row struct {
+		Name  sql.NullString
+		Age   sql.NullInt64
+		Score sql.Null[float64]
+		Tags  []sql.NullString
+	}
+
+	r := Row{
+		Name:  sql.NullString{String: "fatih", Valid: true},
+		Age:   sql.NullInt64{Int64: 30, Valid: false},
+		Score: sql.Null[float64]{V: 1.5, Valid: true},
+		Tags:  []sql.NullString{{String: "a", Valid: true}, {}},
+	}
+
+	m := Map(r)
+
+	if m["Name"] != "fatih" {
+		t.Errorf("Map should contain the inner value of a valid Null type, got: %#v", m["Name"])
+	}
+
+	if m["Age"] != nil {
+		t.Errorf("Map should contain nil for an invalid Null type, got: %#v", m["Age"])
+	}
+
+	if m["Score"] != 1.5 {
+		t.Errorf("Map should support the generic Null type, got: %#v", m["Score"])
+	}
+
+	if !reflect.DeepEqual(m["Tags"], []interface{}{"a", nil}) {
+		t.Errorf("Map should unwrap Null types in slices, got: %#v", m["Tags"])
+	}
+
+	v := Values(r)
+	if !reflect.DeepEqual(v[:3], []interface{}{"fatih", nil, 1.5}) {
+		t.Errorf("Values should unwrap Null types, got: %#v", v)
+	}
+
+	if !HasZero(r) {
+		t.Error("HasZero should be true for an invalid Null type")
+	}
+
+	invalid := struct {
+		Name sql.NullString
+	}{
+		Name: sql.NullString{String: "stale", Valid: false},
+	}
+
+	if !IsZero(invalid) {
+		t.Error("IsZero should be true if all Null types are invalid")
+	}
+}
+
+// sqlMoney implements driver.Valuer and stores cents.
+type sqlMoney struct {
+	Cents int64
+}
+
+func (m sqlMoney) Value() (driver.Value, error) {
+	return fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100), nil
+}
+
+func TestSQLValuer(t *testing.T) {
+	type Order struct {
+		Price  sqlMoney
+		Extra  *sqlMoney
+		Prices []sqlMoney
+	}
+
+	o := Order{
+		Price:  sqlMoney{Cents: 1050},
+		Prices: []sqlMoney{{Cents: 1}},
+	}
+
+	m := Map(o)
+
+	if m["Price"] != "10.50" {
+		t.Errorf("Map should use the Value() of a driver.Valuer, got: %#v", m["Price"])
+	}
+
+	if m["Extra"] != (*sqlMoney)(nil) {
+		t.Errorf("Map should keep nil driver.Valuer pointers, got: %#v", m["Extra"])
+	}
+
+	if !reflect.DeepEqual(m["Prices"], []interface{}{"0.01"}) {
+		t.Errorf("Map should use the Value() of driver.Valuer slice elements, got: %#v", m["Prices"])
+	}
+}
+
+func TestScan_EmbeddedPointer(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `db:"created_by"`
+	}
+
+	type Row struct {
+		*Audit
+		ID int64
+	}
+
+	rows := &fakeRows{
+		columns: []string{"id", "created_by"},
+		data:    [][]interface{}{{int64(1), "admin"}},
+		pos:     1,
+	}
+
+	var r Row
+	if err := Scan(rows, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Audit == nil || r.CreatedBy != "admin" {
+		t.Errorf("Scan should allocate nil embedded pointers, got: %+v", r)
+	}
+
+	batches, err := InsertValues([]Row{{ID: 1}, r}, Question, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if batches[0].Values != "VALUES (?, ?), (?, ?)" {
+		t.Errorf("InsertValues should handle nil embedded pointers as zero values, got: %q", batches[0].Values)
+	}
+}
@@ -0,0 +1,183 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// KindPolicy defines how fields of kinds that don't carry data, such as a
+// chan, a func or an unsafe.Pointer, are handled. Slices, arrays, maps and
+// pointers of these kinds are handled the same way.
+type KindPolicy int
+
+const (
+	// KindKeep passes the field's value through as is. This is the default.
+	KindKeep KindPolicy = iota
+
+	// KindSkip leaves the field out.
+	KindSkip
+
+	// KindNil replaces the field's value with nil.
+	KindNil
+
+	// KindError panics with an error wrapping ErrUnsupportedKind. MapStrict
+	// returns the error instead.
+	KindError
+)
+
+// unsupported applies the OnUnsupportedKind policy of s to the field with
+// the given name and value. It returns the value to use, which is skipped{}
+// if the field must be left out. The boolean is false if the field's value
+// is of a supported kind or the policy is KindKeep.
+func (s *Struct) unsupported(name string, val reflect.Value) (interface{}, bool) {
+	if s.OnUnsupportedKind == KindKeep {
+		return nil, false
+	}
+
+	// use the dynamic type of interface fields
+	t := val.Type()
+	if val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil, false
+		}
+
+		t = val.Elem().Type()
+	}
+
+	kind, ok := unsupportedKind(t)
+	if !ok {
+		return nil, false
+	}
+
+	switch s.OnUnsupportedKind {
+	case KindSkip:
+		return skipped{}, true
+	case KindError:
+		panic(fmt.Errorf("%w %s at %s", ErrUnsupportedKind, kind, name))
+	}
+
+	return nil, true
+}
+
+// MapStrict is the same as Map, but returns an error if the output contains
+// a value that can't be meaningfully converted, such as a chan, a func or an
+// unsafe.Pointer. These values are passed through by Map, which later breaks
+// encoders such as encoding/json. Values are checked by their type, so nil
+// funcs or an empty []chan int are reported too. Fields tagged with "-" are
+// not checked. With the CycleError, KindError and DuplicateError policies,
+// the errors are returned instead of panicking.
+func (s *Struct) MapStrict() (m map[string]interface{}, err error) {
+	defer recoverPolicy(&err)
+
+	m = s.Map()
+
+	for k, v := range m {
+		if err := checkKinds(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// ValuesStrict is the same as Values, but returns an error if the output
+// contains a value that can't be meaningfully converted. For more info refer
+// to Struct types MapStrict() method.
+func (s *Struct) ValuesStrict() (values []interface{}, err error) {
+	defer recoverPolicy(&err)
+
+	values = s.Values()
+
+	for i, v := range values {
+		if err := checkKinds(strconv.Itoa(i), v); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// recoverPolicy recovers from the panics of the CycleError, KindError and
+// DuplicateError policies and sets err to their error. It must be deferred
+// by the strict functions, whose other results are left as nil. Other
+// panics are passed on.
+func recoverPolicy(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	cerr, ok := r.(error)
+	if !ok || (!errors.Is(cerr, ErrCycle) && !errors.Is(cerr, ErrUnsupportedKind) &&
+		!errors.Is(cerr, ErrDuplicateKey)) {
+		panic(r)
+	}
+
+	*err = cerr
+}
+
+// checkKinds returns an error if v or any of the values nested in v has an
+// unsupported kind. The path is used to describe the location of v.
+func checkKinds(path string, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		for k, elem := range v {
+			if err := checkKinds(path+"."+k, elem); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case []interface{}:
+		for i, elem := range v {
+			if err := checkKinds(path+"["+strconv.Itoa(i)+"]", elem); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if kind, ok := unsupportedKind(reflect.TypeOf(v)); ok {
+		return fmt.Errorf("%w %s at %s", ErrUnsupportedKind, kind, path)
+	}
+
+	return nil
+}
+
+// unsupportedKind returns the unsupported kind found in t or the element
+// types of t. Struct fields are not examined.
+func unsupportedKind(t reflect.Type) (reflect.Kind, bool) {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return t.Kind(), true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return unsupportedKind(t.Elem())
+	case reflect.Map:
+		if kind, ok := unsupportedKind(t.Key()); ok {
+			return kind, true
+		}
+
+		return unsupportedKind(t.Elem())
+	}
+
+	return reflect.Invalid, false
+}
+
+// MapStrict converts the given struct to a map[string]interface{} and returns
+// an error for unsupported kinds. For more info refer to Struct types
+// MapStrict() method. It panics if s's kind is not struct.
+func MapStrict(s interface{}) (map[string]interface{}, error) {
+	return New(s).MapStrict()
+}
+
+// ValuesStrict converts the given struct to a []interface{} and returns an
+// error for unsupported kinds. For more info refer to Struct types
+// ValuesStrict() method. It panics if s's kind is not struct.
+func ValuesStrict(s interface{}) ([]interface{}, error) {
+	return New(s).ValuesStrict()
+}
@@ -0,0 +1,85 @@
+package structs
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestMapStrict(t *testing.T) {
+	type Nested struct {
+		Callbacks []func()
+	}
+
+	type Server struct {
+		Name   string
+		Nested Nested
+	}
+
+	type Supported struct {
+		Name  string
+		Tags  []string
+		Attrs map[string]interface{}
+	}
+
+	if _, err := MapStrict(Supported{Name: "fatih"}); err != nil {
+		t.Errorf("MapStrict should not return an error for supported kinds: %s", err)
+	}
+
+	_, err := MapStrict(Server{Nested: Nested{Callbacks: []func(){nil}}})
+	if err == nil || err.Error() != "unsupported kind func at Nested.Callbacks" {
+		t.Errorf("MapStrict should return an error for nested funcs, got: %v", err)
+	}
+
+	type Unsafe struct {
+		Ch      chan int `structs:"-"`
+		Pointer unsafe.Pointer
+	}
+
+	if _, err := MapStrict(Unsafe{}); err == nil {
+		t.Error("MapStrict should return an error for unsafe.Pointer")
+	}
+
+	if _, err := ValuesStrict(Unsafe{}); err == nil {
+		t.Error("ValuesStrict should return an error for unsafe.Pointer")
+	}
+}
+
+func TestOnUnsupportedKind(t *testing.T) {
+	type Job struct {
+		Name     string
+		Done     chan struct{}
+		Callback interface{}
+	}
+
+	job := Job{Name: "job", Done: make(chan struct{}), Callback: func() {}}
+
+	s := New(job)
+	s.OnUnsupportedKind = KindSkip
+
+	if m := s.Map(); len(m) != 1 || m["Name"] != "job" {
+		t.Errorf("Map should skip unsupported kinds with KindSkip, got: %v", m)
+	}
+
+	s = New(job)
+	s.OnUnsupportedKind = KindNil
+
+	m := s.Map()
+	if len(m) != 3 || m["Done"] != nil || m["Callback"] != nil {
+		t.Errorf("Map should replace unsupported kinds with nil with KindNil, got: %v", m)
+	}
+
+	if v := s.Values(); len(v) != 3 || v[1] != nil {
+		t.Errorf("Values should replace unsupported kinds with nil with KindNil, got: %v", v)
+	}
+
+	s = New(job)
+	s.OnUnsupportedKind = KindError
+
+	if _, err := s.MapStrict(); err == nil || err.Error() != "unsupported kind chan at Done" {
+		t.Errorf("MapStrict should return the error of KindError, got: %v", err)
+	}
+
+	if _, err := s.ValuesStrict(); err == nil || err.Error() != "unsupported kind chan at Done" {
+		t.Errorf("ValuesStrict should return the error of KindError, got: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package structs
 
 import (
 	"fmt"
+	"sort"
 
 	"reflect"
 )
@@ -12,6 +13,10 @@ var (
 	// a more granular to tweak certain structs. Lookup the necessary functions
 	// for more info.
 	DefaultTagName = "structs" // struct's field default tag name
+
+	// Mask replaces the values of fields tagged with the "sensitive" option
+	// in the output of Map and MapString, unless they have a mask tag.
+	Mask = "***"
 )
 
 // Struct encapsulates a struct type to provide several high level functions
@@ -20,6 +25,63 @@ type Struct struct {
 	raw     interface{}
 	value   reflect.Value
 	TagName string
+
+	// Trace, if set, is called for every decision made about a field, such
+	// as skipping, renaming or recursing into it. It's handy to find out why
+	// a field doesn't appear in the output of Map.
+	Trace func(TraceEvent)
+
+	// OnCycle defines how pointers that point back to a struct which is
+	// already being converted are handled. Without it, such cycles would
+	// recurse infinitely. The default is CycleNil.
+	OnCycle CyclePolicy
+
+	// MaxDepth limits how many levels of nested structs are converted. Nested
+	// structs below the limit are handled as if they were tagged with
+	// "omitnested". Zero means no limit.
+	MaxDepth int
+
+	// JSONMarshaler defines how nested structs implementing json.Marshaler
+	// are converted. By default they're converted like any other struct.
+	JSONMarshaler MarshalerPolicy
+
+	// OnUnsupportedKind defines how fields that don't carry data, such as a
+	// chan, a func or an unsafe.Pointer, are handled by Map and Values. By
+	// default they're passed through as is.
+	OnUnsupportedKind KindPolicy
+
+	// KeyCase defines how the keys of fields without a name in their tag are
+	// derived from the field's name. By default the field's name is used as
+	// is.
+	KeyCase KeyCase
+
+	// KeyPrefix and KeySuffix are added to every key, including the keys of
+	// nested and flattened structs, i.e. "app_" for metric labels or "X-"
+	// for HTTP headers. Keys of map fields are left as is.
+	KeyPrefix string
+	KeySuffix string
+
+	// OnDuplicateKey defines how fields are handled by Map whose key is
+	// already used by another field. By default the last field wins.
+	OnDuplicateKey DuplicatePolicy
+
+	// AllowList includes only the fields tagged with `log:"true"` in the
+	// output of Map, MapString and LogValue, for services which must not log
+	// any field by default. Fields of nested structs must be tagged too.
+	AllowList bool
+
+	// Order defines the order of the fields returned by Fields, Names and
+	// Values, so the output is stable across struct edits. By default the
+	// fields are in the order they're declared.
+	Order FieldOrder
+
+	// Secrets, if set, resolves the secrets of the fields with a secret tag
+	// when Fill is called, after the fields are set from the map. See
+	// ResolveSecrets.
+	Secrets SecretResolver
+
+	state *callState
+	depth int
 }
 
 // New returns a new *Struct with the struct s. It panics if the s's kind is
@@ -32,6 +94,21 @@ func New(s interface{}) *Struct {
 	}
 }
 
+// NewE returns a new *Struct with the struct s. Unlike New, it returns an
+// error instead of panicking if the s's kind is not struct.
+func NewE(s interface{}) (*Struct, error) {
+	v, err := structVal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Struct{
+		raw:     s,
+		value:   v,
+		TagName: DefaultTagName,
+	}, nil
+}
+
 // Map converts the given struct to a map[string]interface{}, where the keys
 // of the map are the field names and the values of the map the associated
 // values of the fields. The default key string is the struct field name but
@@ -76,6 +153,27 @@ func New(s interface{}) *Struct {
 //   // the field is skipped if empty.
 //   Field string `structs:",omitempty"`
 //
+// Fields of the database/sql Null types, such as sql.NullString, and of wrapper
+// types registered with RegisterWrapper appear in the map with their inner
+// value, or nil if they're not valid. Fields implementing
+// driver.Valuer appear with the result of their Value() method. Nested structs
+// implementing encoding.TextMarshaler, such as decimal or ID types, are not
+// converted to a map but appear in their text form. If they have no exported
+// fields, such as time.Time, they appear as is.
+//
+// A tag value with the option of "sensitive" replaces the value with Mask, so
+// passwords or tokens don't leak into logs. Example:
+//
+//   // Field appears in map as "***"
+//   Field string `structs:",sensitive"`
+//
+//   // Field appears in map as "************4242", see RegisterMasker
+//   Field string `mask:"card"`
+//
+// Values which are not converted, such as the ones of omitnested fields or of
+// structs below MaxDepth, are replaced with Mask if they hold a sensitive
+// field.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields will be neglected.
 func (s *Struct) Map() map[string]interface{} {
@@ -91,17 +189,33 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 		return
 	}
 
-	fields := s.structFields()
+	s = s.track()
 
-	for _, field := range fields {
-		name := field.Name
-		val := s.value.FieldByName(name)
+	seen := make(map[string]bool)
+
+	s.entries(func(field, key string, val interface{}) bool {
+		out[s.dedupe(seen, field, key)] = val
+		return true
+	})
+}
+
+// entries calls yield with the key and the value of every entry of the
+// output of Map, along with the name of the field it belongs to. It stops if
+// yield returns false and reports whether it ran to completion. The keys are
+// not deduplicated. Callers must track the pointers being converted.
+func (s *Struct) entries(yield func(field, key string, val interface{}) bool) bool {
+	for _, field := range s.structFields() {
+		if !s.allowed(field) {
+			continue
+		}
+
+		val := s.value.FieldByName(field.Name)
 		isSubStruct := false
 		var finalVal interface{}
 
-		tagName, tagOpts := parseTag(field.Tag.Get(s.TagName))
-		if tagName != "" {
-			name = tagName
+		name, tagOpts := s.key(field)
+		if name != field.Name {
+			s.trace(field.Name, TraceRename, name)
 		}
 
 		// if the value is a zero value and the field is marked as omitempty do
@@ -111,12 +225,37 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 			current := val.Interface()
 
 			if reflect.DeepEqual(current, zero) {
+				s.trace(field.Name, TraceSkip, "omitempty")
 				continue
 			}
 		}
 
-		if !tagOpts.Has("omitnested") {
+		if masked, ok := maskField(s.value.Type(), field, tagOpts, val); ok {
+			s.trace(field.Name, TraceCoerce, "sensitive")
+			if !yield(field.Name, name, masked) {
+				return false
+			}
+			continue
+		}
+
+		if uv, ok := s.unsupported(field.Name, val); ok {
+			if _, skip := uv.(skipped); skip {
+				s.trace(field.Name, TraceSkip, "unsupported kind")
+			} else if !yield(field.Name, name, uv) {
+				return false
+			}
+			continue
+		}
+
+		if dv, ok := sqlValue(val); ok {
+			finalVal = dv
+			s.trace(field.Name, TraceCoerce, "database value")
+		} else if !tagOpts.Has("omitnested") {
 			finalVal = s.nested(val)
+			if _, ok := finalVal.(skipped); ok {
+				s.trace(field.Name, TraceSkip, "cycle")
+				continue
+			}
 
 			v := reflect.ValueOf(val.Interface())
 			if v.Kind() == reflect.Ptr {
@@ -126,27 +265,37 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 			switch v.Kind() {
 			case reflect.Map, reflect.Struct:
 				isSubStruct = true
+				s.trace(field.Name, TraceRecurse, v.Kind().String())
 			}
 		} else {
-			finalVal = val.Interface()
+			finalVal = s.passThrough(val)
 		}
 
 		if tagOpts.Has("string") {
-			s, ok := val.Interface().(fmt.Stringer)
+			str, ok := val.Interface().(fmt.Stringer)
 			if ok {
-				out[name] = s.String()
+				s.trace(field.Name, TraceCoerce, "fmt.Stringer")
+				if !yield(field.Name, name, str.String()) {
+					return false
+				}
+			} else {
+				s.trace(field.Name, TraceSkip, "not a fmt.Stringer")
 			}
 			continue
 		}
 
-		if isSubStruct && (tagOpts.Has("flatten")) {
-			for k := range finalVal.(map[string]interface{}) {
-				out[k] = finalVal.(map[string]interface{})[k]
+		if m, ok := finalVal.(map[string]interface{}); ok && isSubStruct && tagOpts.Has("flatten") {
+			for k := range m {
+				if !yield(field.Name, k, m[k]) {
+					return false
+				}
 			}
-		} else {
-			out[name] = finalVal
+		} else if !yield(field.Name, name, finalVal) {
+			return false
 		}
 	}
+
+	return true
 }
 
 // Values converts the given s struct's field values to a []interface{}.  A
@@ -172,6 +321,8 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected.
 func (s *Struct) Values() []interface{} {
+	s = s.track()
+
 	fields := s.structFields()
 
 	var t []interface{}
@@ -188,24 +339,63 @@ func (s *Struct) Values() []interface{} {
 			current := val.Interface()
 
 			if reflect.DeepEqual(current, zero) {
+				s.trace(field.Name, TraceSkip, "omitempty")
 				continue
 			}
 		}
 
 		if tagOpts.Has("string") {
-			s, ok := val.Interface().(fmt.Stringer)
+			str, ok := val.Interface().(fmt.Stringer)
 			if ok {
-				t = append(t, s.String())
+				t = append(t, str.String())
+				s.trace(field.Name, TraceCoerce, "fmt.Stringer")
+			} else {
+				s.trace(field.Name, TraceSkip, "not a fmt.Stringer")
+			}
+			continue
+		}
+
+		if uv, ok := s.unsupported(field.Name, val); ok {
+			if _, skip := uv.(skipped); skip {
+				s.trace(field.Name, TraceSkip, "unsupported kind")
+			} else {
+				t = append(t, uv)
 			}
 			continue
 		}
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
+		if dv, ok := sqlValue(val); ok {
+			t = append(t, dv)
+			s.trace(field.Name, TraceCoerce, "database value")
+			continue
+		}
+
+		if leaf, ok := textLeaf(val); ok {
+			t = append(t, leaf)
+			continue
+		}
+
+		if leaf, ok := s.jsonLeaf(val); ok {
+			t = append(t, leaf)
+			continue
+		}
+
+		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") && !s.tooDeep() {
 			// look out for embedded structs, and convert them to a
 			// []interface{} to be added to the final values slice
-			t = append(t, Values(val.Interface())...)
+			pv := reflect.ValueOf(val.Interface())
+			if !s.enter(pv) {
+				if dv := s.cycle(pv); dv != (skipped{}) {
+					t = append(t, dv)
+				}
+				continue
+			}
+
+			s.trace(field.Name, TraceRecurse, "struct")
+			t = append(t, s.nestedStruct(val.Interface()).Values()...)
+			s.leave(pv)
 		} else {
-			t = append(t, val.Interface())
+			t = append(t, s.passThrough(val))
 		}
 	}
 
@@ -220,7 +410,7 @@ func (s *Struct) Values() []interface{} {
 //
 // It panics if s's kind is not struct.
 func (s *Struct) Fields() []*Field {
-	return getFields(s.value, s.TagName)
+	return getFields(s.value, s.TagName, s.fieldLayout())
 }
 
 // Names returns a slice of field names. A struct tag with the content of "-"
@@ -231,7 +421,7 @@ func (s *Struct) Fields() []*Field {
 //
 // It panics if s's kind is not struct.
 func (s *Struct) Names() []string {
-	fields := getFields(s.value, s.TagName)
+	fields := getFields(s.value, s.TagName, s.fieldLayout())
 
 	names := make([]string, len(fields))
 
@@ -242,8 +432,13 @@ func (s *Struct) Names() []string {
 	return names
 }
 
-func getFields(v reflect.Value, tagName string) []*Field {
+func getFields(v reflect.Value, tagName string, layout fieldLayout) []*Field {
 	if v.Kind() == reflect.Ptr {
+		// a nil embedded pointer has no fields
+		if v.IsNil() {
+			return nil
+		}
+
 		v = v.Elem()
 	}
 
@@ -259,14 +454,22 @@ func getFields(v reflect.Value, tagName string) []*Field {
 		}
 
 		f := &Field{
-			field: field,
-			value: v.FieldByName(field.Name),
+			field:      field,
+			value:      v.Field(i),
+			defaultTag: tagName,
+			layout:     layout,
 		}
 
 		fields = append(fields, f)
 
 	}
 
+	if layout.order != OrderDeclared {
+		sort.SliceStable(fields, func(i, j int) bool {
+			return layout.less(fields[i].field, fields[j].field)
+		})
+	}
+
 	return fields
 }
 
@@ -275,7 +478,7 @@ func getFields(v reflect.Value, tagName string) []*Field {
 func (s *Struct) Field(name string) *Field {
 	f, ok := s.FieldOk(name)
 	if !ok {
-		panic("field not found")
+		panic(ErrFieldNotFound)
 	}
 
 	return f
@@ -283,7 +486,7 @@ func (s *Struct) Field(name string) *Field {
 
 // FieldOk returns a new Field struct that provides several high level functions
 // around a single struct field entity. The boolean returns true if the field
-// was found.
+// was found. Fields promoted from a nil embedded pointer are not found.
 func (s *Struct) FieldOk(name string) (*Field, bool) {
 	t := s.value.Type()
 
@@ -292,13 +495,38 @@ func (s *Struct) FieldOk(name string) (*Field, bool) {
 		return nil, false
 	}
 
+	value, ok := fieldByIndex(s.value, field.Index)
+	if !ok {
+		return nil, false
+	}
+
 	return &Field{
 		field:      field,
-		value:      s.value.FieldByName(name),
+		value:      value,
 		defaultTag: s.TagName,
+		layout:     s.fieldLayout(),
 	}, true
 }
 
+// fieldByIndex returns the nested field of v for the given index sequence.
+// Unlike reflect.Value.FieldByIndex it doesn't panic if an embedded pointer
+// on the way is nil, but returns false instead.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
 // IsZero returns true if all fields in a struct is a zero value (not
 // initialized) A struct tag with the content of "-" ignores the checking of
 // that particular field. Example:
@@ -316,6 +544,8 @@ func (s *Struct) FieldOk(name string) (*Field, bool) {
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected. It panics if s's kind is not struct.
 func (s *Struct) IsZero() bool {
+	s = s.track()
+
 	fields := s.structFields()
 
 	for _, field := range fields {
@@ -323,8 +553,23 @@ func (s *Struct) IsZero() bool {
 
 		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
-			ok := IsZero(val.Interface())
+		if null, ok := unwrap(val); ok {
+			if null != nil {
+				return false
+			}
+
+			continue
+		}
+
+		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") && !s.tooDeep() {
+			// a pointer cycle is never zero, as the pointer is not nil
+			pv := reflect.ValueOf(val.Interface())
+			if !s.enter(pv) {
+				return false
+			}
+
+			ok := s.nestedStruct(val.Interface()).IsZero()
+			s.leave(pv)
 			if !ok {
 				return false
 			}
@@ -363,6 +608,8 @@ func (s *Struct) IsZero() bool {
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected. It panics if s's kind is not struct.
 func (s *Struct) HasZero() bool {
+	s = s.track()
+
 	fields := s.structFields()
 
 	for _, field := range fields {
@@ -370,8 +617,23 @@ func (s *Struct) HasZero() bool {
 
 		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
-			ok := HasZero(val.Interface())
+		if null, ok := unwrap(val); ok {
+			if null == nil {
+				return true
+			}
+
+			continue
+		}
+
+		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") && !s.tooDeep() {
+			// the struct of a pointer cycle is already being checked
+			pv := reflect.ValueOf(val.Interface())
+			if !s.enter(pv) {
+				continue
+			}
+
+			ok := s.nestedStruct(val.Interface()).HasZero()
+			s.leave(pv)
 			if ok {
 				return true
 			}
@@ -411,21 +673,35 @@ func (s *Struct) structFields() []reflect.StructField {
 		field := t.Field(i)
 		// we can't access the value of unexported fields
 		if field.PkgPath != "" {
+			s.trace(field.Name, TraceSkip, "unexported")
 			continue
 		}
 
 		// don't check if it's omitted
 		if tag := field.Tag.Get(s.TagName); tag == "-" {
+			s.trace(field.Name, TraceSkip, `tag "-"`)
 			continue
 		}
 
 		f = append(f, field)
 	}
 
+	s.fieldLayout().sortFields(f)
 	return f
 }
 
 func strctVal(s interface{}) reflect.Value {
+	v, err := structVal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// structVal returns the underlying struct value of s. It returns an error if
+// s's kind is not struct or a pointer to struct.
+func structVal(s interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(s)
 
 	// if pointer get the underlying element≤
@@ -434,10 +710,10 @@ func strctVal(s interface{}) reflect.Value {
 	}
 
 	if v.Kind() != reflect.Struct {
-		panic("not struct")
+		return v, ErrNotStruct
 	}
 
-	return v
+	return v, nil
 }
 
 // Map converts the given struct to a map[string]interface{}. For more info
@@ -482,6 +758,83 @@ func HasZero(s interface{}) bool {
 	return New(s).HasZero()
 }
 
+// MapE is the same as Map. Instead of panicking, it returns an error if s's
+// kind is not struct.
+func MapE(s interface{}) (map[string]interface{}, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.Map(), nil
+}
+
+// ValuesE is the same as Values. Instead of panicking, it returns an error if
+// s's kind is not struct.
+func ValuesE(s interface{}) ([]interface{}, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.Values(), nil
+}
+
+// FieldsE is the same as Fields. Instead of panicking, it returns an error if
+// s's kind is not struct.
+func FieldsE(s interface{}) ([]*Field, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.Fields(), nil
+}
+
+// NamesE is the same as Names. Instead of panicking, it returns an error if
+// s's kind is not struct.
+func NamesE(s interface{}) ([]string, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.Names(), nil
+}
+
+// IsZeroE is the same as IsZero. Instead of panicking, it returns an error if
+// s's kind is not struct.
+func IsZeroE(s interface{}) (bool, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return false, err
+	}
+
+	return n.IsZero(), nil
+}
+
+// HasZeroE is the same as HasZero. Instead of panicking, it returns an error
+// if s's kind is not struct.
+func HasZeroE(s interface{}) (bool, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return false, err
+	}
+
+	return n.HasZero(), nil
+}
+
+// NameE is the same as Name. Instead of panicking, it returns an error if s's
+// kind is not struct.
+func NameE(s interface{}) (string, error) {
+	n, err := NewE(s)
+	if err != nil {
+		return "", err
+	}
+
+	return n.Name(), nil
+}
+
 // IsStruct returns true if the given variable is a struct or a pointer to
 // struct.
 func IsStruct(s interface{}) bool {
@@ -504,25 +857,163 @@ func Name(s interface{}) string {
 	return New(s).Name()
 }
 
+// nestedStruct returns a new *Struct for the nested struct v, which inherits
+// the options of s, such as the tag name.
+func (s *Struct) nestedStruct(v interface{}) *Struct {
+	n := New(v)
+	n.TagName = s.TagName
+	n.Trace = s.Trace
+	n.OnCycle = s.OnCycle
+	n.MaxDepth = s.MaxDepth
+	n.JSONMarshaler = s.JSONMarshaler
+	n.OnUnsupportedKind = s.OnUnsupportedKind
+	n.KeyCase = s.KeyCase
+	n.KeyPrefix = s.KeyPrefix
+	n.KeySuffix = s.KeySuffix
+	n.OnDuplicateKey = s.OnDuplicateKey
+	n.Order = s.Order
+	n.AllowList = s.AllowList
+	n.state = s.state
+	n.depth = s.depth + 1
+	return n
+}
+
+// passThrough returns the value of val for values which are not converted,
+// such as the ones of omitnested fields or of structs below MaxDepth. Values
+// holding a sensitive field are replaced with Mask, so masking doesn't
+// depend on how deep values are converted.
+func (s *Struct) passThrough(val reflect.Value) interface{} {
+	if sensitiveIn(val, s.TagName, make(map[visit]bool)) {
+		return Mask
+	}
+
+	return val.Interface()
+}
+
+// sensitiveIn returns true if v holds a struct with a sensitive field,
+// including the structs behind pointers and interfaces and in slices, arrays
+// and maps. Pointers, maps and slices already checked are recorded in seen to
+// stop at cycles.
+func sensitiveIn(v reflect.Value, tagName string, seen map[visit]bool) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return false
+		}
+
+		key := visit{ptr: v.Pointer(), typ: v.Type()}
+		if seen[key] {
+			return false
+		}
+
+		seen[key] = true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return sensitiveIn(v.Elem(), tagName, seen)
+	case reflect.Interface:
+		return !v.IsNil() && sensitiveIn(v.Elem(), tagName, seen)
+	case reflect.Slice, reflect.Array:
+		if !composite(v.Type().Elem()) {
+			return false
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if sensitiveIn(v.Index(i), tagName, seen) {
+				return true
+			}
+		}
+	case reflect.Map:
+		if !composite(v.Type().Elem()) {
+			return false
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			if sensitiveIn(iter.Value(), tagName, seen) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			tag := field.Tag.Get(tagName)
+			if tag == "-" {
+				continue
+			}
+
+			_, tagOpts := parseTag(tag)
+			if sensitiveField(t, field, tagOpts) || sensitiveIn(v.Field(i), tagName, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// composite returns true if values of type t can hold structs.
+func composite(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+// tooDeep returns true if the nested structs of s are below the MaxDepth
+// limit and must not be converted.
+func (s *Struct) tooDeep() bool {
+	return s.MaxDepth > 0 && s.depth >= s.MaxDepth
+}
+
 // nested retrieves recursively all types for the given value and returns the
 // nested value.
 func (s *Struct) nested(val reflect.Value) interface{} {
 	var finalVal interface{}
 
+	if dv, ok := sqlValue(val); ok {
+		return dv
+	}
+
+	if s.tooDeep() {
+		return s.passThrough(val)
+	}
+
+	if leaf, ok := textLeaf(val); ok {
+		return leaf
+	}
+
+	if leaf, ok := s.jsonLeaf(val); ok {
+		return leaf
+	}
+
 	v := reflect.ValueOf(val.Interface())
 	if v.Kind() == reflect.Ptr {
+		if !s.enter(v) {
+			return s.cycle(v)
+		}
+		defer s.leave(v)
+
 		v = v.Elem()
 	}
 
 	switch v.Kind() {
 	case reflect.Struct:
-		n := New(val.Interface())
-		n.TagName = s.TagName
-		m := n.Map()
+		m := s.nestedStruct(val.Interface()).Map()
 
 		// do not add the converted value if there are no exported fields, ie:
-		// time.Time
-		if len(m) == 0 {
+		// time.Time. Structs whose fields are all left out, such as by the
+		// AllowList, stay empty maps, so their fields don't leak.
+		if len(m) == 0 && !hasFields(v.Type(), "") {
 			finalVal = val.Interface()
 		} else {
 			finalVal = m
@@ -540,23 +1031,28 @@ func (s *Struct) nested(val reflect.Value) interface{} {
 		}
 
 		// only iterate over struct types, ie: map[string]StructType,
-		// map[string][]StructType,
+		// map[string][]StructType, map[string][2]StructType
 		if mapElem.Kind() == reflect.Struct ||
-			(mapElem.Kind() == reflect.Slice &&
+			((mapElem.Kind() == reflect.Slice || mapElem.Kind() == reflect.Array) &&
 				mapElem.Elem().Kind() == reflect.Struct) {
 			m := make(map[string]interface{}, val.Len())
 			for _, k := range val.MapKeys() {
-				m[k.String()] = s.nested(val.MapIndex(k))
+				elem := s.nested(val.MapIndex(k))
+				if _, ok := elem.(skipped); ok {
+					continue
+				}
+
+				m[k.String()] = elem
 			}
 			finalVal = m
 			break
 		}
 
 		// TODO(arslan): should this be optional?
-		finalVal = val.Interface()
+		finalVal = s.passThrough(val)
 	case reflect.Slice, reflect.Array:
 		if val.Type().Kind() == reflect.Interface {
-			finalVal = val.Interface()
+			finalVal = s.passThrough(val)
 			break
 		}
 
@@ -567,13 +1063,18 @@ func (s *Struct) nested(val reflect.Value) interface{} {
 		if val.Type().Elem().Kind() != reflect.Struct &&
 			!(val.Type().Elem().Kind() == reflect.Ptr &&
 				val.Type().Elem().Elem().Kind() == reflect.Struct) {
-			finalVal = val.Interface()
+			finalVal = s.passThrough(val)
 			break
 		}
 
 		slices := make([]interface{}, val.Len())
 		for x := 0; x < val.Len(); x++ {
 			slices[x] = s.nested(val.Index(x))
+
+			// keep the indexes of the other elements intact
+			if _, ok := slices[x].(skipped); ok {
+				slices[x] = nil
+			}
 		}
 		finalVal = slices
 	default:
@@ -21,6 +21,62 @@ func TestMapNonStruct(t *testing.T) {
 	_ = Map(foo)
 }
 
+func TestErrorVariants(t *testing.T) {
+	type A struct {
+		Name string
+	}
+
+	nonStructs := []interface{}{nil, 1, "foo", []string{"foo"}, (*A)(nil)}
+
+	for _, s := range nonStructs {
+		if _, err := NewE(s); err == nil {
+			t.Errorf("NewE(%#v) should return an error", s)
+		}
+
+		if _, err := MapE(s); err == nil {
+			t.Errorf("MapE(%#v) should return an error", s)
+		}
+
+		if _, err := ValuesE(s); err == nil {
+			t.Errorf("ValuesE(%#v) should return an error", s)
+		}
+
+		if _, err := FieldsE(s); err == nil {
+			t.Errorf("FieldsE(%#v) should return an error", s)
+		}
+
+		if _, err := NamesE(s); err == nil {
+			t.Errorf("NamesE(%#v) should return an error", s)
+		}
+
+		if _, err := IsZeroE(s); err == nil {
+			t.Errorf("IsZeroE(%#v) should return an error", s)
+		}
+
+		if _, err := HasZeroE(s); err == nil {
+			t.Errorf("HasZeroE(%#v) should return an error", s)
+		}
+
+		if _, err := NameE(s); err == nil {
+			t.Errorf("NameE(%#v) should return an error", s)
+		}
+	}
+
+	m, err := MapE(&A{Name: "fatih"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m["Name"] != "fatih" {
+		t.Errorf("MapE should return the map, got: %v", m)
+	}
+
+	name, err := NameE(A{})
+	if err != nil || name != "A" {
+		t.Errorf("NameE should return the name, got: %q %v", name, err)
+	}
+}
+
 func TestStructIndexes(t *testing.T) {
 	type C struct {
 		something int
@@ -1451,3 +1507,61 @@ func TestMap_InterfaceTypeWithMapValue(t *testing.T) {
 
 	_ = Map(a)
 }
+
+func TestEmbeddedPointer(t *testing.T) {
+	type Base struct {
+		ID int
+	}
+
+	type Value struct {
+		Base
+		Name string
+	}
+
+	type Pointer struct {
+		*Base
+		Name string
+	}
+
+	v := Value{Base: Base{ID: 1}, Name: "fatih"}
+	p := &Pointer{Base: &Base{ID: 1}, Name: "fatih"}
+
+	if !reflect.DeepEqual(Map(v), Map(p)) {
+		t.Errorf("Map of embedded pointer should match embedded value, got: %v and %v", Map(v), Map(p))
+	}
+
+	if !reflect.DeepEqual(Values(v), Values(p)) {
+		t.Errorf("Values of embedded pointer should match embedded value, got: %v and %v", Values(v), Values(p))
+	}
+
+	if IsZero(v) != IsZero(p) || HasZero(v) != HasZero(p) {
+		t.Error("IsZero and HasZero of embedded pointer should match embedded value")
+	}
+
+	id := New(p).Field("ID")
+	if id.Value() != 1 {
+		t.Errorf("Promoted field of embedded pointer should be found, got: %v", id.Value())
+	}
+
+	if err := id.Set(2); err != nil || p.ID != 2 {
+		t.Errorf("Promoted field of embedded pointer should be settable, got: %v", err)
+	}
+
+	if len(New(p).Field("Base").Fields()) != 1 {
+		t.Error("Fields of embedded pointer should be returned")
+	}
+
+	nilBase := &Pointer{Name: "fatih"}
+
+	if _, ok := New(nilBase).FieldOk("ID"); ok {
+		t.Error("Promoted field of nil embedded pointer should not be found")
+	}
+
+	if fields := New(nilBase).Field("Base").Fields(); len(fields) != 0 {
+		t.Errorf("Nil embedded pointer should have no fields, got: %v", fields)
+	}
+
+	if !HasZero(nilBase) || IsZero(nilBase) {
+		t.Error("Nil embedded pointer should be a zero value")
+	}
+}
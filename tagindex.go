@@ -0,0 +1,76 @@
+package structs
+
+import "reflect"
+
+// Tag is the parsed tag of a field, for the tag name of the Struct it
+// belongs to.
+type Tag struct {
+	// Name is the name given in the tag, which is empty if the tag has none,
+	// such as for `structs:",omitempty"`.
+	Name string
+
+	// Key is the key of the field as it appears in the output of Map. For
+	// more info refer to Field types Key() method.
+	Key string
+
+	// Options are the options which come after the name, such as
+	// "omitempty" or "flatten".
+	Options []string
+
+	// StructTag is the whole tag of the field, to look up other tag names.
+	StructTag reflect.StructTag
+}
+
+// Has returns true if the tag has the given option.
+func (t Tag) Has(opt string) bool {
+	return tagOptions(t.Options).Has(opt)
+}
+
+// Tags returns the parsed tags of the fields of s and of the structs nested
+// in it by the paths of the fields, such as "DB.Host", so frameworks built
+// on this package can precompute their own tables. Every field which is
+// exported and not tagged with "-" is included, even without a tag. Nested
+// structs are the fields of struct type, or pointers to one, except for
+// structs implementing encoding.TextUnmarshaler such as time.Time. The
+// fields of structs in slices and maps are not included. Only the type of s
+// is examined, so nil pointers don't matter, and nested structs of a type
+// which is already being examined, such as in a linked list, are left out.
+// It panics if s's kind is not struct.
+func (s *Struct) Tags() map[string]Tag {
+	tags := make(map[string]Tag)
+	s.tags("", tags, map[reflect.Type]bool{s.value.Type(): true})
+	return tags
+}
+
+// tags adds the tags of the fields of s to tags, with their paths prefixed
+// by prefix. The types of the structs being examined are in parents.
+func (s *Struct) tags(prefix string, tags map[string]Tag, parents map[reflect.Type]bool) {
+	for _, field := range s.structFields() {
+		name, opts := parseTag(field.Tag.Get(s.TagName))
+		key, _ := s.key(field)
+		path := prefix + field.Name
+
+		tags[path] = Tag{
+			Name:      name,
+			Key:       key,
+			Options:   opts,
+			StructTag: field.Tag,
+		}
+
+		t := indirectType(field.Type)
+		if !envNested(t) || parents[t] {
+			continue
+		}
+
+		parents[t] = true
+		s.nestedStruct(reflect.New(t).Interface()).tags(path+".", tags, parents)
+		delete(parents, t)
+	}
+}
+
+// Tags returns the parsed tags of the fields of the given struct and of the
+// structs nested in it by their paths. For more info refer to Struct types
+// Tags() method. It panics if s's kind is not struct.
+func Tags(s interface{}) map[string]Tag {
+	return New(s).Tags()
+}
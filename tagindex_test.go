@@ -0,0 +1,52 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type tagIndexNode struct {
+	Value int           `structs:"value,omitempty"`
+	Next  *tagIndexNode `structs:"next"`
+}
+
+type tagIndexDB struct {
+	Host string `structs:"host" env:"DB_HOST"`
+}
+
+type tagIndexConfig struct {
+	Name    string
+	DB      *tagIndexDB `structs:",flatten"`
+	List    tagIndexNode
+	Created time.Time
+	Items   []tagIndexDB
+	Ignored string `structs:"-"`
+	secret  string
+}
+
+func TestTags(t *testing.T) {
+	s := New(tagIndexConfig{})
+	s.KeyCase = KeySnakeCase
+
+	tags := s.Tags()
+
+	want := map[string]Tag{
+		"Name":       {Key: "name", Options: []string{}},
+		"DB":         {Key: "db", Options: []string{"flatten"}, StructTag: `structs:",flatten"`},
+		"DB.Host":    {Name: "host", Key: "host", Options: []string{}, StructTag: `structs:"host" env:"DB_HOST"`},
+		"List":       {Key: "list", Options: []string{}},
+		"List.Value": {Name: "value", Key: "value", Options: []string{"omitempty"}, StructTag: `structs:"value,omitempty"`},
+		"List.Next":  {Name: "next", Key: "next", Options: []string{}, StructTag: `structs:"next"`},
+		"Created":    {Key: "created", Options: []string{}},
+		"Items":      {Key: "items", Options: []string{}},
+	}
+
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("got  %+v\nwant %+v", tags, want)
+	}
+
+	if !tags["List.Value"].Has("omitempty") || tags["DB.Host"].StructTag.Get("env") != "DB_HOST" {
+		t.Errorf("got %+v", tags)
+	}
+}
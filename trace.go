@@ -0,0 +1,82 @@
+package structs
+
+import "fmt"
+
+// TraceAction describes what the package did with a single field.
+type TraceAction int
+
+const (
+	// TraceSkip reports that a field was left out, i.e. because it's not
+	// exported, ignored with "-" or empty with "omitempty".
+	TraceSkip TraceAction = iota
+
+	// TraceRename reports that a field is named differently due to its tag.
+	TraceRename
+
+	// TraceRecurse reports that the package descended into a nested value of
+	// the field, such as a struct, a map or a slice of structs.
+	TraceRecurse
+
+	// TraceCoerce reports that the field's value was converted, i.e. with
+	// String() for the "string" option or Value() for a driver.Valuer.
+	TraceCoerce
+)
+
+// String returns the name of the action.
+func (a TraceAction) String() string {
+	switch a {
+	case TraceSkip:
+		return "skipped"
+	case TraceRename:
+		return "renamed"
+	case TraceRecurse:
+		return "recursed"
+	case TraceCoerce:
+		return "coerced"
+	}
+
+	return fmt.Sprintf("TraceAction(%d)", int(a))
+}
+
+// TraceEvent is passed to the Trace callback of a Struct for every decision
+// made about a field.
+type TraceEvent struct {
+	// Struct is the type name of the struct the field belongs to.
+	Struct string
+
+	// Field is the name of the field as declared in the struct.
+	Field string
+
+	// Action is what happened to the field.
+	Action TraceAction
+
+	// Detail gives more information about the action, i.e. the reason a
+	// field was skipped or the new name of a renamed field.
+	Detail string
+}
+
+// String returns a human readable description of the event, such as:
+//
+//   Server.users: skipped (unexported)
+func (e TraceEvent) String() string {
+	return fmt.Sprintf("%s.%s: %s (%s)", e.Struct, e.Field, e.Action, e.Detail)
+}
+
+// trace reports an event for the given field if s has a Trace callback.
+func (s *Struct) trace(field string, action TraceAction, detail string) {
+	if s.Trace == nil {
+		return
+	}
+
+	name := s.value.Type().Name()
+	if name == "" {
+		name = s.value.Type().String()
+	}
+
+	s.Trace(TraceEvent{
+		Struct: name,
+		Field:  field,
+		Action: action,
+		Detail: detail,
+	})
+}
@@ -0,0 +1,41 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	type Nested struct {
+		A string
+	}
+
+	type Server struct {
+		Name    string `structs:"name"`
+		Ignored string `structs:"-"`
+		Empty   string `structs:",omitempty"`
+		Nested  Nested
+		users   []string
+	}
+
+	var events []string
+
+	s := New(Server{users: []string{"fatih"}})
+	s.Trace = func(e TraceEvent) {
+		events = append(events, e.String())
+	}
+
+	s.Map()
+
+	want := []string{
+		`Server.Ignored: skipped (tag "-")`,
+		"Server.users: skipped (unexported)",
+		"Server.Name: renamed (name)",
+		"Server.Empty: skipped (omitempty)",
+		"Server.Nested: recursed (struct)",
+	}
+
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Trace events should be\n%q\ngot\n%q", want, events)
+	}
+}
@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Transform calls fn for every value of type T in the struct pointed to by
+// dst and replaces the value with fn's result, such as for normalization
+// passes:
+//
+//   structs.Transform(&user, strings.TrimSpace)
+//   structs.Transform(&order, func(p float64) float64 { return math.Round(p*100) / 100 })
+//
+// The values are the fields visited by Edit whose type is exactly T, so a
+// type Email string is not a string, and the elements of T of their slices,
+// arrays and maps, the value their *T points to if it's not nil, or the
+// value their interface holds if it's a T. It returns an error if dst is not
+// a pointer to struct.
+func Transform[T any](dst interface{}, fn func(T) T) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	apply := func(_ string, v reflect.Value) {
+		out := fn(v.Interface().(T))
+		v.Set(reflect.ValueOf(&out).Elem())
+	}
+
+	return s.Edit(func(path string, f *Field) error {
+		typedValues(path, f.value, t, true, apply)
+		return nil
+	})
+}
+
+// Collect returns every value of type T in s and in the structs nested in
+// it by their paths, such as all the timestamps of a struct graph:
+//
+//   for path, ts := range structs.Collect[time.Time](order) {
+//       fmt.Println(path, ts)
+//   }
+//
+// The values are the ones Transform would change, with the paths of Walk,
+// i.e. "Lines[\"k\"].Created" or "Tags[1]". It panics if s's kind is not
+// struct.
+func Collect[T any](s interface{}) map[string]T {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	values := make(map[string]T)
+
+	New(s).Walk(func(path string, f *Field) error {
+		typedValues(path, f.value, t, false, func(path string, v reflect.Value) {
+			values[path] = v.Interface().(T)
+		})
+
+		return nil
+	})
+
+	return values
+}
+
+// typedValues calls fn for v, or the values in v, of type t as described in
+// Transform, with their paths. If set is true, the values are settable and
+// values copied out of maps and interfaces are stored back after fn returns.
+func typedValues(path string, v reflect.Value, t reflect.Type, set bool, fn func(string, reflect.Value)) {
+	if v.Type() == t {
+		fn(path, v)
+		return
+	}
+
+	// copied calls fn for a settable copy of v and returns it
+	copied := func(path string, v reflect.Value) reflect.Value {
+		if !set {
+			fn(path, v)
+			return v
+		}
+
+		c := reflect.New(t).Elem()
+		c.Set(v)
+		fn(path, c)
+		return c
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && v.Type().Elem() == t {
+			fn(path, v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() || v.Elem().Type() != t {
+			return
+		}
+
+		if c := copied(path, v.Elem()); set {
+			v.Set(c)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem() != t {
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			fn(path+"["+strconv.Itoa(i)+"]", v.Index(i))
+		}
+	case reflect.Map:
+		if v.Type().Elem() != t {
+			return
+		}
+
+		for _, k := range sortedKeys(v) {
+			if c := copied(path+"["+indexKey(k)+"]", v.MapIndex(k)); set {
+				v.SetMapIndex(k, c)
+			}
+		}
+	}
+}
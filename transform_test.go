@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type transformLine struct {
+	SKU   string
+	Price float64
+}
+
+type transformOrder struct {
+	Name    string
+	Email   transformEmail
+	Note    *string
+	Tags    []string
+	Lines   map[string]transformLine
+	Created time.Time
+	Any     interface{}
+	Ignored string `structs:"-"`
+}
+
+type transformEmail string
+
+func TestTransform(t *testing.T) {
+	note := " note "
+	o := transformOrder{
+		Name:    " Ann ",
+		Email:   " A@B ",
+		Note:    &note,
+		Tags:    []string{" a", "b "},
+		Lines:   map[string]transformLine{" k ": {SKU: " x ", Price: 1.005}},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("", 3600)),
+		Any:     " any ",
+		Ignored: " ignored ",
+	}
+
+	if err := Transform(&o, strings.TrimSpace); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Transform(&o, func(p float64) float64 { return math.Round(p * 100) }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Transform(&o, time.Time.UTC); err != nil {
+		t.Fatal(err)
+	}
+
+	want := transformOrder{
+		Name:    "Ann",
+		Email:   " A@B ",
+		Note:    &note,
+		Tags:    []string{"a", "b"},
+		Lines:   map[string]transformLine{" k ": {SKU: "x", Price: 100}},
+		Created: time.Date(2024, 1, 2, 2, 4, 5, 6, time.UTC),
+		Any:     "any",
+		Ignored: " ignored ",
+	}
+
+	if note != "note" || !reflect.DeepEqual(o, want) {
+		t.Errorf("got %+v\nwant %+v", o, want)
+	}
+
+	if err := Transform(o, strings.TrimSpace); err == nil {
+		t.Error("expected an error for a struct which is not a pointer")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	o := &transformOrder{
+		Name:    "Ann",
+		Tags:    []string{"a", "b"},
+		Lines:   map[string]transformLine{"k": {SKU: "x"}},
+		Created: created,
+		Any:     "any",
+	}
+
+	want := map[string]string{
+		"Name":           "Ann",
+		"Tags[0]":        "a",
+		"Tags[1]":        "b",
+		`Lines["k"].SKU`: "x",
+		"Any":            "any",
+	}
+
+	if got := Collect[string](o); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	if got := Collect[time.Time](*o); len(got) != 1 || !got["Created"].Equal(created) {
+		t.Errorf("got %v", got)
+	}
+
+	if got := Collect[int](o); len(got) != 0 {
+		t.Errorf("got %v", got)
+	}
+}
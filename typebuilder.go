@@ -0,0 +1,141 @@
+package structs
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+)
+
+// FieldSpec describes a field of a struct type built at runtime with
+// StructOf or a TypeBuilder.
+type FieldSpec struct {
+	// Name is the name of the field, which must be an exported identifier.
+	Name string
+
+	// Type is the type of the field.
+	Type reflect.Type
+
+	// Tag is the tag of the field, such as `json:"name" structs:"name"`.
+	Tag string
+}
+
+// TypeBuilder builds struct types at runtime, for schema driven systems
+// whose shapes are only known from their config:
+//
+//   t, err := structs.NewTypeBuilder().
+//       AddField("Name", reflect.TypeOf(""), `structs:"name"`).
+//       AddField("Port", reflect.TypeOf(0), `default:"8080"`).
+//       Build()
+//
+//   v := reflect.New(t).Interface()
+//   err = structs.Fill(map[string]interface{}{"name": "api"}, v)
+//
+// The values of the built types are ordinary structs, so they work with all
+// functions of this package. Building the same fields again returns the
+// same type.
+type TypeBuilder struct {
+	fields []reflect.StructField
+	names  map[string]bool
+	err    error
+}
+
+// NewTypeBuilder returns a TypeBuilder of a struct type without fields.
+func NewTypeBuilder() *TypeBuilder {
+	return &TypeBuilder{names: make(map[string]bool)}
+}
+
+// AddField adds a field with the given name, type and tag. The first invalid
+// field, such as a field whose name is not an exported identifier or is
+// already used, is reported by Build.
+func (b *TypeBuilder) AddField(name string, typ reflect.Type, tag string) *TypeBuilder {
+	return b.add(reflect.StructField{
+		Name: name,
+		Type: typ,
+		Tag:  reflect.StructTag(tag),
+	})
+}
+
+// AddFields adds the exported fields of the struct type t, or the struct
+// type t points to, with their types and tags, such as to extend a type
+// which can't be modified. Unexported fields are left out. Embedded fields
+// stay embedded if their type has no methods, and become fields named after
+// their type otherwise, since the methods can't be promoted at runtime.
+func (b *TypeBuilder) AddFields(t reflect.Type) *TypeBuilder {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		if b.err == nil {
+			b.err = fmt.Errorf("%w: can't add the fields of %s", ErrNotStruct, t)
+		}
+
+		return b
+	}
+
+	for _, f := range exportedFields(t) {
+		b.add(f)
+	}
+
+	return b
+}
+
+// exportedFields returns the exported fields of the struct type t as
+// described in AddFields.
+func exportedFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if f.Anonymous && (f.Type.NumMethod() > 0 || reflect.PointerTo(indirectType(f.Type)).NumMethod() > 0) {
+			f.Anonymous = false
+		}
+
+		fields = append(fields, reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag, Anonymous: f.Anonymous, Index: f.Index})
+	}
+
+	return fields
+}
+
+// add adds the field f, which may be embedded, as described in AddField.
+func (b *TypeBuilder) add(f reflect.StructField) *TypeBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	switch {
+	case !token.IsIdentifier(f.Name) || !token.IsExported(f.Name):
+		b.err = fmt.Errorf("invalid field name %q: not an exported identifier", f.Name)
+	case b.names[f.Name]:
+		b.err = fmt.Errorf("invalid field name %q: already used", f.Name)
+	case f.Type == nil:
+		b.err = fmt.Errorf("invalid field %s: no type", f.Name)
+	default:
+		b.names[f.Name] = true
+		b.fields = append(b.fields, f)
+	}
+
+	return b
+}
+
+// Build returns the struct type with the fields added so far, in the order
+// they were added. It returns an error if a field is invalid.
+func (b *TypeBuilder) Build() (reflect.Type, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return reflect.StructOf(b.fields), nil
+}
+
+// StructOf returns the struct type with the given fields. For more info
+// refer to TypeBuilder. It returns an error if a field is invalid.
+func StructOf(fields ...FieldSpec) (reflect.Type, error) {
+	b := NewTypeBuilder()
+	for _, f := range fields {
+		b.AddField(f.Name, f.Type, f.Tag)
+	}
+
+	return b.Build()
+}
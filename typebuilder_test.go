@@ -0,0 +1,80 @@
+package structs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypeBuilder(t *testing.T) {
+	typ, err := NewTypeBuilder().
+		AddField("Name", reflect.TypeOf(""), `structs:"name"`).
+		AddField("Port", reflect.TypeOf(0), `default:"8080"`).
+		AddField("Timeout", reflect.TypeOf(time.Duration(0)), "").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := reflect.New(typ).Interface()
+
+	if err := ApplyDefaults(v); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Fill(map[string]interface{}{"name": "api", "Timeout": time.Second}, v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"name": "api", "Port": 8080, "Timeout": time.Second}
+	if got := Map(v); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	same, err := StructOf(
+		FieldSpec{Name: "Name", Type: reflect.TypeOf(""), Tag: `structs:"name"`},
+		FieldSpec{Name: "Port", Type: reflect.TypeOf(0), Tag: `default:"8080"`},
+		FieldSpec{Name: "Timeout", Type: reflect.TypeOf(time.Duration(0))},
+	)
+	if err != nil || same != typ {
+		t.Errorf("got %v, %v", same, err)
+	}
+}
+
+func TestTypeBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		fields []FieldSpec
+		want   string
+	}{
+		{[]FieldSpec{{Name: "name", Type: reflect.TypeOf("")}}, `"name": not an exported`},
+		{[]FieldSpec{{Name: "A-B", Type: reflect.TypeOf("")}}, `"A-B": not an exported`},
+		{[]FieldSpec{{Name: "A", Type: reflect.TypeOf("")}, {Name: "A", Type: reflect.TypeOf(0)}}, `"A": already used`},
+		{[]FieldSpec{{Name: "A"}, {Name: "b"}}, "A: no type"},
+	}
+
+	for _, test := range tests {
+		if _, err := StructOf(test.fields...); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want %q", test.fields, err, test.want)
+		}
+	}
+}
+
+func TestTypeBuilder_AddFields(t *testing.T) {
+	typ, err := NewTypeBuilder().AddFields(reflect.TypeOf(&walkTarget{})).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f := typ.Field(0); f.Name != "WalkBase" || !f.Anonymous {
+		t.Errorf("got %+v, want an embedded field", f)
+	}
+
+	if _, ok := typ.FieldByName("secret"); ok || typ.NumField() != 9 {
+		t.Errorf("got %s", typ)
+	}
+
+	if _, err := NewTypeBuilder().AddFields(reflect.TypeOf(0)).Build(); err == nil {
+		t.Error("expected an error for an int")
+	}
+}
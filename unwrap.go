@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"reflect"
+	"sync"
+)
+
+// wrapper holds the functions registered for a wrapper type with
+// RegisterWrapper.
+type wrapper struct {
+	inner  reflect.Type
+	unwrap func(w reflect.Value) (interface{}, bool)
+	wrap   func(inner reflect.Value, valid bool) reflect.Value
+}
+
+var (
+	wrappersMu sync.RWMutex
+	wrappers   = map[reflect.Type]wrapper{}
+)
+
+// RegisterWrapper registers the functions to unwrap and wrap values of the
+// wrapper type W, such as an Optional[T] or a null.String, which holds an
+// inner value of type T and a presence flag. Map and Values use the inner
+// value of valid wrappers and nil otherwise, IsZero and HasZero consider
+// invalid wrappers as zero and Fill sets the inner value and marks it as
+// present, converting it as described in Fill. A nil value sets an invalid
+// wrapper.
+//
+// The wrap function may be nil if values of type W are never filled. The
+// database/sql Null types are handled without registering them. Registering
+// the same type again replaces the previous functions. It's safe to call
+// RegisterWrapper concurrently, though it's usually called from an init
+// function:
+//
+//   structs.RegisterWrapper(
+//       func(o Optional[int]) (int, bool) { return o.Get() },
+//       func(v int, ok bool) Optional[int] { return Optional[int]{v, ok} },
+//   )
+func RegisterWrapper[W, T any](unwrap func(W) (T, bool), wrap func(T, bool) W) {
+	w := wrapper{
+		inner: reflect.TypeOf((*T)(nil)).Elem(),
+		unwrap: func(v reflect.Value) (interface{}, bool) {
+			inner, ok := unwrap(v.Interface().(W))
+			return inner, ok
+		},
+	}
+
+	if wrap != nil {
+		w.wrap = func(inner reflect.Value, valid bool) reflect.Value {
+			t, _ := inner.Interface().(T)
+			return reflect.ValueOf(wrap(t, valid))
+		}
+	}
+
+	wrappersMu.Lock()
+	wrappers[reflect.TypeOf((*W)(nil)).Elem()] = w
+	wrappersMu.Unlock()
+}
+
+// registered returns the wrapper registered for t.
+func registered(t reflect.Type) (wrapper, bool) {
+	wrappersMu.RLock()
+	defer wrappersMu.RUnlock()
+
+	w, ok := wrappers[t]
+	return w, ok
+}
+
+// unwrap returns the inner value of v if its type was registered with
+// RegisterWrapper or is one of the database/sql Null types. The inner value
+// is nil if v is not valid. The boolean is false if v is not a wrapper.
+func unwrap(v reflect.Value) (interface{}, bool) {
+	if w, ok := registered(v.Type()); ok {
+		inner, valid := w.unwrap(v)
+		if !valid {
+			return nil, true
+		}
+
+		return inner, true
+	}
+
+	return sqlNull(v)
+}
+
+// fillWrapper sets the wrapper v from val if v's type was registered with a
+// wrap function. The boolean is false if v is not such a wrapper or val is
+// already a wrapper itself.
+func (s *Struct) fillWrapper(v reflect.Value, val interface{}) (bool, error) {
+	w, ok := registered(v.Type())
+	if !ok || w.wrap == nil {
+		return false, nil
+	}
+
+	if given := reflect.ValueOf(val); given.IsValid() && given.Type().AssignableTo(v.Type()) {
+		return false, nil
+	}
+
+	inner := reflect.New(w.inner).Elem()
+	if val == nil {
+		v.Set(w.wrap(inner, false))
+		return true, nil
+	}
+
+	if err := s.assign(inner, val); err != nil {
+		return true, err
+	}
+
+	v.Set(w.wrap(inner, true))
+	return true, nil
+}
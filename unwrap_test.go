@@ -0,0 +1,114 @@
+package structs
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type optional[T any] struct {
+	value T
+	ok    bool
+}
+
+func (o optional[T]) get() (T, bool) { return o.value, o.ok }
+
+func some[T any](v T) optional[T] { return optional[T]{value: v, ok: true} }
+
+func init() {
+	RegisterWrapper(optional[int].get, func(v int, ok bool) optional[int] {
+		return optional[int]{value: v, ok: ok}
+	})
+	RegisterWrapper(optional[string].get, func(v string, ok bool) optional[string] {
+		return optional[string]{value: v, ok: ok}
+	})
+}
+
+type wrapped struct {
+	Age  optional[int]
+	Name optional[string]
+	Note sql.NullString
+}
+
+func TestWrapper_Map(t *testing.T) {
+	w := wrapped{
+		Age:  some(42),
+		Note: sql.NullString{String: "x", Valid: true},
+	}
+
+	want := map[string]interface{}{
+		"Age":  42,
+		"Name": nil,
+		"Note": "x",
+	}
+
+	if m := Map(w); !reflect.DeepEqual(m, want) {
+		t.Errorf("Map: got %#v want %#v", m, want)
+	}
+
+	if v := Values(w); !reflect.DeepEqual(v, []interface{}{42, nil, "x"}) {
+		t.Errorf("Values: got %#v", v)
+	}
+}
+
+func TestWrapper_IsZero(t *testing.T) {
+	// a valid wrapper holding a zero value is not zero
+	w := wrapped{Age: some(0)}
+
+	if IsZero(w) {
+		t.Error("IsZero: expected false")
+	}
+
+	if !HasZero(w) {
+		t.Error("HasZero: expected true")
+	}
+
+	if !New(w).Field("Name").IsZero() {
+		t.Error("Field.IsZero: expected true for an invalid wrapper")
+	}
+
+	if New(w).Field("Age").IsZero() {
+		t.Error("Field.IsZero: expected false for a valid wrapper")
+	}
+
+	w = wrapped{Age: some(1), Name: some(""), Note: sql.NullString{Valid: true}}
+	if HasZero(w) {
+		t.Error("HasZero: expected false")
+	}
+}
+
+func TestWrapper_Fill(t *testing.T) {
+	w := wrapped{Name: some("old")}
+
+	err := Fill(map[string]interface{}{
+		"Age":  float64(7),
+		"Name": nil,
+		"Note": "x",
+	}, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := wrapped{
+		Age:  some(7),
+		Note: sql.NullString{String: "x", Valid: true},
+	}
+
+	if !reflect.DeepEqual(w, want) {
+		t.Errorf("got %#v want %#v", w, want)
+	}
+
+	// the wrapper itself is set as is
+	if err := Fill(map[string]interface{}{"Age": some(3)}, &w); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Age != some(3) {
+		t.Errorf("got %#v", w.Age)
+	}
+
+	err = Fill(map[string]interface{}{"Age": "seven"}, &w)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
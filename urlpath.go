@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	// PathTagName is the tag name which gives the name of the path variable
+	// of a field, such as `path:"user_id"`, for ExpandPath.
+	PathTagName = "path"
+)
+
+// ExpandPath replaces the variables of the URL path template with the values
+// of the fields of s, such as for building the URLs of outbound requests:
+//
+//   type GetOrder struct {
+//       UserID  int    `path:"user_id"`
+//       OrderID string `path:"id"`
+//       File    string `path:"file"`
+//   }
+//
+//   // "/users/42/orders/a%2Fb"
+//   s.ExpandPath("/users/{user_id}/orders/{id}")
+//
+//   // "/files/docs/a%20b.txt" for File "docs/a b.txt"
+//   s.ExpandPath("/files/{file...}")
+//
+// The name of a field's variable is given in its path tag, or else it's the
+// field's key as in Map. Values are formatted as in Env and escaped with
+// url.PathEscape, except that the slashes of variables ending in "...", like
+// in the patterns of http.ServeMux, are kept. It returns an error if a
+// variable has no field or an empty value, if a brace is not closed or if a
+// value is or has a "." or ".." segment, which would change the path.
+func (s *Struct) ExpandPath(template string) (string, error) {
+	var b strings.Builder
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), nil
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated variable in %q", ErrTypeMismatch, template[start:])
+		}
+
+		b.WriteString(template[:start])
+
+		name := template[start+1 : start+end]
+		rest, multi := strings.CutSuffix(name, "...")
+
+		val, err := s.pathValue(rest)
+		if err != nil {
+			return "", err
+		}
+
+		segments := []string{val}
+		if multi {
+			segments = strings.Split(val, "/")
+		}
+
+		for i, seg := range segments {
+			// dot segments would move the path up, such as for an ID of ".."
+			if seg == "." || seg == ".." {
+				return "", fmt.Errorf("%w: {%s} has the segment %q", ErrInvalidPath, name, seg)
+			}
+
+			segments[i] = url.PathEscape(seg)
+		}
+
+		b.WriteString(strings.Join(segments, "/"))
+
+		template = template[start+end+1:]
+	}
+}
+
+// pathValue returns the formatted value of the field of the path variable
+// with the given name.
+func (s *Struct) pathValue(name string) (string, error) {
+	for _, field := range s.structFields() {
+		key, _ := parseTag(field.Tag.Get(PathTagName))
+		if key == "-" {
+			continue
+		}
+
+		if key == "" {
+			key, _ = s.key(field)
+		}
+
+		if key != name {
+			continue
+		}
+
+		str, err := formatEnv(s.value.FieldByName(field.Name))
+		if err != nil {
+			return "", fmt.Errorf("{%s}: %w", name, err)
+		}
+
+		if str == "" {
+			return "", fmt.Errorf("%w: {%s} is empty", ErrRequired, name)
+		}
+
+		return str, nil
+	}
+
+	return "", fmt.Errorf("%w: no field for {%s}", ErrFieldNotFound, name)
+}
+
+// ExpandPath replaces the variables of the URL path template with the values
+// of the fields of s. For more info refer to Struct types ExpandPath()
+// method. It panics if s's kind is not struct.
+func ExpandPath(template string, s interface{}) (string, error) {
+	return New(s).ExpandPath(template)
+}
@@ -0,0 +1,74 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+)
+
+type pathOrder struct {
+	UserID  int    `path:"user_id"`
+	OrderID string `path:"id"`
+	File    string `path:"file"`
+	Kind    string `structs:"kind"`
+	Empty   string
+	Skip    string `path:"-" structs:"skip"`
+}
+
+func TestExpandPath(t *testing.T) {
+	o := pathOrder{UserID: 42, OrderID: "a/b", File: "docs/a b.txt", Kind: "x?y", Skip: "s"}
+
+	tests := map[string]string{
+		"/users/{user_id}/orders/{id}": "/users/42/orders/a%2Fb",
+		"/files/{file...}":             "/files/docs/a%20b.txt",
+		"/kinds/{kind}":                "/kinds/x%3Fy",
+		"/static":                      "/static",
+	}
+
+	for template, want := range tests {
+		got, err := ExpandPath(template, o)
+		if err != nil {
+			t.Errorf("%s: %v", template, err)
+			continue
+		}
+
+		if got != want {
+			t.Errorf("%s: got %q want %q", template, got, want)
+		}
+	}
+}
+
+func TestExpandPath_Errors(t *testing.T) {
+	s := New(pathOrder{})
+
+	tests := map[string]error{
+		"/users/{user_id}/{unknown}": ErrFieldNotFound,
+		"/{Empty}":                   ErrRequired,
+		"/{skip}":                    ErrFieldNotFound,
+		"/{user_id":                  ErrTypeMismatch,
+	}
+
+	for template, want := range tests {
+		if _, err := s.ExpandPath(template); !errors.Is(err, want) {
+			t.Errorf("%s: got %v want %v", template, err, want)
+		}
+	}
+}
+
+func TestExpandPath_DotSegments(t *testing.T) {
+	tests := map[string]pathOrder{
+		"/users/{user_id}/orders/{id}": {UserID: 1, OrderID: ".."},
+		"/orders/{id}":                 {OrderID: "."},
+		"/files/{file...}":             {File: "docs/../../etc/passwd"},
+	}
+
+	for template, o := range tests {
+		if got, err := ExpandPath(template, o); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%s: got %q, %v want ErrInvalidPath", template, got, err)
+		}
+	}
+
+	got, err := ExpandPath("/files/{file...}", pathOrder{File: "a/..b/c."})
+	if err != nil || got != "/files/a/..b/c." {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,240 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	// SkipNested is returned by a WalkFunc to skip the structs nested in
+	// the field it was called for.
+	SkipNested = errors.New("skip nested structs")
+
+	// SkipAll is returned by a WalkFunc to skip all remaining fields. Walk
+	// and Edit return nil then.
+	SkipAll = errors.New("skip all fields")
+)
+
+// WalkFunc is the type of the function called by Walk for each field, with
+// the field's path, such as "Orders[2].Items[\"sku\"].Price".
+type WalkFunc func(path string, f *Field) error
+
+// Walk calls fn for every exported field of s which is not tagged with "-",
+// in the order of the fields, and then for the fields of the structs nested
+// in it. The structs nested in a field are:
+//
+//   - the field's value if it's a struct, or a pointer or interface holding
+//     one, except for structs implementing encoding.TextUnmarshaler such as
+//     time.Time and wrappers such as sql.NullString
+//   - the elements of slices, arrays and maps, which are part of the path
+//     as "[i]" and "[key]", with string keys quoted. Map entries are walked
+//     in the order of their keys.
+//
+// Paths are dotted, such as "DB.Host", and can be passed to GetPath and
+// SetPath. Nil pointers are not followed, and neither are pointers back to
+// a struct which is already being walked. If fn returns SkipNested, the
+// structs nested in the field are skipped. If fn returns SkipAll, Walk stops
+// and returns nil. If fn returns any other error, Walk stops and returns it.
+func (s *Struct) Walk(fn WalkFunc) error {
+	return s.walkAll(walker{fn: fn})
+}
+
+// Edit is the same as Walk, except that fn may change the fields in place
+// with their Set and Zero methods, such as to normalize a struct before it's
+// stored:
+//
+//   err := structs.Edit(&user, func(path string, f *structs.Field) error {
+//       if v, ok := f.Value().(string); ok {
+//           return f.Set(strings.TrimSpace(v))
+//       }
+//       return nil
+//   })
+//
+// The fields of structs in maps and interfaces, which are not addressable,
+// are changed on a copy which is stored back after it was walked. The
+// structs nested in a field are walked after fn returns, so fn may set a
+// field to a new struct to walk. It returns an error if s was not created
+// from a pointer.
+func (s *Struct) Edit(fn WalkFunc) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	return s.walkAll(walker{fn: fn, edit: true})
+}
+
+// walker holds the state of a Walk or Edit call.
+type walker struct {
+	fn WalkFunc
+
+	// edit stores the copies of structs which are not addressable back.
+	edit bool
+}
+
+// walkAll walks s with w and handles SkipAll.
+func (s *Struct) walkAll(w walker) error {
+	s = s.track()
+
+	if err := s.walk("", w); err != nil && err != SkipAll {
+		return err
+	}
+
+	return nil
+}
+
+// walk calls w's fn for the fields of s, with their paths prefixed by
+// prefix.
+func (s *Struct) walk(prefix string, w walker) error {
+	for _, field := range s.structFields() {
+		f := &Field{
+			field:      field,
+			value:      s.value.FieldByIndex(field.Index),
+			defaultTag: s.TagName,
+			layout:     s.fieldLayout(),
+		}
+
+		path := prefix + field.Name
+		if err := w.fn(path, f); err == SkipNested {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if err := s.walkValue(path, f.value, w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkValue walks the structs nested in v, whose path is path.
+func (s *Struct) walkValue(path string, v reflect.Value, w walker) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || !s.enter(v) {
+			return nil
+		}
+		defer s.leave(v)
+
+		return s.walkValue(path, v.Elem(), w)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		if w.edit && v.CanSet() {
+			return s.walkCopy(path, v.Elem(), w, v.Set)
+		}
+
+		return s.walkValue(path, v.Elem(), w)
+	case reflect.Struct:
+		if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+			return nil
+		}
+
+		if _, ok := unwrap(v); ok {
+			return nil
+		}
+
+		if v.CanAddr() {
+			return s.nestedStruct(v.Addr().Interface()).walk(path+".", w)
+		}
+
+		return s.nestedStruct(v.Interface()).walk(path+".", w)
+	case reflect.Slice, reflect.Array:
+		if !walkable(v.Type().Elem()) {
+			return nil
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := s.walkValue(path+"["+strconv.Itoa(i)+"]", v.Index(i), w); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !walkable(v.Type().Elem()) {
+			return nil
+		}
+
+		for _, k := range sortedKeys(v) {
+			elem, path := v.MapIndex(k), path+"["+indexKey(k)+"]"
+
+			var err error
+			if w.edit {
+				err = s.walkCopy(path, elem, w, func(c reflect.Value) { v.SetMapIndex(k, c) })
+			} else {
+				err = s.walkValue(path, elem, w)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkCopy walks an addressable copy of v and stores it back with set, even
+// if fn returned an error, so the changes made so far are kept.
+func (s *Struct) walkCopy(path string, v reflect.Value, w walker, set func(reflect.Value)) error {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+
+	err := s.walkValue(path, c, w)
+	set(c)
+	return err
+}
+
+// walkable returns true if values of type t may hold structs to walk.
+func walkable(t reflect.Type) bool {
+	switch indirectType(t).Kind() {
+	case reflect.Struct, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+// sortedKeys returns the keys of the map v, sorted by their string form.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	return keys
+}
+
+// indexKey returns the map key k as it appears in a path, quoted if it's a
+// string.
+func indexKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return strconv.Quote(k.String())
+	}
+
+	return fmt.Sprint(k.Interface())
+}
+
+// Walk calls fn for every field of the given struct and of the structs
+// nested in it. For more info refer to Struct types Walk() method. It panics
+// if s's kind is not struct.
+func Walk(s interface{}, fn WalkFunc) error {
+	return New(s).Walk(fn)
+}
+
+// Edit calls fn for every field of the struct s points to and of the structs
+// nested in it, which fn may change in place. For more info refer to Struct
+// types Edit() method. It returns an error if s is not a pointer to struct.
+func Edit(s interface{}, fn WalkFunc) error {
+	st, err := structPtr(s)
+	if err != nil {
+		return err
+	}
+
+	return st.Edit(fn)
+}
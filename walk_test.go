@@ -0,0 +1,183 @@
+package structs
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type walkItem struct {
+	Price float64
+}
+
+type walkOrder struct {
+	ID    int
+	Items map[string]walkItem
+}
+
+type walkNode struct {
+	Name   string
+	Parent *walkNode
+}
+
+type WalkBase struct {
+	Version int
+}
+
+type walkTarget struct {
+	WalkBase
+	Name    string
+	secret  string
+	Ignored string `structs:"-"`
+	Created time.Time
+	Note    sql.NullString
+	Orders  []*walkOrder
+	Any     interface{}
+	Node    *walkNode
+	Tags    []string
+}
+
+func TestWalk(t *testing.T) {
+	node := &walkNode{Name: "child"}
+	node.Parent = node
+
+	s := &walkTarget{
+		Name: "a",
+		Orders: []*walkOrder{
+			{ID: 1, Items: map[string]walkItem{"b": {2}, "a": {1}}},
+			nil,
+		},
+		Any:  walkItem{3},
+		Node: node,
+		Tags: []string{"x"},
+	}
+
+	var paths []string
+	err := Walk(s, func(path string, f *Field) error {
+		paths = append(paths, path)
+
+		if path == `Orders[0].Items["b"].Price` && f.Value() != 2.0 {
+			t.Errorf("%s: got %v", path, f.Value())
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"WalkBase",
+		"WalkBase.Version",
+		"Name",
+		"Created",
+		"Note",
+		"Orders",
+		"Orders[0].ID",
+		"Orders[0].Items",
+		`Orders[0].Items["a"].Price`,
+		`Orders[0].Items["b"].Price`,
+		"Any",
+		"Any.Price",
+		"Node",
+		"Node.Name",
+		"Node.Parent",
+		"Tags",
+	}
+
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got  %q\nwant %q", paths, want)
+	}
+}
+
+func TestWalk_Error(t *testing.T) {
+	errStop := errors.New("stop")
+
+	var paths []string
+	err := Walk(walkTarget{}, func(path string, f *Field) error {
+		paths = append(paths, path)
+		if f.Name() == "Version" {
+			return errStop
+		}
+
+		return nil
+	})
+
+	if err != errStop || len(paths) != 2 {
+		t.Errorf("got %v after %q", err, paths)
+	}
+}
+
+func TestWalk_Skip(t *testing.T) {
+	s := walkTarget{Node: &walkNode{Name: "a"}, Any: walkItem{1}}
+
+	var paths []string
+	err := Walk(s, func(path string, f *Field) error {
+		paths = append(paths, path)
+
+		switch path {
+		case "WalkBase", "Any":
+			return SkipNested
+		case "Node.Name":
+			return SkipAll
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"WalkBase", "Name", "Created", "Note", "Orders", "Any", "Node", "Node.Name"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got  %q\nwant %q", paths, want)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	s := &walkTarget{
+		Name:   " a ",
+		Orders: []*walkOrder{{Items: map[string]walkItem{"a": {-1}, "b": {2}}}},
+		Any:    walkItem{-3},
+	}
+
+	err := Edit(s, func(path string, f *Field) error {
+		switch v := f.Value().(type) {
+		case string:
+			return f.Set(strings.TrimSpace(v))
+		case float64:
+			if v < 0 {
+				return f.Zero()
+			}
+		case *walkNode:
+			// set fields are walked after fn returns
+			if path == "Node" {
+				return f.Set(&walkNode{Name: " b "})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Name != "a" || s.Node.Name != "b" {
+		t.Errorf("got %q and %q", s.Name, s.Node.Name)
+	}
+
+	if items := s.Orders[0].Items; items["a"].Price != 0 || items["b"].Price != 2 {
+		t.Errorf("got %v", items)
+	}
+
+	if s.Any != (walkItem{0}) {
+		t.Errorf("got %v", s.Any)
+	}
+
+	if err := Edit(walkTarget{}, func(string, *Field) error { return nil }); err == nil {
+		t.Error("expected an error for a struct which is not a pointer")
+	}
+}
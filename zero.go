@@ -0,0 +1,247 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Zero sets every exported field of s back to its zero value, such as for
+// objects reused with a sync.Pool. Nested structs are reset recursively, so
+// their fields tagged with "-" and their unexported fields are kept, just
+// like the ones of s. Nested structs without such fields, such as
+// time.Time, are reset as a whole. Pointers, slices and maps are set to
+// nil. It returns an error if s was not created from a pointer.
+func (s *Struct) Zero() error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	zeroStruct(s.value, s.TagName)
+	return nil
+}
+
+// zeroStruct sets the exported fields of the struct v to their zero value,
+// except the fields tagged with "-".
+func zeroStruct(v reflect.Value, tagName string) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Tag.Get(tagName) == "-" {
+			continue
+		}
+
+		// structs without fields to reset, such as time.Time, are reset as
+		// a whole
+		if field.Type.Kind() == reflect.Struct && hasFields(field.Type, tagName) {
+			zeroStruct(v.Field(i), tagName)
+			continue
+		}
+
+		v.Field(i).Set(reflect.Zero(field.Type))
+	}
+}
+
+// hasFields returns true if the struct type t has exported fields which are
+// not tagged with "-" for the given tag name.
+func hasFields(t reflect.Type, tagName string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath == "" && field.Tag.Get(tagName) != "-" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ZeroFields sets the fields with the given names back to their zero value,
+// such as clearing secrets before a struct is logged. Fields of nested
+// structs are given by their path, i.e. "Session.Token". Fields behind nil
+// pointers are already cleared and left as is. It returns an error wrapping
+// ErrFieldNotFound or ErrNotExported for invalid names, in which case no
+// field is changed. It returns an error if s was not created from a pointer.
+func (s *Struct) ZeroFields(names ...string) error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	var fields []reflect.Value
+
+	for _, name := range names {
+		v, ok, err := fieldByPath(s.value, name, false)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			fields = append(fields, v)
+		}
+	}
+
+	for _, v := range fields {
+		v.Set(reflect.Zero(v.Type()))
+	}
+
+	return nil
+}
+
+// fieldByPath returns the field of the struct v with the given dot separated
+// path. If alloc is true, nil pointers along the path are allocated,
+// otherwise the boolean is false if the field is behind a nil pointer.
+func fieldByPath(v reflect.Value, path string, alloc bool) (reflect.Value, bool, error) {
+	// deref returns the value v points to, allocating it if needed
+	deref := func(v reflect.Value) (reflect.Value, bool, error) {
+		if v.IsNil() {
+			if !alloc {
+				return reflect.Value{}, false, nil
+			}
+
+			if !v.CanSet() {
+				return reflect.Value{}, false, fmt.Errorf("%w: %s", ErrNotSettable, path)
+			}
+
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return v.Elem(), true, nil
+	}
+
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() == reflect.Ptr {
+			var ok bool
+			var err error
+			if v, ok, err = deref(v); !ok {
+				return v, false, err
+			}
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false, fmt.Errorf("%w: %s", ErrFieldNotFound, path)
+		}
+
+		field, ok := v.Type().FieldByName(name)
+		if !ok {
+			return reflect.Value{}, false, fmt.Errorf("%w: %s", ErrFieldNotFound, path)
+		}
+
+		if field.PkgPath != "" {
+			return reflect.Value{}, false, fmt.Errorf("%w: %s", ErrNotExported, path)
+		}
+
+		// promoted fields of embedded pointers
+		for i, x := range field.Index {
+			if i > 0 && v.Kind() == reflect.Ptr {
+				var err error
+				if v, ok, err = deref(v); !ok {
+					return v, false, err
+				}
+			}
+
+			v = v.Field(x)
+		}
+	}
+
+	return v, true, nil
+}
+
+// ClearTransient sets all fields tagged with the "transient" option back to
+// their zero value, so runtime only fields such as caches or computed values
+// are stripped before s is stored or transmitted. Example:
+//
+//   // Field is cleared by ClearTransient.
+//   Field map[string]int `structs:",transient"`
+//
+// Nested structs, including the ones behind pointers and in slices and
+// arrays, are cleared recursively. It returns an error if s was not created
+// from a pointer.
+func (s *Struct) ClearTransient() error {
+	if !s.value.CanAddr() {
+		return errNotStructPtr
+	}
+
+	clearTransient(s.value, s.TagName, make(map[visit]bool))
+	return nil
+}
+
+// clearTransient clears the transient fields of all structs found in v.
+// Pointers already cleared are recorded in seen to stop at pointer cycles.
+func clearTransient(v reflect.Value, tagName string, seen map[visit]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+
+		key := visit{ptr: v.Pointer(), typ: v.Type()}
+		if seen[key] {
+			return
+		}
+
+		seen[key] = true
+		clearTransient(v.Elem(), tagName, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			clearTransient(v.Index(i), tagName, seen)
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			tag := field.Tag.Get(tagName)
+			if tag == "-" {
+				continue
+			}
+
+			if _, tagOpts := parseTag(tag); tagOpts.Has("transient") {
+				v.Field(i).Set(reflect.Zero(field.Type))
+				continue
+			}
+
+			clearTransient(v.Field(i), tagName, seen)
+		}
+	}
+}
+
+// Zero sets every exported field of the struct pointed to by dst back to its
+// zero value. For more info refer to Struct types Zero() method. It returns
+// an error if dst is not a pointer to struct.
+func Zero(dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.Zero()
+}
+
+// ZeroFields sets the fields of the struct pointed to by dst with the given
+// names back to their zero value. For more info refer to Struct types
+// ZeroFields() method. It returns an error if dst is not a pointer to struct.
+func ZeroFields(dst interface{}, names ...string) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.ZeroFields(names...)
+}
+
+// ClearTransient sets all fields of the struct pointed to by dst tagged with
+// the "transient" option back to their zero value. For more info refer to
+// Struct types ClearTransient() method. It returns an error if dst is not a
+// pointer to struct.
+func ClearTransient(dst interface{}) error {
+	s, err := structPtr(dst)
+	if err != nil {
+		return err
+	}
+
+	return s.ClearTransient()
+}
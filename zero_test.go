@@ -0,0 +1,155 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type zeroInner struct {
+	Count int
+	Keep  string `structs:"-"`
+}
+
+type zeroOuter struct {
+	Name   string
+	Tags   []string
+	Inner  zeroInner
+	Ptr    *zeroInner
+	ID     int `structs:"-"`
+	hidden int
+}
+
+func TestZero(t *testing.T) {
+	z := zeroOuter{
+		Name:   "a",
+		Tags:   []string{"x"},
+		Inner:  zeroInner{Count: 1, Keep: "inner"},
+		Ptr:    &zeroInner{Count: 2},
+		ID:     42,
+		hidden: 7,
+	}
+
+	if err := Zero(&z); err != nil {
+		t.Fatal(err)
+	}
+
+	want := zeroOuter{
+		Inner:  zeroInner{Keep: "inner"},
+		ID:     42,
+		hidden: 7,
+	}
+
+	if z.Name != want.Name || z.Tags != nil || z.Inner != want.Inner || z.Ptr != nil || z.ID != want.ID || z.hidden != want.hidden {
+		t.Errorf("got %+v want %+v", z, want)
+	}
+
+	if err := Zero(z); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct for a non pointer, got %v", err)
+	}
+}
+
+func TestZero_Opaque(t *testing.T) {
+	type Event struct {
+		At      time.Time
+		Expires *time.Time
+	}
+
+	now := time.Now()
+	e := Event{At: now, Expires: &now}
+
+	if err := Zero(&e); err != nil {
+		t.Fatal(err)
+	}
+
+	if !e.At.IsZero() || e.Expires != nil {
+		t.Errorf("got %+v, want zero values", e)
+	}
+}
+
+type zeroSession struct {
+	Token string
+	User  string
+}
+
+type ZeroEmbedded struct {
+	A string
+}
+
+type zeroRequest struct {
+	Password string
+	Session  zeroSession
+	Previous *zeroSession
+	ZeroEmbedded
+	private string
+}
+
+func TestZeroFields(t *testing.T) {
+	r := zeroRequest{
+		Password:     "hunter2",
+		Session:      zeroSession{Token: "t", User: "u"},
+		ZeroEmbedded: ZeroEmbedded{A: "a"},
+	}
+
+	if err := ZeroFields(&r, "Password", "Session.Token", "Previous.Token", "A"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := zeroRequest{Session: zeroSession{User: "u"}}
+	if r != want {
+		t.Errorf("got %+v want %+v", r, want)
+	}
+
+	r.Password = "hunter2"
+
+	tests := map[string]error{
+		"Session.Missing": ErrFieldNotFound,
+		"Password.Length": ErrFieldNotFound,
+		"private":         ErrNotExported,
+	}
+
+	for name, want := range tests {
+		if err := ZeroFields(&r, "Password", name); !errors.Is(err, want) {
+			t.Errorf("%s: got %v want %v", name, err, want)
+		}
+
+		if r.Password != "hunter2" {
+			t.Errorf("%s: no field should be changed on error", name)
+		}
+	}
+}
+
+type transientItem struct {
+	ID    int
+	Score float64 `structs:",transient"`
+}
+
+type transientOrder struct {
+	Items   []transientItem
+	Primary *transientItem
+	Cache   map[string]int `structs:"cache,transient"`
+	Total   int
+	Parent  *transientOrder
+}
+
+func TestClearTransient(t *testing.T) {
+	o := transientOrder{
+		Items:   []transientItem{{1, 0.5}, {2, 0.7}},
+		Primary: &transientItem{3, 0.9},
+		Cache:   map[string]int{"a": 1},
+		Total:   10,
+	}
+	o.Parent = &o
+
+	if err := ClearTransient(&o); err != nil {
+		t.Fatal(err)
+	}
+
+	if o.Cache != nil || o.Items[0].Score != 0 || o.Items[1].Score != 0 || o.Primary.Score != 0 {
+		t.Errorf("transient fields should be cleared: %+v", o)
+	}
+
+	if o.Total != 10 || o.Items[1].ID != 2 || o.Primary.ID != 3 {
+		t.Errorf("other fields should be kept: %+v", o)
+	}
+}